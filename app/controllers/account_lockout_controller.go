@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+
+	gomail "github.com/studio-senkou/lentera-cendekia-be/utils/mail"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /users/:id/unlock
+// ─────────────────────────────────────────────────────────────────────────────
+
+// UnlockUser lets an admin lift a login lockout before it expires. It also
+// clears the login, reset password and verify email throttles on the user's
+// email.
+func (uc *UserController) UnlockUser(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid user ID",
+		})
+	}
+
+	user, err := uc.userRepo.GetByID(uint(userID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve user",
+			"error":   err.Error(),
+		})
+	}
+	if user == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	}
+
+	if err := uc.lockout.Unlock(c.Context(), user.Email); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to unlock user",
+			"error":   err.Error(),
+		})
+	}
+
+	log.Printf("[AUTH] Admin %v unlocked login of user %d", c.Locals("userID"), user.ID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "User has been unlocked",
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// recordLoginFailure menghitung password salah untuk email tersebut, juga untuk
+// email yang tidak terdaftar agar penguncian tidak membocorkan akun mana yang
+// ada. Pemilik akun diberi tahu lewat email saat akunnya terkunci.
+func (ac *AuthController) recordLoginFailure(c *fiber.Ctx, email string, user *models.User) {
+	locked, err := ac.lockout.Fail(c.Context(), email)
+	if err != nil {
+		log.Printf("[AUTH] Failed to record failed login: %v", err)
+		return
+	}
+	if !locked || user == nil {
+		return
+	}
+
+	log.Printf("[AUTH] Locked login of user %d after %d failed attempts", user.ID, ac.lockout.Threshold)
+
+	mail, err := gomail.NewMailFromTemplate(
+		user.Email,
+		"Akun Lentera Cendekia Dikunci Sementara",
+		"templates/emails/account_locked.html",
+		fiber.Map{
+			"Name":        user.Name,
+			"Attempts":    ac.lockout.Threshold,
+			"LockedUntil": time.Now().Add(ac.lockout.Duration).Format(time.RFC1123),
+			"IPAddress":   c.IP(),
+		},
+	)
+	if err != nil {
+		log.Printf("[AUTH] Failed to build lockout email for user %d: %v", user.ID, err)
+		return
+	}
+	if err := mail.Send(); err != nil {
+		log.Printf("[AUTH] Failed to send lockout email to user %d: %v", user.ID, err)
+	}
+}
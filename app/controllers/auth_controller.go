@@ -1,35 +1,49 @@
 package controllers
 
 import (
+	"database/sql"
 	"fmt"
+	"log"
+	"math"
 	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/studio-senkou/lentera-cendekia-be/app/models"
 	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
 	"github.com/studio-senkou/lentera-cendekia-be/database"
 	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
 	"github.com/studio-senkou/lentera-cendekia-be/utils/auth"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/oidc"
 	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
 
 	gomail "github.com/studio-senkou/lentera-cendekia-be/utils/mail"
 )
 
 type AuthController struct {
-	jwtManager *auth.JwtManager
-	userRepo   *models.UserRepository
-	authRepo   *models.AuthenticationRepository
+	jwtManager     *auth.JwtManager
+	lockout        *auth.AccountLockout
+	mfaLimiter     *auth.Limiter
+	userRepo       *models.UserRepository
+	authRepo       *models.AuthenticationRepository
+	settingRepo    *models.AppSettingRepository
+	permissionRepo *models.PermissionRepository
+	oidcProviders  map[string]*oidc.Provider
 }
 
 func NewAuthController() *AuthController {
 	db := database.GetDB()
-	authSecret := app.GetEnv("AUTH_SECRET", "")
 
 	return &AuthController{
-		jwtManager: auth.NewJwtManager(authSecret),
-		userRepo:   models.NewUserRepository(db),
-		authRepo:   models.NewAuthenticationRepository(db),
+		jwtManager:     auth.GetJwtManager(),
+		lockout:        auth.NewAccountLockout(),
+		mfaLimiter:     auth.NewLimiter("mfa"),
+		userRepo:       models.NewUserRepository(db),
+		authRepo:       models.NewAuthenticationRepository(db),
+		settingRepo:    models.NewAppSettingRepository(db),
+		permissionRepo: models.NewPermissionRepository(db),
+		oidcProviders:  loadOIDCProviders(),
 	}
 }
 
@@ -57,22 +71,45 @@ func (ac *AuthController) Login(c *fiber.Ctx, isAdmin bool) error {
 		})
 	}
 
-	user, err := ac.userRepo.GetByEmail(loginRequest.Email)
-	if user != nil && user.Role != "admin" && isAdmin {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+	if lockedFor, err := ac.lockout.LockedFor(c.Context(), loginRequest.Email); err != nil {
+		log.Printf("[AUTH] Failed to check account lockout: %v", err)
+	} else if lockedFor > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(lockedFor.Seconds()))))
+		return c.Status(fiber.StatusLocked).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Access denied",
-			"error":   "You do not have permission to access this resource",
+			"message": "Account temporarily locked",
+			"error":   "Too many failed login attempts, please try again later",
 		})
 	}
 
+	user, err := ac.userRepo.GetByEmail(loginRequest.Email)
+	if user != nil && isAdmin {
+		allowed, err := ac.permissionRepo.HasPermission(c.Context(), user.ID, models.PermAdminPanel)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Login failed",
+				"error":   err.Error(),
+			})
+		}
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Access denied",
+				"error":   "You do not have permission to access this resource",
+			})
+		}
+	}
+
 	if err != nil || user == nil {
+		ac.recordLoginFailure(c, loginRequest.Email, nil)
 		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Login failed",
 			"error":   "Invalid email or password",
 		})
 	} else if !user.CheckPassword(loginRequest.Password) {
+		ac.recordLoginFailure(c, loginRequest.Email, user)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Login failed",
@@ -88,37 +125,32 @@ func (ac *AuthController) Login(c *fiber.Ctx, isAdmin bool) error {
 		})
 	}
 
-	accessToken, err := ac.jwtManager.GenerateToken(auth.Payload{
-		UserID: user.ID,
-		Role:   user.Role,
-	}, time.Now().Add(24*time.Hour))
+	if err := ac.lockout.Reset(c.Context(), loginRequest.Email); err != nil {
+		log.Printf("[AUTH] Failed to reset failed logins of user %d: %v", user.ID, err)
+	}
+
+	// Pengguna dengan 2FA mendapat token login tertunda untuk langkah kedua.
+	challenge, err := ac.mfaChallenge(c.Context(), user)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Cannot generate access token",
+			"message": "Cannot check two-factor authentication",
 			"error":   err.Error(),
 		})
 	}
-
-	refreshToken, err2 := ac.jwtManager.GenerateToken(auth.Payload{
-		UserID: user.ID,
-		Role:   user.Role,
-	}, time.Now().Add(30*24*time.Hour))
-	if err2 != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  "fail",
-			"message": "Cannot generate refresh token",
-			"error":   err2.Error(),
+	if challenge != nil {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"status":  "success",
+			"message": "Two-factor authentication required",
+			"data":    challenge,
 		})
 	}
 
-	if err := ac.authRepo.UpdateOrCreate(&models.UserHasToken{
-		UserID: user.ID,
-		Token:  refreshToken.Token,
-	}); err != nil {
+	tokens, err := startSession(c, ac.jwtManager, ac.authRepo, user, loginRequest.DeviceName)
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Failed to save authentication token",
+			"message": "Cannot generate authentication tokens",
 			"error":   err.Error(),
 		})
 	}
@@ -126,13 +158,7 @@ func (ac *AuthController) Login(c *fiber.Ctx, isAdmin bool) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Login successful",
-		"data": fiber.Map{
-			"active_role":          user.Role,
-			"access_token":         accessToken.Token,
-			"access_token_expiry":  accessToken.ExpiresAt,
-			"refresh_token":        refreshToken.Token,
-			"refresh_token_expiry": refreshToken.ExpiresAt,
-		},
+		"data":    tokens,
 	})
 }
 
@@ -226,83 +252,64 @@ func (ac *AuthController) RefreshToken(c *fiber.Ctx) error {
 		})
 	}
 
-	claims, err := ac.jwtManager.ValidateToken(refreshTokenRequest.Token)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  "fail",
-			"message": "Invalid refresh token",
-			"error":   err.Error(),
-		})
-	}
-
-	payloadMap, ok := claims["payload"].(map[string]any)
-	if !ok {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  "fail",
-			"message": "Invalid token payload format",
-		})
-	}
-
-	var parsedUserID int
-	if id, ok := payloadMap["user_id"].(float64); ok {
-		parsedUserID = int(id)
-	} else {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"status":  "fail",
-			"message": "Invalid user ID in token",
-			"error":   "Invalid user ID",
-		})
-	}
-
-	userIDStr := fmt.Sprintf("%v", parsedUserID)
-	userID, err := strconv.ParseInt(userIDStr, 10, 32)
+	// Refresh token dirotasi setiap dipakai. Token lama yang dipakai ulang berarti
+	// token tersebut bocor, jadi seluruh sesi (login di perangkat itu) dicabut.
+	var tokens fiber.Map
+	var reused bool
+	err := database.DB.Transaction(func(tx *sql.Tx) error {
+		authRepo := ac.authRepo.WithExecutor(tx)
+
+		token, err := authRepo.GetRefreshTokenForUpdate(auth.HashRefreshToken(refreshTokenRequest.Token))
+		if err != nil {
+			return err
+		}
+		if token == nil || token.RevokedAt != nil || token.ExpiresAt.Before(time.Now()) {
+			return models.ErrRefreshTokenInvalid
+		}
+		if token.UsedAt != nil {
+			log.Printf("[AUTH] Refresh token reuse detected for user %d, revoking session %s", token.UserID, token.SessionID)
+			reused = true
+			_, err := authRepo.RevokeSession(token.UserID, token.SessionID)
+			return err
+		}
+
+		user, err := ac.userRepo.GetByID(token.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil || !user.IsActive {
+			return models.ErrRefreshTokenInvalid
+		}
+
+		if err := authRepo.MarkRefreshTokenUsed(token.ID); err != nil {
+			return err
+		}
+		tokens, err = issueTokens(ac.jwtManager, authRepo, user, token.SessionID, &token.ID)
+		if err != nil {
+			return err
+		}
+		ipAddress, userAgent := clientInfo(c)
+		return authRepo.ExtendSession(token.SessionID, time.Now().Add(auth.RefreshTokenTTL()), ipAddress, userAgent)
+	})
 
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+	if err == nil && reused {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Invalid refresh token",
-			"error":   "Invalid user ID in token",
+			"error":   "Refresh token was already used; this session has been revoked, please login again",
 		})
 	}
-
-	if ok, err := ac.authRepo.ValidateSessionExist(int(userID), refreshTokenRequest.Token); err != nil || !ok {
+	if err == models.ErrRefreshTokenInvalid {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Invalid refresh token",
 			"error":   "Session does not exist or has been invalidated",
 		})
 	}
-
-	newPayload := auth.Payload{
-		UserID: uint(userID),
-		Role:   payloadMap["role"].(string),
-	}
-
-	newAccessToken, err := ac.jwtManager.GenerateToken(newPayload, time.Now().Add(24*time.Hour))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Cannot generate new access token",
-			"error":   err.Error(),
-		})
-	}
-
-	newRefreshToken, err2 := ac.jwtManager.GenerateToken(newPayload, time.Now().Add(30*24*time.Hour))
-	if err2 != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  "fail",
-			"message": "Cannot generate new refresh token",
-			"error":   err2.Error(),
-		})
-	}
-
-	if err := ac.authRepo.UpdateOrCreate(&models.UserHasToken{
-		UserID: uint(userID),
-		Token:  newRefreshToken.Token,
-	}); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  "fail",
-			"message": "Failed to save new authentication token",
+			"message": "Failed to refresh token",
 			"error":   err.Error(),
 		})
 	}
@@ -310,29 +317,22 @@ func (ac *AuthController) RefreshToken(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Successfully refreshed token",
-		"data": fiber.Map{
-			"active_role":          newPayload.Role,
-			"access_token":         newAccessToken.Token,
-			"access_token_expiry":  newAccessToken.ExpiresAt,
-			"refresh_token":        newRefreshToken.Token,
-			"refresh_token_expiry": newRefreshToken.ExpiresAt,
-		},
+		"data":    tokens,
 	})
 }
 
 func (ac *AuthController) Logout(c *fiber.Ctx) error {
-	userIDStr := fmt.Sprintf("%v", c.Locals("userID"))
-	userID, err := strconv.ParseInt(userIDStr, 10, 32)
-
-	if err != nil {
+	userID := uint(c.Locals("userID").(int))
+	sessionID, ok := c.Locals("sessionID").(string)
+	if !ok || sessionID == "" {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Unable to log out",
-			"error":   "Invalid user ID",
+			"error":   "Invalid session",
 		})
 	}
 
-	if err := ac.authRepo.InvalidateToken(int(userID)); err != nil {
+	if _, err := ac.authRepo.RevokeSession(userID, sessionID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "fail",
 			"message": "Unable to log out",
@@ -346,6 +346,14 @@ func (ac *AuthController) Logout(c *fiber.Ctx) error {
 	})
 }
 
+// JWKS publishes the public keys of asymmetric signing keys so other
+// services can verify access tokens. The body is a plain JWK set rather than
+// the usual envelope, since JWT libraries read it as is.
+func (ac *AuthController) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(ac.jwtManager.JWKS())
+}
+
 func (ac *AuthController) ResetPasswordRequest(c *fiber.Ctx) error {
 	requestPasswordRequest := new(requests.ResetPasswordRequest)
 	if validationError, err := validator.ValidateRequest(c, requestPasswordRequest); err != nil {
@@ -410,3 +418,82 @@ func (ac *AuthController) ResetPasswordRequest(c *fiber.Ctx) error {
 		"message": "Reset password request is valid",
 	})
 }
+
+// startSession membuka sesi baru untuk perangkat yang sedang login beserta
+// token pertamanya.
+func startSession(c *fiber.Ctx, jwtManager *auth.JwtManager, authRepo *models.AuthenticationRepository, user *models.User, deviceName string) (fiber.Map, error) {
+	session := &models.UserSession{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(auth.RefreshTokenTTL()),
+	}
+	if deviceName != "" {
+		session.DeviceName = &deviceName
+	}
+	session.IPAddress, session.UserAgent = clientInfo(c)
+
+	var tokens fiber.Map
+	err := database.DB.Transaction(func(tx *sql.Tx) error {
+		txRepo := authRepo.WithExecutor(tx)
+		if err := txRepo.CreateSession(session); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+
+		var err error
+		tokens, err = issueTokens(jwtManager, txRepo, user, session.ID, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens["session_id"] = session.ID
+	return tokens, nil
+}
+
+// issueTokens membuat access token JWT berumur pendek dan refresh token opaque
+// baru di sesi yang sama. Hanya hash refresh token yang disimpan.
+func issueTokens(jwtManager *auth.JwtManager, authRepo *models.AuthenticationRepository, user *models.User, sessionID string, parentID *uint) (fiber.Map, error) {
+	accessToken, err := jwtManager.GenerateToken(auth.Payload{
+		UserID:    user.ID,
+		Role:      user.Role,
+		SessionID: sessionID,
+	}, time.Now().Add(auth.AccessTokenTTL()))
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshTokenHash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	stored := &models.RefreshToken{
+		SessionID: sessionID,
+		UserID:    user.ID,
+		TokenHash: refreshTokenHash,
+		ParentID:  parentID,
+		ExpiresAt: time.Now().Add(auth.RefreshTokenTTL()),
+	}
+	if err := authRepo.CreateRefreshToken(stored); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return fiber.Map{
+		"active_role":          user.Role,
+		"access_token":         accessToken.Token,
+		"access_token_expiry":  accessToken.ExpiresAt,
+		"refresh_token":        refreshToken,
+		"refresh_token_expiry": stored.ExpiresAt,
+	}, nil
+}
+
+// clientInfo mengambil IP dan User-Agent request untuk dicatat di sesi.
+func clientInfo(c *fiber.Ctx) (ipAddress, userAgent *string) {
+	if ip := c.IP(); ip != "" {
+		ipAddress = &ip
+	}
+	if ua := c.Get(fiber.HeaderUserAgent); ua != "" {
+		userAgent = &ua
+	}
+	return ipAddress, userAgent
+}
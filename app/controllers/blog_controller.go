@@ -13,13 +13,17 @@ import (
 
 type BlogController struct {
 	blogRepository *models.BlogRepository
+	permissionRepo *models.PermissionRepository
 }
 
 func NewBlogController() *BlogController {
 	db := database.GetDB()
 	blogRepository := models.NewBlogRepository(db)
 
-	return &BlogController{blogRepository: blogRepository}
+	return &BlogController{
+		blogRepository: blogRepository,
+		permissionRepo: models.NewPermissionRepository(db),
+	}
 }
 
 func (bc *BlogController) CreateBlog(c *fiber.Ctx) error {
@@ -126,6 +130,10 @@ func (bc *BlogController) UpdateBlog(c *fiber.Ctx) error {
 		})
 	}
 
+	if allowed, err := bc.authorizeAuthor(c, id); !allowed {
+		return err
+	}
+
 	updateData := &models.Blog{
 		ID:      id,
 		Title:   updateBlogRequest.Title,
@@ -154,6 +162,10 @@ func (bc *BlogController) DeleteBlog(c *fiber.Ctx) error {
 		})
 	}
 
+	if allowed, err := bc.authorizeAuthor(c, id); !allowed {
+		return err
+	}
+
 	if err := bc.blogRepository.Delete(id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "Failed to delete blog",
@@ -166,3 +178,33 @@ func (bc *BlogController) DeleteBlog(c *fiber.Ctx) error {
 		"message": "Blog deleted successfully",
 	})
 }
+
+// authorizeAuthor melaporkan apakah pengguna adalah penulis blog atau boleh
+// mengelola blog milik siapa pun. Jika tidak, respons 404 atau 403 sudah
+// ditulis dan handler cukup mengembalikan error-nya.
+func (bc *BlogController) authorizeAuthor(c *fiber.Ctx, blogID int) (bool, error) {
+	userID, canManageAll, err := ownershipScope(c, bc.permissionRepo, models.PermBlogManageAll)
+	if err != nil {
+		return false, permissionCheckError(c)
+	}
+	if canManageAll {
+		return true, nil
+	}
+
+	blog, err := bc.blogRepository.GetByID(blogID)
+	if err != nil {
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Failed to retrieve blog",
+			"error":   err.Error(),
+		})
+	}
+	if blog == nil {
+		return false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "Blog not found",
+		})
+	}
+	if uint(blog.AuthorID) != userID {
+		return false, forbidden(c)
+	}
+	return true, nil
+}
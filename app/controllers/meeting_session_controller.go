@@ -14,6 +14,7 @@ import (
 type MeetingSessionController struct {
 	meetingSessionRepo *models.MeetingSessionRepository
 	studentPlanRepo    *models.StudentPlanRepository
+	permissionRepo     *models.PermissionRepository
 }
 
 func NewMeetingSessionController() *MeetingSessionController {
@@ -22,6 +23,7 @@ func NewMeetingSessionController() *MeetingSessionController {
 	return &MeetingSessionController{
 		meetingSessionRepo: models.NewMeetingSessionRepository(db),
 		studentPlanRepo:    models.NewStudentPlanRepository(db),
+		permissionRepo:     models.NewPermissionRepository(db),
 	}
 }
 
@@ -42,6 +44,14 @@ func (mc *MeetingSessionController) CreateMeetingSession(c *fiber.Ctx) error {
 		})
 	}
 
+	userID, canManageAll, err := ownershipScope(c, mc.permissionRepo, models.PermSessionManageAll)
+	if err != nil {
+		return permissionCheckError(c)
+	}
+	if !canManageAll && createMeetingSessionRequest.MentorID != userID {
+		return forbidden(c)
+	}
+
 	sessionDate, err := datetime.ParseDateOnly(createMeetingSessionRequest.Date)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -111,9 +121,18 @@ func (mc *MeetingSessionController) BulkCreateMeetingSessions(c *fiber.Ctx) erro
 		})
 	}
 
+	userID, canManageAll, err := ownershipScope(c, mc.permissionRepo, models.PermSessionManageAll)
+	if err != nil {
+		return permissionCheckError(c)
+	}
+
 	sessions := make([]*models.MeetingSession, len(bulkCreateMeetingSessions.Sessions))
 
 	for i, session := range bulkCreateMeetingSessions.Sessions {
+		if !canManageAll && session.MentorID != userID {
+			return forbidden(c)
+		}
+
 		sessionDate, err := datetime.ParseDateOnly(session.Date)
 		if err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -169,7 +188,17 @@ func (mc *MeetingSessionController) GetMeetingSessions(c *fiber.Ctx) error {
 		})
 	}
 
-	meetingSessions, err := mc.meetingSessionRepo.GetAll(uint(user))
+	// Mentor hanya melihat sesinya sendiri, sama seperti pada GET /:id.
+	userID, canManageAll, err := ownershipScope(c, mc.permissionRepo, models.PermSessionManageAll)
+	if err != nil {
+		return permissionCheckError(c)
+	}
+	mentorID := userID
+	if canManageAll {
+		mentorID = 0
+	}
+
+	meetingSessions, err := mc.meetingSessionRepo.GetAll(uint(user), mentorID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "fail",
@@ -248,6 +277,26 @@ func (mc *MeetingSessionController) GetMeetingSessionByID(c *fiber.Ctx) error {
 		})
 	}
 
+	userID, canManageAll, err := ownershipScope(c, mc.permissionRepo, models.PermSessionManageAll)
+	if err != nil {
+		return permissionCheckError(c)
+	}
+	if !canManageAll {
+		owners, err := mc.meetingSessionRepo.GetOwners([]uint{meetingSession.ID})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Failed to retrieve meeting session",
+				"error":   err.Error(),
+			})
+		}
+		owner := owners[meetingSession.ID]
+		isStudent := owner.StudentUserID != nil && *owner.StudentUserID == userID
+		if owner.MentorID != userID && !isStudent {
+			return forbidden(c)
+		}
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Meeting session retrieved successfully",
@@ -292,6 +341,41 @@ func (mc *MeetingSessionController) UpdateMeetingSession(c *fiber.Ctx) error {
 		})
 	}
 
+	userID, canManageAll, err := ownershipScope(c, mc.permissionRepo, models.PermSessionManageAll)
+	if err != nil {
+		return permissionCheckError(c)
+	}
+
+	if !canManageAll {
+		sessionIDs := make([]uint, len(updateMeetingSessionRequest.Sessions))
+		for i, sessionReq := range updateMeetingSessionRequest.Sessions {
+			sessionIDs[i] = sessionReq.SessionID
+		}
+
+		owners, err := mc.meetingSessionRepo.GetOwners(sessionIDs)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Failed to retrieve meeting sessions",
+				"error":   err.Error(),
+			})
+		}
+
+		for _, sessionReq := range updateMeetingSessionRequest.Sessions {
+			owner, ok := owners[sessionReq.SessionID]
+			if !ok {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"status":  "fail",
+					"message": "Meeting session not found",
+				})
+			}
+			// Sesi juga tidak boleh dipindahkan ke mentor lain.
+			if owner.MentorID != userID || sessionReq.MentorID != userID {
+				return forbidden(c)
+			}
+		}
+	}
+
 	sessions := make([]*models.MeetingSession, len(updateMeetingSessionRequest.Sessions))
 
 	for i, sessionReq := range updateMeetingSessionRequest.Sessions {
@@ -350,6 +434,33 @@ func (mc *MeetingSessionController) DeleteMeetingSession(c *fiber.Ctx) error {
 		})
 	}
 
+	userID, canManageAll, err := ownershipScope(c, mc.permissionRepo, models.PermSessionManageAll)
+	if err != nil {
+		return permissionCheckError(c)
+	}
+
+	if !canManageAll {
+		owners, err := mc.meetingSessionRepo.GetOwners([]uint{uint(sessionID)})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Failed to retrieve meeting session",
+				"error":   err.Error(),
+			})
+		}
+
+		owner, ok := owners[uint(sessionID)]
+		if !ok {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Meeting session not found",
+			})
+		}
+		if owner.MentorID != userID {
+			return forbidden(c)
+		}
+	}
+
 	if err := mc.meetingSessionRepo.Delete(uint(sessionID)); err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -0,0 +1,768 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/database"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/auth"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+const (
+	mfaPendingPurpose = "mfa_pending"
+	// mfaPendingTTL is how long the second login step may take after the
+	// password was accepted.
+	mfaPendingTTL = 5 * time.Minute
+)
+
+var errMFACodeInvalid = errors.New("invalid or already used code")
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /auth/mfa
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetMFAStatus shows whether the user has two-factor authentication enabled
+// and whether it is mandatory for them.
+func (ac *AuthController) GetMFAStatus(c *fiber.Ctx) error {
+	userID := uint(c.Locals("userID").(int))
+
+	mfa, err := ac.authRepo.GetMFA(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve two-factor authentication",
+			"error":   err.Error(),
+		})
+	}
+
+	required, err := ac.mfaRequired(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve two-factor authentication policy",
+			"error":   err.Error(),
+		})
+	}
+
+	data := fiber.Map{
+		"enabled":  mfa.IsEnabled(),
+		"required": required,
+	}
+	if mfa.IsEnabled() {
+		left, err := ac.authRepo.CountRecoveryCodes(userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Failed to retrieve recovery codes",
+				"error":   err.Error(),
+			})
+		}
+		data["enabled_at"] = mfa.EnabledAt
+		data["recovery_codes_left"] = left
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Two-factor authentication status retrieved successfully",
+		"data":    data,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /auth/mfa/setup
+// ─────────────────────────────────────────────────────────────────────────────
+
+// SetupMFA starts enrollment: it returns a new secret and the provisioning
+// URI to show as a QR code. The secret is only used once a code from it is
+// confirmed with EnableMFA.
+func (ac *AuthController) SetupMFA(c *fiber.Ctx) error {
+	user, err := ac.userRepo.GetByID(uint(c.Locals("userID").(int)))
+	if err != nil || user == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	}
+
+	return ac.beginMFASetup(c, user)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /auth/mfa/enable
+// ─────────────────────────────────────────────────────────────────────────────
+
+// EnableMFA confirms enrollment with a code from the authenticator app and
+// returns the recovery codes, which are shown only this once.
+func (ac *AuthController) EnableMFA(c *fiber.Ctx) error {
+	req := new(requests.MFACodeRequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+
+	userID := uint(c.Locals("userID").(int))
+	mfa, err := ac.authRepo.GetMFA(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve two-factor authentication",
+			"error":   err.Error(),
+		})
+	}
+	if mfa == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication setup has not been started",
+		})
+	}
+	if mfa.IsEnabled() {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": models.ErrMFAAlreadyEnabled.Error(),
+		})
+	}
+
+	recoveryCodes, err := ac.confirmMFA(mfa, req.Code)
+	if err != nil {
+		return mfaCodeError(c, err)
+	}
+
+	log.Printf("[AUTH] User %d enabled two-factor authentication", userID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Two-factor authentication enabled",
+		"data": fiber.Map{
+			"recovery_codes": recoveryCodes,
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// DELETE /auth/mfa
+// ─────────────────────────────────────────────────────────────────────────────
+
+// DisableMFA turns two-factor authentication off after checking the password
+// and a current code. Admin panel users cannot turn it off while it is
+// mandatory.
+func (ac *AuthController) DisableMFA(c *fiber.Ctx) error {
+	req := new(requests.DisableMFARequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+
+	userID := uint(c.Locals("userID").(int))
+
+	required, err := ac.mfaRequired(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve two-factor authentication policy",
+			"error":   err.Error(),
+		})
+	}
+	if required {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication is required for your account",
+		})
+	}
+
+	user, err := ac.userRepo.GetByID(userID)
+	if err == nil && user != nil {
+		user, err = ac.userRepo.GetByEmail(user.Email)
+	}
+	if err != nil || user == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	}
+	if !user.CheckPassword(req.Password) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid password",
+		})
+	}
+
+	mfa, err := ac.authRepo.GetMFA(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve two-factor authentication",
+			"error":   err.Error(),
+		})
+	}
+	if !mfa.IsEnabled() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication is not enabled",
+		})
+	}
+	if err := ac.verifyMFACode(mfa, req.Code); err != nil {
+		return mfaCodeError(c, err)
+	}
+
+	if err := ac.authRepo.DisableMFA(userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to disable two-factor authentication",
+			"error":   err.Error(),
+		})
+	}
+
+	log.Printf("[AUTH] User %d disabled two-factor authentication", userID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /auth/mfa/recovery-codes
+// ─────────────────────────────────────────────────────────────────────────────
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a
+// current authenticator code.
+func (ac *AuthController) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	req := new(requests.MFACodeRequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+
+	userID := uint(c.Locals("userID").(int))
+	mfa, err := ac.authRepo.GetMFA(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve two-factor authentication",
+			"error":   err.Error(),
+		})
+	}
+	if !mfa.IsEnabled() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication is not enabled",
+		})
+	}
+
+	step, ok := auth.ValidateTOTP(mfa.Secret, req.Code, time.Now())
+	if !ok {
+		return mfaCodeError(c, errMFACodeInvalid)
+	}
+	if used, err := ac.authRepo.UseTOTPStep(userID, step); err != nil {
+		return mfaCodeError(c, err)
+	} else if !used {
+		return mfaCodeError(c, errMFACodeInvalid)
+	}
+
+	recoveryCodes, hashes, err := auth.GenerateRecoveryCodes()
+	if err == nil {
+		err = ac.authRepo.ReplaceRecoveryCodes(userID, hashes)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to regenerate recovery codes",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Recovery codes regenerated",
+		"data": fiber.Map{
+			"recovery_codes": recoveryCodes,
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /auth/login/mfa/setup
+// ─────────────────────────────────────────────────────────────────────────────
+
+// SetupMFAForLogin lets an admin who must use two-factor authentication but
+// has not enrolled yet start enrollment with the pending login token.
+func (ac *AuthController) SetupMFAForLogin(c *fiber.Ctx) error {
+	req := new(requests.MFALoginSetupRequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+
+	pending, err := peekMFAToken(req.MFAToken)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid login token",
+			"error":   err.Error(),
+		})
+	}
+
+	user, err := ac.userRepo.GetByID(pending.UserID)
+	if err != nil || user == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	}
+
+	return ac.beginMFASetup(c, user)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /auth/login/mfa
+// ─────────────────────────────────────────────────────────────────────────────
+
+// LoginMFA is the second login step: it exchanges the pending login token
+// and an authenticator or recovery code for the session tokens. For an
+// enrollment started during login the code also confirms it, and the
+// recovery codes are returned alongside the tokens. Wrong codes are counted
+// per user; once the user is blocked the pending token is dropped and the
+// login has to start over.
+func (ac *AuthController) LoginMFA(c *fiber.Ctx) error {
+	req := new(requests.MFALoginRequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+
+	pending, err := peekMFAToken(req.MFAToken)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid login token",
+			"error":   err.Error(),
+		})
+	}
+
+	// Permintaan ini tidak membawa email, jadi tebakan kode dihitung per pengguna.
+	userKey := "user:" + strconv.FormatUint(uint64(pending.UserID), 10)
+	if wait, err := ac.mfaLimiter.Wait(c.Context(), userKey); err != nil {
+		log.Printf("[AUTH] Failed to check two-factor authentication throttle: %v", err)
+	} else if wait > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Too many attempts",
+			"error":   "Please wait before trying again",
+		})
+	}
+
+	user, err := ac.userRepo.GetByID(pending.UserID)
+	if err != nil || user == nil || !user.IsActive {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Login failed",
+		})
+	}
+
+	mfa, err := ac.authRepo.GetMFA(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve two-factor authentication",
+			"error":   err.Error(),
+		})
+	}
+	if mfa == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Two-factor authentication setup has not been started",
+		})
+	}
+
+	var recoveryCodes []string
+	if mfa.IsEnabled() {
+		err = ac.verifyMFACode(mfa, req.Code)
+	} else {
+		recoveryCodes, err = ac.confirmMFA(mfa, req.Code)
+	}
+	if err == errMFACodeInvalid {
+		ac.recordMFAFailure(c.Context(), userKey, req.MFAToken)
+	}
+	if err != nil {
+		return mfaCodeError(c, err)
+	}
+	if err := ac.mfaLimiter.Reset(c.Context(), userKey); err != nil {
+		log.Printf("[AUTH] Failed to reset two-factor authentication throttle: %v", err)
+	}
+
+	// Token baru dihabiskan setelah kode benar, agar salah ketik tidak memaksa login ulang.
+	if _, err := auth.ValidateOneTimeToken(req.MFAToken, mfaPendingPurpose); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid login token",
+			"error":   err.Error(),
+		})
+	}
+
+	tokens, err := startSession(c, ac.jwtManager, ac.authRepo, user, req.DeviceName)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot generate authentication tokens",
+			"error":   err.Error(),
+		})
+	}
+	if recoveryCodes != nil {
+		tokens["recovery_codes"] = recoveryCodes
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Login successful",
+		"data":    tokens,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/security/mfa-policy
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *AuthController) GetMFAPolicy(c *fiber.Ctx) error {
+	required, err := ac.settingRepo.GetBool(models.SettingMFARequiredForAdmins, false)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve two-factor authentication policy",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Two-factor authentication policy retrieved successfully",
+		"data": fiber.Map{
+			"required_for_admins": required,
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PUT /admin/security/mfa-policy
+// ─────────────────────────────────────────────────────────────────────────────
+
+// UpdateMFAPolicy makes two-factor authentication mandatory (or optional)
+// for everyone who can log in to the admin panel. Those without it enroll on
+// their next login.
+func (ac *AuthController) UpdateMFAPolicy(c *fiber.Ctx) error {
+	req := new(requests.MFAPolicyRequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+
+	adminID := uint(c.Locals("userID").(int))
+	if err := ac.settingRepo.SetBool(models.SettingMFARequiredForAdmins, *req.RequiredForAdmins, adminID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to update two-factor authentication policy",
+			"error":   err.Error(),
+		})
+	}
+
+	log.Printf("[AUTH] Admin %d set two-factor authentication required for admins to %t", adminID, *req.RequiredForAdmins)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Two-factor authentication policy updated successfully",
+		"data": fiber.Map{
+			"required_for_admins": *req.RequiredForAdmins,
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// DELETE /users/:id/mfa
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ResetUserMFA lets an admin remove the two-factor authentication of a user
+// who lost both their authenticator and recovery codes.
+func (uc *UserController) ResetUserMFA(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid user ID",
+		})
+	}
+
+	user, err := uc.userRepo.GetByID(uint(userID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve user",
+			"error":   err.Error(),
+		})
+	}
+	if user == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	}
+
+	if err := uc.authRepo.DisableMFA(user.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to reset two-factor authentication",
+			"error":   err.Error(),
+		})
+	}
+
+	log.Printf("[AUTH] Admin %v reset two-factor authentication of user %d", c.Locals("userID"), user.ID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Two-factor authentication has been reset",
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// mfaRequired memberitahu apakah 2FA wajib untuk pengguna tersebut. Kebijakan
+// ini berlaku untuk semua yang bisa masuk ke panel admin.
+func (ac *AuthController) mfaRequired(ctx context.Context, userID uint) (bool, error) {
+	required, err := ac.settingRepo.GetBool(models.SettingMFARequiredForAdmins, false)
+	if err != nil || !required {
+		return false, err
+	}
+	return ac.permissionRepo.HasPermission(ctx, userID, models.PermAdminPanel)
+}
+
+// mfaChallenge mengembalikan langkah kedua login bila pengguna memakai 2FA atau
+// diwajibkan memakainya, atau nil bila token sesi boleh langsung diterbitkan.
+func (ac *AuthController) mfaChallenge(ctx context.Context, user *models.User) (fiber.Map, error) {
+	mfa, err := ac.authRepo.GetMFA(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !mfa.IsEnabled() {
+		required, err := ac.mfaRequired(ctx, user.ID)
+		if err != nil || !required {
+			return nil, err
+		}
+	}
+
+	pending, err := auth.GenerateOneTimeToken(user.ID, mfaPendingPurpose, mfaPendingTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return fiber.Map{
+		"mfa_required":       true,
+		"mfa_setup_required": !mfa.IsEnabled(),
+		"mfa_token":          pending.Token,
+		"mfa_token_expiry":   pending.ExpiresAt,
+	}, nil
+}
+
+// beginMFASetup membuat secret baru yang belum aktif untuk pengguna.
+func (ac *AuthController) beginMFASetup(c *fiber.Ctx, user *models.User) error {
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to generate two-factor authentication secret",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := ac.authRepo.SaveMFASecret(user.ID, secret); err != nil {
+		if err == models.ErrMFAAlreadyEnabled {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status":  "fail",
+				"message": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to save two-factor authentication secret",
+			"error":   err.Error(),
+		})
+	}
+
+	issuer := app.GetEnv("MFA_ISSUER", "Lentera Cendekia")
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Scan the QR code with your authenticator app, then confirm with a code",
+		"data": fiber.Map{
+			"secret":           secret,
+			"provisioning_uri": auth.TOTPProvisioningURI(issuer, user.Email, secret),
+		},
+	})
+}
+
+// confirmMFA mengaktifkan pendaftaran bila kode dari secret baru benar dan
+// mengembalikan kode pemulihan dalam bentuk asli.
+func (ac *AuthController) confirmMFA(mfa *models.UserMFA, code string) ([]string, error) {
+	step, ok := auth.ValidateTOTP(mfa.Secret, code, time.Now())
+	if !ok {
+		return nil, errMFACodeInvalid
+	}
+
+	recoveryCodes, hashes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		authRepo := ac.authRepo.WithExecutor(tx)
+		if used, err := authRepo.UseTOTPStep(mfa.UserID, step); err != nil {
+			return err
+		} else if !used {
+			return errMFACodeInvalid
+		}
+		return authRepo.EnableMFA(mfa.UserID, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// verifyMFACode menerima kode authenticator (6 digit) atau kode pemulihan.
+// Keduanya hanya bisa dipakai sekali.
+func (ac *AuthController) verifyMFACode(mfa *models.UserMFA, code string) error {
+	if step, ok := auth.ValidateTOTP(mfa.Secret, code, time.Now()); ok {
+		used, err := ac.authRepo.UseTOTPStep(mfa.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errMFACodeInvalid
+		}
+		return nil
+	}
+
+	used, err := ac.authRepo.UseRecoveryCode(mfa.UserID, auth.HashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errMFACodeInvalid
+	}
+
+	log.Printf("[AUTH] User %d logged in with a recovery code", mfa.UserID)
+	return nil
+}
+
+// recordMFAFailure menghitung kode salah milik pengguna. Begitu pengguna
+// diblokir, token login tertunda ikut dibatalkan sehingga tebakan berikutnya
+// harus melewati login dengan password lagi.
+func (ac *AuthController) recordMFAFailure(ctx context.Context, userKey, token string) {
+	if err := ac.mfaLimiter.Fail(ctx, userKey); err != nil {
+		log.Printf("[AUTH] Failed to record two-factor authentication throttle: %v", err)
+		return
+	}
+
+	wait, err := ac.mfaLimiter.Wait(ctx, userKey)
+	if err != nil {
+		log.Printf("[AUTH] Failed to check two-factor authentication throttle: %v", err)
+		return
+	}
+	if wait > 0 {
+		if err := auth.InvalidateOneTimeToken(token); err != nil {
+			log.Printf("[AUTH] Failed to invalidate the pending login token: %v", err)
+		}
+	}
+}
+
+// peekMFAToken memeriksa token login tertunda tanpa menghabiskannya.
+func peekMFAToken(token string) (*auth.OneTimeToken, error) {
+	pending, err := auth.CheckOneTimeTokenStatus(token)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Purpose != mfaPendingPurpose || pending.Used || time.Now().After(pending.ExpiresAt) {
+		return nil, errors.New("login token is no longer valid, please log in again")
+	}
+	return pending, nil
+}
+
+func mfaCodeError(c *fiber.Ctx, err error) error {
+	if err == errMFACodeInvalid {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid two-factor authentication code",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Failed to verify two-factor authentication code",
+		"error":   err.Error(),
+	})
+}
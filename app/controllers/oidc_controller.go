@@ -0,0 +1,371 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/cache"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/oidc"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+// oidcLoginTTL is how long the user may take at the provider before the
+// login has to be started again.
+const oidcLoginTTL = 10 * time.Minute
+
+// oidcProviderIssuers lists the supported providers with their default
+// issuer. <NAME>_OIDC_ISSUER overrides it, e.g. to use cmd/mock-oidc.
+var oidcProviderIssuers = map[string]string{
+	"google": "https://accounts.google.com",
+}
+
+// oidcLogin is what a started login keeps in Redis under its state until the
+// provider redirects back.
+type oidcLogin struct {
+	Provider string `json:"provider"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /auth/oidc/:provider/authorize
+// ─────────────────────────────────────────────────────────────────────────────
+
+// AuthorizeOIDC starts a social login. The client sends the user to the
+// returned URL; the provider redirects back to the configured redirect URL
+// with the code and state that OIDCCallback expects.
+func (ac *AuthController) AuthorizeOIDC(c *fiber.Ctx) error {
+	provider, ok := ac.oidcProviders[c.Params("provider")]
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Login provider not found",
+		})
+	}
+	if cache.RedisClient == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Social login is currently unavailable",
+		})
+	}
+
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		return oidcError(c, err)
+	}
+	login := oidcLogin{Provider: provider.Name}
+	if login.Nonce, err = oidc.RandomString(32); err != nil {
+		return oidcError(c, err)
+	}
+	if login.Verifier, err = oidc.GenerateCodeVerifier(); err != nil {
+		return oidcError(c, err)
+	}
+
+	authURL, err := provider.AuthCodeURL(c.Context(), state, login.Nonce, login.Verifier)
+	if err != nil {
+		log.Printf("[AUTH] Failed to start %s login: %v", provider.Name, err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Login provider is unreachable",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := cache.Set(c.Context(), oidcLoginKey(state), login, oidcLoginTTL); err != nil {
+		return oidcError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Redirect the user to the authorization URL",
+		"data": fiber.Map{
+			"authorization_url": authURL,
+			"state":             state,
+			"state_expiry":      time.Now().Add(oidcLoginTTL),
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /auth/oidc/:provider/callback
+// ─────────────────────────────────────────────────────────────────────────────
+
+// OIDCCallback finishes a social login with the code the provider returned.
+// The first login links the provider account to the user with the same
+// verified email; later logins find the user through that link. The answer
+// is the same as Login's, including the two-factor step.
+func (ac *AuthController) OIDCCallback(c *fiber.Ctx) error {
+	provider, ok := ac.oidcProviders[c.Params("provider")]
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Login provider not found",
+		})
+	}
+
+	req := new(requests.OIDCCallbackRequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+
+	// State hanya bisa dipakai sekali, berhasil maupun gagal.
+	var login oidcLogin
+	if cache.RedisClient == nil || cache.GetDelete(c.Context(), oidcLoginKey(req.State), &login) != nil ||
+		login.Provider != provider.Name {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Login failed",
+			"error":   "Login session is invalid or expired, please try again",
+		})
+	}
+
+	idToken, err := provider.Exchange(c.Context(), req.Code, login.Verifier)
+	if err != nil {
+		log.Printf("[AUTH] Failed to exchange %s authorization code: %v", provider.Name, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Login failed",
+			"error":   "Authorization code is invalid or expired",
+		})
+	}
+
+	claims, err := provider.VerifyIDToken(c.Context(), idToken, login.Nonce)
+	if err != nil {
+		log.Printf("[AUTH] Rejected %s ID token: %v", provider.Name, err)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Login failed",
+			"error":   "Identity could not be verified",
+		})
+	}
+
+	user, err := ac.resolveOIDCUser(c, provider.Name, claims)
+	if user == nil {
+		return err
+	}
+
+	if !user.IsEmailVerified() || !user.IsActive {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Email not verified",
+			"error":   "Please verify your email before logging in",
+		})
+	}
+
+	challenge, err := ac.mfaChallenge(c.Context(), user)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot check two-factor authentication",
+			"error":   err.Error(),
+		})
+	}
+	if challenge != nil {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"status":  "success",
+			"message": "Two-factor authentication required",
+			"data":    challenge,
+		})
+	}
+
+	tokens, err := startSession(c, ac.jwtManager, ac.authRepo, user, req.DeviceName)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot generate authentication tokens",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Login successful",
+		"data":    tokens,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /auth/identities
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ListIdentities shows the login providers linked to the user's account.
+func (ac *AuthController) ListIdentities(c *fiber.Ctx) error {
+	userID := uint(c.Locals("userID").(int))
+
+	identities, err := ac.authRepo.ListIdentities(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve linked accounts",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Linked accounts retrieved successfully",
+		"data":    identities,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// DELETE /auth/identities/:provider
+// ─────────────────────────────────────────────────────────────────────────────
+
+// UnlinkIdentity removes the link to a login provider. Logging in with it
+// links the account again as long as the emails still match.
+func (ac *AuthController) UnlinkIdentity(c *fiber.Ctx) error {
+	userID := uint(c.Locals("userID").(int))
+	provider := c.Params("provider")
+
+	removed, err := ac.authRepo.UnlinkIdentity(userID, provider)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to unlink account",
+			"error":   err.Error(),
+		})
+	}
+	if !removed {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "No linked account for this provider",
+		})
+	}
+
+	log.Printf("[AUTH] User %d unlinked their %s account", userID, provider)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Account unlinked successfully",
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// loadOIDCProviders membaca provider yang dikonfigurasi dari env
+// <NAME>_OIDC_CLIENT_ID, _CLIENT_SECRET, _REDIRECT_URL dan _ISSUER. Provider
+// tanpa client ID dilewati.
+func loadOIDCProviders() map[string]*oidc.Provider {
+	providers := make(map[string]*oidc.Provider)
+	for name, defaultIssuer := range oidcProviderIssuers {
+		prefix := strings.ToUpper(name) + "_OIDC_"
+		clientID := app.GetEnv(prefix+"CLIENT_ID", "")
+		if clientID == "" {
+			continue
+		}
+
+		providers[name] = oidc.NewProvider(
+			name,
+			app.GetEnv(prefix+"ISSUER", defaultIssuer),
+			clientID,
+			app.GetEnv(prefix+"CLIENT_SECRET", ""),
+			app.GetEnv(prefix+"REDIRECT_URL", ""),
+		)
+	}
+	return providers
+}
+
+// resolveOIDCUser mencari pengguna dari identitas yang sudah tertaut, atau
+// menautkan identitas ke pengguna dengan email terverifikasi yang sama. Bila
+// pengguna nil, respons gagal sudah ditulis dan error-nya dikembalikan.
+func (ac *AuthController) resolveOIDCUser(c *fiber.Ctx, provider string, claims *oidc.Claims) (*models.User, error) {
+	var email *string
+	if claims.Email != "" {
+		email = &claims.Email
+	}
+
+	identity, err := ac.authRepo.GetIdentity(provider, claims.Subject)
+	if err != nil {
+		return nil, oidcError(c, err)
+	}
+	if identity != nil {
+		user, err := ac.userRepo.GetByID(identity.UserID)
+		if err != nil {
+			return nil, oidcError(c, err)
+		}
+		if user == nil {
+			return nil, oidcNoAccount(c)
+		}
+		if err := ac.authRepo.TouchIdentity(identity.ID, email); err != nil {
+			log.Printf("[AUTH] Failed to record %s login of user %d: %v", provider, user.ID, err)
+		}
+		return user, nil
+	}
+
+	// Tanpa email terverifikasi, kepemilikan akun tidak bisa dipastikan.
+	if email == nil || !claims.EmailVerified {
+		return nil, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Login failed",
+			"error":   "The provider did not confirm your email address",
+		})
+	}
+
+	user, err := ac.userRepo.GetByEmail(claims.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, oidcNoAccount(c)
+		}
+		return nil, oidcError(c, err)
+	}
+
+	err = ac.authRepo.LinkIdentity(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    email,
+	})
+	if err == models.ErrIdentityAlreadyLinked {
+		return nil, c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Login failed",
+			"error":   fmt.Sprintf("This account is already linked to another %s account", provider),
+		})
+	} else if err != nil {
+		return nil, oidcError(c, err)
+	}
+
+	log.Printf("[AUTH] Linked %s account to user %d by verified email", provider, user.ID)
+	return user, nil
+}
+
+func oidcLoginKey(state string) string {
+	return "oidc_login:" + state
+}
+
+func oidcNoAccount(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		"status":  "fail",
+		"message": "Login failed",
+		"error":   "No account is registered with this email",
+	})
+}
+
+func oidcError(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Login failed",
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+)
+
+// ownershipScope mengembalikan ID pengguna yang sedang login dan apakah salah
+// satu izinnya membolehkan ia mengakses data milik pengguna lain.
+func ownershipScope(c *fiber.Ctx, permissionRepo *models.PermissionRepository, permissions ...string) (uint, bool, error) {
+	userID := uint(c.Locals("userID").(int))
+	canManageAll, err := permissionRepo.HasPermission(c.Context(), userID, permissions...)
+	if err != nil {
+		log.Printf("[AUTH] Failed to load permissions of user %d: %v", userID, err)
+		return userID, false, err
+	}
+	return userID, canManageAll, nil
+}
+
+// permissionCheckError ditulis saat izin pengguna gagal dimuat.
+func permissionCheckError(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Failed to check permissions",
+	})
+}
+
+// forbidden menulis respons 403 yang sama dengan RequirePermission, sehingga
+// penolakan karena kepemilikan tidak bisa dibedakan dari penolakan karena izin.
+func forbidden(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status":  "fail",
+		"message": "Forbidden",
+		"error":   "You do not have permission to access this resource",
+	})
+}
@@ -3,8 +3,11 @@ package controllers
 import (
 	"database/sql"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
 	"github.com/studio-senkou/lentera-cendekia-be/app/models"
 	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
 	"github.com/studio-senkou/lentera-cendekia-be/database"
@@ -73,12 +76,22 @@ func (ac *QuizAdminController) GetQuiz(c *fiber.Ctx) error {
 		})
 	}
 
+	rules, err := ac.adminRepo.ListSamplingRules(quizID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve sampling rules",
+			"error":   err.Error(),
+		})
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Quiz retrieved successfully",
 		"data": fiber.Map{
-			"quiz":      quiz,
-			"questions": buildAdminQuestionsResponse(questions),
+			"quiz":           quiz,
+			"questions":      buildAdminQuestionsResponse(questions),
+			"sampling_rules": rules,
 		},
 	})
 }
@@ -103,15 +116,30 @@ func (ac *QuizAdminController) CreateQuiz(c *fiber.Ctx) error {
 		})
 	}
 
-	quiz := &models.QuizQuiz{
-		Title:            req.Title,
-		Description:      req.Description,
-		PassingScore:     req.PassingScore,
-		TimeLimitMinutes: req.TimeLimitMinutes,
-		IsActive:         req.IsActive,
+	if ve := quizWindowErrors(req.OpensAt, req.ClosesAt); len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
 	}
 
-	if err := ac.adminRepo.CreateQuiz(quiz); err != nil {
+	quiz := newQuizFromRequest(*req)
+
+	err := database.DB.Transaction(func(tx *sql.Tx) error {
+		adminRepo := ac.adminRepo.WithExecutor(tx)
+		if err := adminRepo.CreateQuiz(quiz); err != nil {
+			return err
+		}
+		return adminRepo.ReplaceQuizClasses(quiz.ID, quiz.ClassIDs)
+	})
+	if err != nil {
+		if err == models.ErrClassNotFound {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "One or more classes were not found",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Failed to create quiz",
@@ -154,22 +182,37 @@ func (ac *QuizAdminController) UpdateQuiz(c *fiber.Ctx) error {
 		})
 	}
 
-	quiz := &models.QuizQuiz{
-		ID:               quizID,
-		Title:            req.Title,
-		Description:      req.Description,
-		PassingScore:     req.PassingScore,
-		TimeLimitMinutes: req.TimeLimitMinutes,
-		IsActive:         req.IsActive,
+	if ve := quizWindowErrors(req.OpensAt, req.ClosesAt); len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
 	}
 
-	if err := ac.adminRepo.UpdateQuiz(quiz); err != nil {
+	quiz := newQuizFromRequest(requests.CreateQuizRequest(*req))
+	quiz.ID = quizID
+
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		adminRepo := ac.adminRepo.WithExecutor(tx)
+		if err := adminRepo.UpdateQuiz(quiz); err != nil {
+			return err
+		}
+		return adminRepo.ReplaceQuizClasses(quiz.ID, quiz.ClassIDs)
+	})
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"status":  "fail",
 				"message": "Quiz not found",
 			})
 		}
+		if err == models.ErrClassNotFound {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "One or more classes were not found",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Failed to update quiz",
@@ -219,14 +262,15 @@ func (ac *QuizAdminController) DeleteQuiz(c *fiber.Ctx) error {
 
 // ─────────────────────────────────────────────────────────────────────────────
 // POST /admin/quizzes/:id/questions
+// POST /admin/question-banks/:bid/questions
 // ─────────────────────────────────────────────────────────────────────────────
 
 func (ac *QuizAdminController) CreateQuestion(c *fiber.Ctx) error {
-	quizID, err := parseID(c, "id")
+	owner, invalidMessage, err := parseQuestionOwner(c)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Invalid quiz ID",
+			"message": invalidMessage,
 		})
 	}
 
@@ -245,12 +289,29 @@ func (ac *QuizAdminController) CreateQuestion(c *fiber.Ctx) error {
 		})
 	}
 
-	question := &models.QuizQuestion{
-		QuizID:       quizID,
-		QuestionText: req.QuestionText,
+	question := newQuestionFromRequest(owner, *req)
+	if err := question.ValidateConfig(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": err.Error(),
+		})
 	}
 
-	if err := ac.adminRepo.CreateQuestion(question); err != nil {
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		adminRepo := ac.adminRepo.WithExecutor(tx)
+		if err := adminRepo.CreateQuestion(question); err != nil {
+			return err
+		}
+		if question.QuestionType == models.QuestionTypeTrueFalse {
+			options, err := adminRepo.SetTrueFalseOptions(question.ID, *req.CorrectAnswer)
+			if err != nil {
+				return err
+			}
+			question.Options = options
+		}
+		return nil
+	})
+	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Failed to create question",
@@ -261,20 +322,21 @@ func (ac *QuizAdminController) CreateQuestion(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Question created successfully",
-		"data":    question,
+		"data":    buildAdminQuestionResponse(*question),
 	})
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
 // PUT /admin/quizzes/:id/questions/:qid
+// PUT /admin/question-banks/:bid/questions/:qid
 // ─────────────────────────────────────────────────────────────────────────────
 
 func (ac *QuizAdminController) UpdateQuestion(c *fiber.Ctx) error {
-	quizID, err := parseID(c, "id")
+	owner, invalidMessage, err := parseQuestionOwner(c)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Invalid quiz ID",
+			"message": invalidMessage,
 		})
 	}
 	questionID, err := parseID(c, "qid")
@@ -300,13 +362,33 @@ func (ac *QuizAdminController) UpdateQuestion(c *fiber.Ctx) error {
 		})
 	}
 
-	question := &models.QuizQuestion{
-		ID:           questionID,
-		QuizID:       quizID,
-		QuestionText: req.QuestionText,
+	question := newQuestionFromRequest(owner, requests.CreateQuestionRequest(*req))
+	question.ID = questionID
+	if err := question.ValidateConfig(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": err.Error(),
+		})
 	}
 
-	if err := ac.adminRepo.UpdateQuestion(question); err != nil {
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		adminRepo := ac.adminRepo.WithExecutor(tx)
+		if err := adminRepo.UpdateQuestion(question); err != nil {
+			return err
+		}
+		switch {
+		case question.QuestionType == models.QuestionTypeTrueFalse:
+			options, err := adminRepo.SetTrueFalseOptions(question.ID, *req.CorrectAnswer)
+			if err != nil {
+				return err
+			}
+			question.Options = options
+		case !question.HasOptions():
+			return adminRepo.ClearOptions(question.ID)
+		}
+		return nil
+	})
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"status":  "fail",
@@ -323,20 +405,21 @@ func (ac *QuizAdminController) UpdateQuestion(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Question updated successfully",
-		"data":    question,
+		"data":    buildAdminQuestionResponse(*question),
 	})
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
 // DELETE /admin/quizzes/:id/questions/:qid
+// DELETE /admin/question-banks/:bid/questions/:qid
 // ─────────────────────────────────────────────────────────────────────────────
 
 func (ac *QuizAdminController) DeleteQuestion(c *fiber.Ctx) error {
-	quizID, err := parseID(c, "id")
+	owner, invalidMessage, err := parseQuestionOwner(c)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Invalid quiz ID",
+			"message": invalidMessage,
 		})
 	}
 	questionID, err := parseID(c, "qid")
@@ -347,7 +430,16 @@ func (ac *QuizAdminController) DeleteQuestion(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := ac.adminRepo.DeleteQuestion(questionID, quizID); err != nil {
+	attachmentPaths, err := ac.adminRepo.ListAttachmentPaths(questionID, nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to delete question",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := ac.adminRepo.DeleteQuestion(questionID, owner); err != nil {
 		if err == sql.ErrNoRows {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"status":  "fail",
@@ -360,6 +452,7 @@ func (ac *QuizAdminController) DeleteQuestion(c *fiber.Ctx) error {
 			"error":   err.Error(),
 		})
 	}
+	ac.removeAttachmentFiles(attachmentPaths)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
@@ -369,14 +462,15 @@ func (ac *QuizAdminController) DeleteQuestion(c *fiber.Ctx) error {
 
 // ─────────────────────────────────────────────────────────────────────────────
 // POST /admin/quizzes/:id/questions/:qid/options
+// POST /admin/question-banks/:bid/questions/:qid/options
 // ─────────────────────────────────────────────────────────────────────────────
 
 func (ac *QuizAdminController) CreateOption(c *fiber.Ctx) error {
-	_, err := parseID(c, "id") // pastikan quiz param ada
+	owner, invalidMessage, err := parseQuestionOwner(c)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Invalid quiz ID",
+			"message": invalidMessage,
 		})
 	}
 	questionID, err := parseID(c, "qid")
@@ -402,10 +496,38 @@ func (ac *QuizAdminController) CreateOption(c *fiber.Ctx) error {
 		})
 	}
 
+	questionType, err := ac.adminRepo.GetQuestionType(questionID, owner)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve question",
+			"error":   err.Error(),
+		})
+	}
+	switch questionType {
+	case "":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Question not found",
+		})
+	case models.QuestionTypeShortAnswer, models.QuestionTypeNumeric:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "This question type does not use options",
+		})
+	case models.QuestionTypeTrueFalse:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "True/False options are set through the question's correct_answer",
+		})
+	}
+
 	option := &models.QuizOption{
 		QuestionID:  questionID,
 		OptionText:  req.OptionText,
+		TextFormat:  req.TextFormat,
 		IsCorrect:   req.IsCorrect,
+		Explanation: req.Explanation,
 	}
 
 	if err := ac.adminRepo.CreateOption(option); err != nil {
@@ -420,26 +542,29 @@ func (ac *QuizAdminController) CreateOption(c *fiber.Ctx) error {
 		"status":  "success",
 		"message": "Option created successfully",
 		"data": fiber.Map{
-			"id":           option.ID,
-			"question_id":  option.QuestionID,
-			"option_text":  option.OptionText,
-			"is_correct":   option.IsCorrect,
-			"created_at":   option.CreatedAt,
-			"updated_at":   option.UpdatedAt,
+			"id":          option.ID,
+			"question_id": option.QuestionID,
+			"option_text": option.OptionText,
+			"text_format": option.TextFormat,
+			"is_correct":  option.IsCorrect,
+			"explanation": option.Explanation,
+			"created_at":  option.CreatedAt,
+			"updated_at":  option.UpdatedAt,
 		},
 	})
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
 // PUT /admin/quizzes/:id/questions/:qid/options/:oid
+// PUT /admin/question-banks/:bid/questions/:qid/options/:oid
 // ─────────────────────────────────────────────────────────────────────────────
 
 func (ac *QuizAdminController) UpdateOption(c *fiber.Ctx) error {
-	_, err := parseID(c, "id")
+	_, invalidMessage, err := parseQuestionOwner(c)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Invalid quiz ID",
+			"message": invalidMessage,
 		})
 	}
 	questionID, err := parseID(c, "qid")
@@ -476,7 +601,9 @@ func (ac *QuizAdminController) UpdateOption(c *fiber.Ctx) error {
 		ID:          optionID,
 		QuestionID:  questionID,
 		OptionText:  req.OptionText,
+		TextFormat:  req.TextFormat,
 		IsCorrect:   req.IsCorrect,
+		Explanation: req.Explanation,
 	}
 
 	if err := ac.adminRepo.UpdateOption(option); err != nil {
@@ -497,25 +624,28 @@ func (ac *QuizAdminController) UpdateOption(c *fiber.Ctx) error {
 		"status":  "success",
 		"message": "Option updated successfully",
 		"data": fiber.Map{
-			"id":           option.ID,
-			"question_id":  option.QuestionID,
-			"option_text":  option.OptionText,
-			"is_correct":   option.IsCorrect,
-			"updated_at":   option.UpdatedAt,
+			"id":          option.ID,
+			"question_id": option.QuestionID,
+			"option_text": option.OptionText,
+			"text_format": option.TextFormat,
+			"is_correct":  option.IsCorrect,
+			"explanation": option.Explanation,
+			"updated_at":  option.UpdatedAt,
 		},
 	})
 }
 
 // ─────────────────────────────────────────────────────────────────────────────
 // DELETE /admin/quizzes/:id/questions/:qid/options/:oid
+// DELETE /admin/question-banks/:bid/questions/:qid/options/:oid
 // ─────────────────────────────────────────────────────────────────────────────
 
 func (ac *QuizAdminController) DeleteOption(c *fiber.Ctx) error {
-	_, err := parseID(c, "id")
+	_, invalidMessage, err := parseQuestionOwner(c)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Invalid quiz ID",
+			"message": invalidMessage,
 		})
 	}
 	questionID, err := parseID(c, "qid")
@@ -533,6 +663,15 @@ func (ac *QuizAdminController) DeleteOption(c *fiber.Ctx) error {
 		})
 	}
 
+	attachmentPaths, err := ac.adminRepo.ListAttachmentPaths(questionID, &optionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to delete option",
+			"error":   err.Error(),
+		})
+	}
+
 	if err := ac.adminRepo.DeleteOption(optionID, questionID); err != nil {
 		if err == sql.ErrNoRows {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -546,6 +685,7 @@ func (ac *QuizAdminController) DeleteOption(c *fiber.Ctx) error {
 			"error":   err.Error(),
 		})
 	}
+	ac.removeAttachmentFiles(attachmentPaths)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
@@ -582,6 +722,41 @@ func (ac *QuizAdminController) ListAttempts(c *fiber.Ctx) error {
 	})
 }
 
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/quizzes/:id/analysis
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) GetQuizAnalysis(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	analysis, err := ac.adminRepo.GetQuizAnalysis(quizID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to analyze quiz",
+			"error":   err.Error(),
+		})
+	}
+	if analysis == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Quiz analysis retrieved successfully",
+		"data":    analysis,
+	})
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Helpers
 // ─────────────────────────────────────────────────────────────────────────────
@@ -595,23 +770,133 @@ func parseID(c *fiber.Ctx, param string) (uint, error) {
 	return uint(val), nil
 }
 
+// newQuizFromRequest memetakan request kuis ke model dan mengisi default aturan attempt.
+func newQuizFromRequest(req requests.CreateQuizRequest) *models.QuizQuiz {
+	quiz := &models.QuizQuiz{
+		Title:                req.Title,
+		Description:          req.Description,
+		PassingScore:         req.PassingScore,
+		TimeLimitMinutes:     req.TimeLimitMinutes,
+		MaxAttempts:          req.MaxAttempts,
+		CooldownMinutes:      req.CooldownMinutes,
+		ScorePolicy:          req.ScorePolicy,
+		ReviewPolicy:         req.ReviewPolicy,
+		OpensAt:              req.OpensAt,
+		ClosesAt:             req.ClosesAt,
+		ClassIDs:             pq.StringArray{},
+		CertificateEnabled:   req.CertificateEnabled,
+		LeaderboardOptOut:    req.LeaderboardOptOut,
+		LeaderboardAnonymous: req.LeaderboardAnonymous,
+		LeaderboardPublic:    req.LeaderboardPublic,
+		IsActive:             req.IsActive,
+	}
+	for _, classID := range req.ClassIDs {
+		quiz.ClassIDs = append(quiz.ClassIDs, strings.ToLower(classID))
+	}
+
+	switch {
+	case quiz.MaxAttempts == nil:
+		one := 1
+		quiz.MaxAttempts = &one
+	case *quiz.MaxAttempts == 0:
+		quiz.MaxAttempts = nil
+	}
+	if quiz.ScorePolicy == "" {
+		quiz.ScorePolicy = models.ScorePolicyLatest
+	}
+	if quiz.ReviewPolicy == "" {
+		quiz.ReviewPolicy = models.ReviewPolicyNever
+	}
+	return quiz
+}
+
+// quizWindowErrors memastikan jadwal tutup kuis berada setelah jadwal buka.
+func quizWindowErrors(opensAt, closesAt *time.Time) map[string]string {
+	if opensAt != nil && closesAt != nil && !closesAt.After(*opensAt) {
+		return map[string]string{"closes_at": "The closes_at field must be after opens_at"}
+	}
+	return nil
+}
+
+// parseQuestionOwner membaca pemilik soal dari path: :bid untuk bank soal, :id untuk kuis.
+// Nilai string kedua adalah pesan error untuk ID yang tidak valid.
+func parseQuestionOwner(c *fiber.Ctx) (models.QuizQuestionOwner, string, error) {
+	if c.Params("bid") != "" {
+		bankID, err := parseID(c, "bid")
+		return models.QuizQuestionOwner{BankID: &bankID}, "Invalid question bank ID", err
+	}
+	quizID, err := parseID(c, "id")
+	return models.QuizQuestionOwner{QuizID: &quizID}, "Invalid quiz ID", err
+}
+
+// newQuestionFromRequest memetakan request soal ke model dan mengisi default sesuai jenis soal.
+func newQuestionFromRequest(owner models.QuizQuestionOwner, req requests.CreateQuestionRequest) *models.QuizQuestion {
+	question := &models.QuizQuestion{
+		QuizID:           owner.QuizID,
+		BankID:           owner.BankID,
+		QuestionText:     req.QuestionText,
+		TextFormat:       req.TextFormat,
+		QuestionType:     req.QuestionType,
+		ScoringMode:      req.ScoringMode,
+		MatchMode:        req.MatchMode,
+		AcceptedAnswers:  req.AcceptedAnswers,
+		NumericAnswer:    req.NumericAnswer,
+		NumericTolerance: req.NumericTolerance,
+		Difficulty:       req.Difficulty,
+		Explanation:      req.Explanation,
+		Points:           req.Points,
+		PenaltyPoints:    req.PenaltyPoints,
+	}
+	for _, tag := range req.Tags {
+		question.Tags = append(question.Tags, *normalizeTag(&tag))
+	}
+	question.ApplyDefaults()
+	return question
+}
+
 // buildAdminQuestionsResponse membangun response soal untuk admin (termasuk is_correct).
 func buildAdminQuestionsResponse(questions []models.QuizQuestion) []fiber.Map {
 	result := make([]fiber.Map, len(questions))
 	for i, q := range questions {
-		options := make([]fiber.Map, len(q.Options))
-		for j, o := range q.Options {
-			options[j] = fiber.Map{
-				"id":           o.ID,
-				"option_text":  o.OptionText,
-			"is_correct":   o.IsCorrect,
+		result[i] = buildAdminQuestionResponse(q)
+	}
+	return result
+}
+
+// buildAdminQuestionResponse membangun response satu soal untuk admin, termasuk kunci jawaban.
+func buildAdminQuestionResponse(q models.QuizQuestion) fiber.Map {
+	options := make([]fiber.Map, len(q.Options))
+	for j, o := range q.Options {
+		options[j] = fiber.Map{
+			"id":          o.ID,
+			"option_text": o.OptionText,
+			"text_format": o.TextFormat,
+			"is_correct":  o.IsCorrect,
+			"explanation": o.Explanation,
+			"attachments": attachmentsOrEmpty(o.Attachments),
 		}
 	}
-	result[i] = fiber.Map{
-		"id":            q.ID,
-		"question_text": q.QuestionText,
-		"options":       options,
+	acceptedAnswers := make([]string, 0, len(q.AcceptedAnswers))
+	acceptedAnswers = append(acceptedAnswers, q.AcceptedAnswers...)
+	tags := make([]string, 0, len(q.Tags))
+	tags = append(tags, q.Tags...)
+
+	return fiber.Map{
+		"id":                q.ID,
+		"question_text":     q.QuestionText,
+		"text_format":       q.TextFormat,
+		"question_type":     q.QuestionType,
+		"scoring_mode":      q.ScoringMode,
+		"match_mode":        q.MatchMode,
+		"accepted_answers":  acceptedAnswers,
+		"numeric_answer":    q.NumericAnswer,
+		"numeric_tolerance": q.NumericTolerance,
+		"tags":              tags,
+		"difficulty":        q.Difficulty,
+		"explanation":       q.Explanation,
+		"points":            q.Points,
+		"penalty_points":    q.PenaltyPoints,
+		"options":           options,
+		"attachments":       attachmentsOrEmpty(q.Attachments),
 	}
 }
-return result
-}
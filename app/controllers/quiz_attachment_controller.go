@@ -0,0 +1,303 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/storage"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+const (
+	maxQuizImageSize = int64(1 * 1024 * 1024)
+	maxQuizAudioSize = int64(10 * 1024 * 1024)
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /admin/quizzes/:id/questions/:qid/attachments
+// POST /admin/question-banks/:bid/questions/:qid/attachments
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) UploadAttachment(c *fiber.Ctx) error {
+	owner, invalidMessage, err := parseQuestionOwner(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": invalidMessage,
+		})
+	}
+	questionID, err := parseID(c, "qid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid question ID",
+		})
+	}
+
+	req := new(requests.UploadQuizAttachmentRequest)
+	if ve, err := validator.ValidateFormData(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
+	}
+
+	if found, err := ac.questionExists(questionID, owner); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve question",
+			"error":   err.Error(),
+		})
+	} else if !found {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Question not found",
+		})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Attachment file is required",
+			"error":   err.Error(),
+		})
+	}
+
+	var kind string
+	switch {
+	case storage.IsValidImageExtension(file.Filename):
+		kind = models.AttachmentKindImage
+		if file.Size > maxQuizImageSize {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Image size exceeds the limit of 1MB",
+			})
+		}
+	case storage.IsValidAudioExtension(file.Filename):
+		kind = models.AttachmentKindAudio
+		if file.Size > maxQuizAudioSize {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Audio size exceeds the limit of 10MB",
+			})
+		}
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid attachment type, upload an image or audio file",
+		})
+	}
+
+	uploadedPath, err := storage.UploadFileToStorage(file, "quiz_attachments", "QUIZ", nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Failed to upload attachment",
+			"error":   err.Error(),
+		})
+	}
+
+	attachment := &models.QuizAttachment{
+		QuestionID:  questionID,
+		Kind:        kind,
+		FilePath:    uploadedPath,
+		FileName:    file.Filename,
+		ContentType: storage.GetContentType(file.Filename),
+		FileSize:    file.Size,
+	}
+	if req.OptionID != 0 {
+		attachment.OptionID = &req.OptionID
+	}
+
+	if err := ac.adminRepo.CreateAttachment(attachment); err != nil {
+		ac.removeAttachmentFiles([]string{uploadedPath})
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Option not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to create attachment",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Attachment uploaded successfully",
+		"data":    attachment,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PUT /admin/quizzes/:id/questions/:qid/attachments/order
+// PUT /admin/question-banks/:bid/questions/:qid/attachments/order
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) ReorderAttachments(c *fiber.Ctx) error {
+	owner, invalidMessage, err := parseQuestionOwner(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": invalidMessage,
+		})
+	}
+	questionID, err := parseID(c, "qid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid question ID",
+		})
+	}
+
+	req := new(requests.ReorderQuizAttachmentsRequest)
+	if ve, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
+	}
+
+	if found, err := ac.questionExists(questionID, owner); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve question",
+			"error":   err.Error(),
+		})
+	} else if !found {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Question not found",
+		})
+	}
+
+	if err := ac.adminRepo.ReorderAttachments(questionID, req.AttachmentIDs); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to reorder attachments",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Attachments reordered successfully",
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// DELETE /admin/quizzes/:id/questions/:qid/attachments/:aid
+// DELETE /admin/question-banks/:bid/questions/:qid/attachments/:aid
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) DeleteAttachment(c *fiber.Ctx) error {
+	owner, invalidMessage, err := parseQuestionOwner(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": invalidMessage,
+		})
+	}
+	questionID, err := parseID(c, "qid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid question ID",
+		})
+	}
+	attachmentID, err := parseID(c, "aid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid attachment ID",
+		})
+	}
+
+	if found, err := ac.questionExists(questionID, owner); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve question",
+			"error":   err.Error(),
+		})
+	} else if !found {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Question not found",
+		})
+	}
+
+	attachment, err := ac.adminRepo.DeleteAttachment(attachmentID, questionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to delete attachment",
+			"error":   err.Error(),
+		})
+	}
+	if attachment == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Attachment not found",
+		})
+	}
+	ac.removeAttachmentFiles([]string{attachment.FilePath})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Attachment deleted successfully",
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// questionExists memastikan soal dimiliki oleh kuis atau bank soal pada path.
+func (ac *QuizAdminController) questionExists(questionID uint, owner models.QuizQuestionOwner) (bool, error) {
+	questionType, err := ac.adminRepo.GetQuestionType(questionID, owner)
+	return questionType != "", err
+}
+
+// removeAttachmentFiles menghapus file lampiran dari storage setelah datanya terhapus.
+// File yang masih dipakai salinan soal di versi yang sudah dipublikasikan tidak dihapus.
+// Kegagalan hanya dicatat karena baris di database sudah tidak ada.
+func (ac *QuizAdminController) removeAttachmentFiles(paths []string) {
+	paths, err := ac.adminRepo.UnreferencedAttachmentPaths(paths)
+	if err != nil {
+		log.Printf("[QUIZ] Failed to check attachment usage, keeping files: %v", err)
+		return
+	}
+	for _, path := range paths {
+		if err := storage.RemoveFileFromStorage(path); err != nil {
+			log.Printf("[QUIZ] Failed to remove attachment %s: %v", path, err)
+		}
+	}
+}
+
+// attachmentsOrEmpty memastikan lampiran selalu dikirim sebagai array, bukan null.
+func attachmentsOrEmpty(attachments []models.QuizAttachment) []models.QuizAttachment {
+	if attachments == nil {
+		return []models.QuizAttachment{}
+	}
+	return attachments
+}
@@ -0,0 +1,336 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/database"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/question-banks
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) ListBanks(c *fiber.Ctx) error {
+	banks, err := ac.adminRepo.ListBanks()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve question banks",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Question banks retrieved successfully",
+		"data":    banks,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/question-banks/:bid
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) GetBank(c *fiber.Ctx) error {
+	bankID, err := parseID(c, "bid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid question bank ID",
+		})
+	}
+
+	bank, questions, err := ac.adminRepo.GetBankDetail(bankID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve question bank",
+			"error":   err.Error(),
+		})
+	}
+	if bank == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Question bank not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Question bank retrieved successfully",
+		"data": fiber.Map{
+			"bank":      bank,
+			"questions": buildAdminQuestionsResponse(questions),
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /admin/question-banks
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) CreateBank(c *fiber.Ctx) error {
+	req := new(requests.CreateQuestionBankRequest)
+	if ve, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
+	}
+
+	bank := &models.QuizQuestionBank{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := ac.adminRepo.CreateBank(bank); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to create question bank",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Question bank created successfully",
+		"data":    bank,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PUT /admin/question-banks/:bid
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) UpdateBank(c *fiber.Ctx) error {
+	bankID, err := parseID(c, "bid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid question bank ID",
+		})
+	}
+
+	req := new(requests.UpdateQuestionBankRequest)
+	if ve, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
+	}
+
+	bank := &models.QuizQuestionBank{
+		ID:          bankID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := ac.adminRepo.UpdateBank(bank); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Question bank not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to update question bank",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Question bank updated successfully",
+		"data":    bank,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// DELETE /admin/question-banks/:bid
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) DeleteBank(c *fiber.Ctx) error {
+	bankID, err := parseID(c, "bid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid question bank ID",
+		})
+	}
+
+	if err := ac.adminRepo.DeleteBank(bankID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Question bank not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to delete question bank",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Question bank deleted successfully",
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/quizzes/:id/sampling-rules
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) GetSamplingRules(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	rules, err := ac.adminRepo.ListSamplingRules(quizID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve sampling rules",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Sampling rules retrieved successfully",
+		"data":    rules,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PUT /admin/quizzes/:id/sampling-rules
+// ─────────────────────────────────────────────────────────────────────────────
+
+// UpdateSamplingRules mengganti seluruh aturan pengambilan soal kuis. Kirim
+// rules kosong untuk kembali memakai soal milik kuis saja.
+func (ac *QuizAdminController) UpdateSamplingRules(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	req := new(requests.UpdateSamplingRulesRequest)
+	if ve, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
+	}
+
+	quiz, _, err := ac.adminRepo.GetQuizDetail(quizID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz",
+			"error":   err.Error(),
+		})
+	}
+	if quiz == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found",
+		})
+	}
+
+	rules := make([]models.QuizSamplingRule, len(req.Rules))
+	ruleErrors := make(map[string]string)
+	for i, item := range req.Rules {
+		rules[i] = models.QuizSamplingRule{
+			BankID:        item.BankID,
+			Tag:           normalizeTag(item.Tag),
+			Difficulty:    item.Difficulty,
+			QuestionCount: item.QuestionCount,
+		}
+
+		available, err := ac.adminRepo.CountBankQuestions(item.BankID, rules[i].Tag, item.Difficulty)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Failed to check question bank",
+				"error":   err.Error(),
+			})
+		}
+
+		key := fmt.Sprintf("rules[%d]", i)
+		switch {
+		case available < 0:
+			ruleErrors[key] = "Question bank not found"
+		case available < item.QuestionCount:
+			ruleErrors[key] = fmt.Sprintf("Only %d matching questions are available in the bank", available)
+		}
+	}
+	if len(ruleErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ruleErrors,
+		})
+	}
+
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		return ac.adminRepo.WithExecutor(tx).ReplaceSamplingRules(quizID, rules)
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to update sampling rules",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Sampling rules updated successfully",
+		"data":    rules,
+	})
+}
+
+// normalizeTag menyamakan penulisan tag agar pencocokan tag tidak peka huruf besar/kecil.
+func normalizeTag(tag *string) *string {
+	if tag == nil {
+		return nil
+	}
+	normalized := strings.ToLower(strings.TrimSpace(*tag))
+	return &normalized
+}
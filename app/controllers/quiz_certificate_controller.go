@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/jobs"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /quiz/certificates/:code
+// ─────────────────────────────────────────────────────────────────────────────
+
+// VerifyCertificate lets anyone check a certificate by its verification code.
+func (qc *QuizController) VerifyCertificate(c *fiber.Ctx) error {
+	code := strings.ToUpper(strings.TrimSpace(c.Params("code")))
+
+	cert, err := qc.quizRepo.GetCertificateByCode(code)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to verify certificate",
+			"error":   err.Error(),
+		})
+	}
+	if cert == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Certificate not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Certificate is valid",
+		"data": fiber.Map{
+			"code":         cert.Code,
+			"student_name": cert.StudentName,
+			"quiz_title":   cert.QuizTitle,
+			"score":        cert.Score,
+			"issued_at":    cert.IssuedAt,
+			"file_path":    cert.FilePath,
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// issueCertificate menerbitkan sertifikat saat siswa pertama kali lulus dan
+// mengantrekan pembuatan PDF-nya. Kegagalannya hanya dicatat di log agar tidak
+// menggagalkan pengumpulan kuis.
+func (qc *QuizController) issueCertificate(c *fiber.Ctx, userID, quizID uint) {
+	cert, err := qc.quizRepo.IssueCertificate(userID, quizID)
+	if err != nil {
+		log.Printf("[QUIZ] Failed to issue certificate of quiz %d for user %d: %v", quizID, userID, err)
+		return
+	}
+	if cert == nil {
+		return
+	}
+	if err := jobs.EnqueueQuizCertificate(c.Context(), cert.ID); err != nil {
+		log.Printf("[QUIZ] Failed to queue certificate %d: %v", cert.ID, err)
+	}
+}
@@ -42,18 +42,24 @@ func (qc *QuizController) GetQuiz(c *fiber.Ctx) error {
 		})
 	}
 
+	attempt, err = qc.enforceTimeLimit(c, attempt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt time limit",
+			"error":   err.Error(),
+		})
+	}
+
 	if attempt == nil {
 		newAttempt := &models.QuizAttempt{
 			QuizID: uint(quizID),
 			UserID: userID,
 		}
 		if err := qc.quizRepo.CreateAttempt(newAttempt); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Failed to start quiz attempt",
-				"error":   err.Error(),
-			})
+			return qc.startAttemptError(c, err, userID, newAttempt.QuizID)
 		}
+		qc.recordAttemptEvent(c, newAttempt.ID, models.QuizEventAttemptStarted, nil)
 		attempt = newAttempt
 	}
 
@@ -73,6 +79,15 @@ func (qc *QuizController) GetQuiz(c *fiber.Ctx) error {
 	}
 
 	if attempt.Status == "completed" {
+		summary, err := qc.quizRepo.GetAttemptSummary(userID, quiz.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Failed to retrieve attempt policy",
+				"error":   err.Error(),
+			})
+		}
+
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
 			"status":  "success",
 			"message": "You have already completed this quiz",
@@ -82,16 +97,27 @@ func (qc *QuizController) GetQuiz(c *fiber.Ctx) error {
 					"title": quiz.Title,
 				},
 				"attempt": fiber.Map{
-					"id":           attempt.ID,
-					"status":       attempt.Status,
-					"score":        attempt.Score,
-					"submitted_at": attempt.SubmittedAt,
+					"id":             attempt.ID,
+					"status":         attempt.Status,
+					"score":          attempt.Score,
+					"submitted_at":   attempt.SubmittedAt,
+					"auto_submitted": attempt.AutoSubmitted,
 				},
-				"questions": nil,
+				"questions":      nil,
+				"attempt_policy": summary,
 			},
 		})
 	}
 
+	savedAnswers, err := qc.quizRepo.GetSavedAnswers(attempt.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve saved answers",
+			"error":   err.Error(),
+		})
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Quiz retrieved successfully",
@@ -104,11 +130,13 @@ func (qc *QuizController) GetQuiz(c *fiber.Ctx) error {
 				"passing_score":      quiz.PassingScore,
 			},
 			"attempt": fiber.Map{
-				"id":         attempt.ID,
-				"status":     attempt.Status,
-				"started_at": attempt.StartedAt,
+				"id":                attempt.ID,
+				"status":            attempt.Status,
+				"started_at":        attempt.StartedAt,
+				"expires_at":        attempt.ExpiresAt,
+				"remaining_seconds": attempt.RemainingSeconds,
 			},
-			"questions": buildQuestionsResponse(questions),
+			"questions": buildQuestionsResponse(questions, savedAnswers),
 		},
 	})
 }
@@ -148,18 +176,24 @@ func (qc *QuizController) GetQuizByCode(c *fiber.Ctx) error {
 		})
 	}
 
+	attempt, err = qc.enforceTimeLimit(c, attempt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt time limit",
+			"error":   err.Error(),
+		})
+	}
+
 	if attempt == nil {
 		newAttempt := &models.QuizAttempt{
 			QuizID: quizID,
 			UserID: userID,
 		}
 		if err := qc.quizRepo.CreateAttempt(newAttempt); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Failed to start quiz attempt",
-				"error":   err.Error(),
-			})
+			return qc.startAttemptError(c, err, userID, newAttempt.QuizID)
 		}
+		qc.recordAttemptEvent(c, newAttempt.ID, models.QuizEventAttemptStarted, nil)
 		attempt = newAttempt
 	}
 
@@ -179,6 +213,15 @@ func (qc *QuizController) GetQuizByCode(c *fiber.Ctx) error {
 	}
 
 	if attempt.Status == "completed" {
+		summary, err := qc.quizRepo.GetAttemptSummary(userID, quiz.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Failed to retrieve attempt policy",
+				"error":   err.Error(),
+			})
+		}
+
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
 			"status":  "success",
 			"message": "You have already completed this quiz",
@@ -189,16 +232,27 @@ func (qc *QuizController) GetQuizByCode(c *fiber.Ctx) error {
 					"title": quiz.Title,
 				},
 				"attempt": fiber.Map{
-					"id":           attempt.ID,
-					"status":       attempt.Status,
-					"score":        attempt.Score,
-					"submitted_at": attempt.SubmittedAt,
+					"id":             attempt.ID,
+					"status":         attempt.Status,
+					"score":          attempt.Score,
+					"submitted_at":   attempt.SubmittedAt,
+					"auto_submitted": attempt.AutoSubmitted,
 				},
-				"questions": nil,
+				"questions":      nil,
+				"attempt_policy": summary,
 			},
 		})
 	}
 
+	savedAnswers, err := qc.quizRepo.GetSavedAnswers(attempt.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve saved answers",
+			"error":   err.Error(),
+		})
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Quiz retrieved successfully",
@@ -212,11 +266,13 @@ func (qc *QuizController) GetQuizByCode(c *fiber.Ctx) error {
 				"passing_score":      quiz.PassingScore,
 			},
 			"attempt": fiber.Map{
-				"id":         attempt.ID,
-				"status":     attempt.Status,
-				"started_at": attempt.StartedAt,
+				"id":                attempt.ID,
+				"status":            attempt.Status,
+				"started_at":        attempt.StartedAt,
+				"expires_at":        attempt.ExpiresAt,
+				"remaining_seconds": attempt.RemainingSeconds,
 			},
-			"questions": buildQuestionsResponse(questions),
+			"questions": buildQuestionsResponse(questions, savedAnswers),
 		},
 	})
 }
@@ -264,27 +320,61 @@ func (qc *QuizController) SubmitQuiz(c *fiber.Ctx) error {
 	if attempt.Status == "completed" {
 		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 			"status":  "fail",
-			"message": "Quiz already submitted. You cannot re-submit unless reset by admin.",
+			"message": "Quiz already submitted. Start a new attempt if the quiz allows retakes.",
+		})
+	}
+
+	expiredAttempt, err := qc.quizRepo.FinalizeIfExpired(attempt.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt time limit",
+			"error":   err.Error(),
+		})
+	}
+	if expiredAttempt != nil {
+		qc.afterAttemptFinalized(c, expiredAttempt)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Time limit exceeded. Your attempt was submitted automatically with the answers saved before the deadline.",
+			"data": fiber.Map{
+				"attempt_id":     expiredAttempt.ID,
+				"score":          expiredAttempt.Score,
+				"raw_score":      expiredAttempt.RawScore,
+				"max_score":      expiredAttempt.MaxScore,
+				"submitted_at":   expiredAttempt.SubmittedAt,
+				"auto_submitted": expiredAttempt.AutoSubmitted,
+			},
 		})
 	}
 
 	answers := make([]models.QuizAnswer, len(req.Answers))
 	for i, a := range req.Answers {
-		answers[i] = models.QuizAnswer{
-			AttemptID:  attempt.ID,
-			QuestionID: a.QuestionID,
-			OptionID:   a.OptionID,
-		}
+		answers[i] = newQuizAnswer(attempt.ID, a)
 	}
 
-	completedAttempt, err := qc.quizRepo.SubmitAnswers(attempt.ID, answers)
+	completedAttempt, err := qc.quizRepo.SubmitAnswers(attempt, answers)
 	if err != nil {
+		if err == models.ErrQuizAttemptClosed {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Quiz already submitted. Start a new attempt if the quiz allows retakes.",
+			})
+		}
+		if err == models.ErrQuizInvalidAnswer {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "One of the answers does not fit its question or is not part of this attempt",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Failed to submit quiz",
 			"error":   err.Error(),
 		})
 	}
+	qc.recordAttemptEvent(c, completedAttempt.ID, models.QuizEventAttemptSubmitted, nil)
+	qc.afterAttemptFinalized(c, completedAttempt)
 
 	quiz, _, err := qc.quizRepo.GetActiveQuizWithQuestionsV2(uint(quizID), attempt)
 	if err != nil || quiz == nil {
@@ -294,17 +384,213 @@ func (qc *QuizController) SubmitQuiz(c *fiber.Ctx) error {
 		})
 	}
 
-	passed := completedAttempt.Score != nil && *completedAttempt.Score >= float64(quiz.PassingScore)
+	summary, err := qc.quizRepo.GetAttemptSummary(userID, quiz.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve attempt policy",
+			"error":   err.Error(),
+		})
+	}
+
+	passed := summary.FinalScore != nil && *summary.FinalScore >= float64(quiz.PassingScore)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Quiz submitted successfully",
 		"data": fiber.Map{
-			"attempt_id":    completedAttempt.ID,
-			"score":         completedAttempt.Score,
-			"passing_score": quiz.PassingScore,
-			"passed":        passed,
-			"submitted_at":  completedAttempt.SubmittedAt,
+			"attempt_id":     completedAttempt.ID,
+			"score":          completedAttempt.Score,
+			"raw_score":      completedAttempt.RawScore,
+			"max_score":      completedAttempt.MaxScore,
+			"final_score":    summary.FinalScore,
+			"passing_score":  quiz.PassingScore,
+			"passed":         passed,
+			"submitted_at":   completedAttempt.SubmittedAt,
+			"attempt_policy": summary,
+		},
+	})
+}
+
+func (qc *QuizController) SaveAnswer(c *fiber.Ctx) error {
+	quizID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	userID := uint(c.Locals("userID").(int))
+
+	req := new(requests.SubmitAnswerItem)
+	if validationErrors, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationErrors,
+		})
+	}
+
+	attempt, err := qc.quizRepo.GetActiveAttempt(userID, uint(quizID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt",
+			"error":   err.Error(),
+		})
+	}
+
+	attempt, err = qc.enforceTimeLimit(c, attempt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt time limit",
+			"error":   err.Error(),
+		})
+	}
+	if attempt == nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "No active attempt found. Please start the quiz first.",
+		})
+	}
+	if attempt.Status == "completed" {
+		message := "Quiz already submitted. Answers can no longer be changed."
+		if attempt.AutoSubmitted {
+			message = "Time limit exceeded. Your attempt was submitted automatically."
+		}
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": message,
+		})
+	}
+
+	if !attempt.HasQuestion(req.QuestionID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Question is not part of this attempt",
+		})
+	}
+
+	answer := newQuizAnswer(attempt.ID, *req)
+	if err := qc.quizRepo.SaveAnswer(&answer); err != nil {
+		if err == models.ErrQuizInvalidAnswer {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Answer does not fit this question",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to save answer",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Answer saved successfully",
+		"data": fiber.Map{
+			"attempt_id":         attempt.ID,
+			"question_id":        answer.QuestionID,
+			"selected_option_id": answer.OptionID,
+			"saved_answer":       savedAnswerResponse(&answer),
+			"remaining_seconds":  attempt.RemainingSeconds,
+		},
+	})
+}
+
+// ClearAnswer takes back the saved answer to a question, so a student unsure
+// of it can leave it blank instead of risking its penalty points.
+func (qc *QuizController) ClearAnswer(c *fiber.Ctx) error {
+	quizID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	questionID, err := strconv.ParseUint(c.Params("qid"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid question ID",
+		})
+	}
+
+	userID := uint(c.Locals("userID").(int))
+
+	attempt, err := qc.quizRepo.GetActiveAttempt(userID, uint(quizID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt",
+			"error":   err.Error(),
+		})
+	}
+
+	attempt, err = qc.enforceTimeLimit(c, attempt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt time limit",
+			"error":   err.Error(),
+		})
+	}
+	if attempt == nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "No active attempt found. Please start the quiz first.",
+		})
+	}
+	if attempt.Status == "completed" {
+		message := "Quiz already submitted. Answers can no longer be changed."
+		if attempt.AutoSubmitted {
+			message = "Time limit exceeded. Your attempt was submitted automatically."
+		}
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": message,
+		})
+	}
+
+	if !attempt.HasQuestion(uint(questionID)) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Question is not part of this attempt",
+		})
+	}
+
+	cleared, err := qc.quizRepo.ClearAnswer(attempt.ID, uint(questionID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to clear answer",
+			"error":   err.Error(),
+		})
+	}
+	if !cleared {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "No saved answer for this question",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Answer cleared successfully",
+		"data": fiber.Map{
+			"attempt_id":        attempt.ID,
+			"question_id":       uint(questionID),
+			"remaining_seconds": attempt.RemainingSeconds,
 		},
 	})
 }
@@ -328,13 +614,31 @@ func (qc *QuizController) GetQuizStatus(c *fiber.Ctx) error {
 			"error":   err.Error(),
 		})
 	}
+	attempt, err = qc.enforceTimeLimit(c, attempt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt time limit",
+			"error":   err.Error(),
+		})
+	}
+	summary, err := qc.quizRepo.GetAttemptSummary(userID, uint(quizID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve attempt policy",
+			"error":   err.Error(),
+		})
+	}
+
 	if attempt == nil {
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
 			"status":  "success",
 			"message": "No attempt found",
 			"data": fiber.Map{
-				"has_attempt": false,
-				"attempt":     nil,
+				"has_attempt":    false,
+				"attempt":        nil,
+				"attempt_policy": summary,
 			},
 		})
 	}
@@ -343,13 +647,19 @@ func (qc *QuizController) GetQuizStatus(c *fiber.Ctx) error {
 		"status":  "success",
 		"message": "Attempt status retrieved",
 		"data": fiber.Map{
-			"has_attempt": true,
+			"has_attempt":    true,
+			"attempt_policy": summary,
 			"attempt": fiber.Map{
-				"id":           attempt.ID,
-				"status":       attempt.Status,
-				"score":        attempt.Score,
-				"started_at":   attempt.StartedAt,
-				"submitted_at": attempt.SubmittedAt,
+				"id":                attempt.ID,
+				"status":            attempt.Status,
+				"score":             attempt.Score,
+				"raw_score":         attempt.RawScore,
+				"max_score":         attempt.MaxScore,
+				"started_at":        attempt.StartedAt,
+				"submitted_at":      attempt.SubmittedAt,
+				"expires_at":        attempt.ExpiresAt,
+				"remaining_seconds": attempt.RemainingSeconds,
+				"auto_submitted":    attempt.AutoSubmitted,
 			},
 		},
 	})
@@ -395,6 +705,7 @@ func (qc *QuizController) ResetAttempt(c *fiber.Ctx) error {
 			"error":   err.Error(),
 		})
 	}
+	invalidateLeaderboard(c, uint(quizID))
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
@@ -402,25 +713,237 @@ func (qc *QuizController) ResetAttempt(c *fiber.Ctx) error {
 	})
 }
 
-func buildQuestionsResponse(questions []models.QuizQuestion) []fiber.Map {
+// StartAttempt starts a new attempt after a completed one, when the quiz's
+// attempt policy allows it.
+func (qc *QuizController) StartAttempt(c *fiber.Ctx) error {
+	quizID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	userID := uint(c.Locals("userID").(int))
+
+	quiz, _, err := qc.quizRepo.GetActiveQuizWithQuestions(uint(quizID), nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz",
+			"error":   err.Error(),
+		})
+	}
+	if quiz == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found or inactive",
+		})
+	}
+
+	attempt, err := qc.quizRepo.GetActiveAttempt(userID, quiz.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt status",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := qc.enforceTimeLimit(c, attempt); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt time limit",
+			"error":   err.Error(),
+		})
+	}
+
+	newAttempt := &models.QuizAttempt{
+		QuizID: quiz.ID,
+		UserID: userID,
+	}
+	if err := qc.quizRepo.CreateAttempt(newAttempt); err != nil {
+		return qc.startAttemptError(c, err, userID, quiz.ID)
+	}
+	qc.recordAttemptEvent(c, newAttempt.ID, models.QuizEventAttemptStarted, nil)
+
+	summary, err := qc.quizRepo.GetAttemptSummary(userID, quiz.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve attempt policy",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Quiz attempt started",
+		"data": fiber.Map{
+			"attempt": fiber.Map{
+				"id":                newAttempt.ID,
+				"status":            newAttempt.Status,
+				"started_at":        newAttempt.StartedAt,
+				"expires_at":        newAttempt.ExpiresAt,
+				"remaining_seconds": newAttempt.RemainingSeconds,
+			},
+			"attempt_policy": summary,
+		},
+	})
+}
+
+// startAttemptError maps a CreateAttempt failure to a response, including the
+// student's attempt policy standing when the policy refused the attempt.
+func (qc *QuizController) startAttemptError(c *fiber.Ctx, err error, userID, quizID uint) error {
+	var status int
+	var message string
+	switch err {
+	case models.ErrQuizNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found or inactive",
+		})
+	case models.ErrQuizNotAssigned:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "This quiz is not assigned to your class",
+		})
+	case models.ErrQuizNotOpen:
+		status, message = fiber.StatusForbidden, "This quiz is not open yet"
+	case models.ErrQuizClosed:
+		status, message = fiber.StatusForbidden, "This quiz is closed"
+	case models.ErrQuizAttemptInProgress:
+		status, message = fiber.StatusConflict, "You already have an attempt in progress for this quiz"
+	case models.ErrQuizMaxAttemptsReached:
+		status, message = fiber.StatusForbidden, "You have used all attempts for this quiz"
+	case models.ErrQuizAttemptCooldown:
+		status, message = fiber.StatusTooManyRequests, "Please wait before starting another attempt"
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to start quiz attempt",
+			"error":   err.Error(),
+		})
+	}
+
+	summary, err := qc.quizRepo.GetAttemptSummary(userID, quizID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve attempt policy",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"status":  "fail",
+		"message": message,
+		"data": fiber.Map{
+			"attempt_policy": summary,
+		},
+	})
+}
+
+// afterAttemptFinalized dijalankan setiap kali sebuah percobaan selesai lewat
+// request, baik dikumpulkan siswa maupun dikumpulkan otomatis karena waktunya
+// habis: cache papan peringkat kuisnya dibuang dan sertifikat diterbitkan bila
+// siswa lulus. Percobaan yang diselesaikan job latar belakang ditangani di
+// handleFinalizeExpiredAttempts.
+func (qc *QuizController) afterAttemptFinalized(c *fiber.Ctx, attempt *models.QuizAttempt) {
+	invalidateLeaderboard(c, attempt.QuizID)
+	qc.issueCertificate(c, attempt.UserID, attempt.QuizID)
+}
+
+// enforceTimeLimit auto-submits an in-progress attempt whose deadline has
+// passed and returns the attempt as it stands afterwards.
+func (qc *QuizController) enforceTimeLimit(c *fiber.Ctx, attempt *models.QuizAttempt) (*models.QuizAttempt, error) {
+	if attempt == nil || attempt.Status != "in_progress" || attempt.ExpiresAt == nil {
+		return attempt, nil
+	}
+
+	expiredAttempt, err := qc.quizRepo.FinalizeIfExpired(attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	if expiredAttempt != nil {
+		qc.afterAttemptFinalized(c, expiredAttempt)
+		return expiredAttempt, nil
+	}
+
+	return attempt, nil
+}
+
+func buildQuestionsResponse(questions []models.QuizQuestion, savedAnswers map[uint]*models.QuizAnswer) []fiber.Map {
 	result := make([]fiber.Map, len(questions))
 	for i, q := range questions {
-		options := make([]fiber.Map, len(q.Options))
-		for j, o := range q.Options {
-			options[j] = fiber.Map{
-				"id":          o.ID,
-				"option_text": o.OptionText,
-			}
-		}
-		result[i] = fiber.Map{
-			"id":            q.ID,
-			"question_text": q.QuestionText,
-			"options":       options,
-		}
+		result[i] = buildQuestionResponse(q, savedAnswers[q.ID])
 	}
 	return result
 }
 
+// buildQuestionResponse builds the student view of a question. Correct
+// options and answer keys are never included.
+func buildQuestionResponse(q models.QuizQuestion, saved *models.QuizAnswer) fiber.Map {
+	options := make([]fiber.Map, len(q.Options))
+	for j, o := range q.Options {
+		options[j] = fiber.Map{
+			"id":          o.ID,
+			"option_text": o.OptionText,
+			"text_format": o.TextFormat,
+			"attachments": attachmentsOrEmpty(o.Attachments),
+		}
+	}
+
+	var selectedOptionID *uint
+	if saved != nil {
+		selectedOptionID = saved.OptionID
+	}
+
+	return fiber.Map{
+		"id":                 q.ID,
+		"question_text":      q.QuestionText,
+		"text_format":        q.TextFormat,
+		"question_type":      q.QuestionType,
+		"points":             q.Points,
+		"penalty_points":     q.PenaltyPoints,
+		"options":            options,
+		"attachments":        attachmentsOrEmpty(q.Attachments),
+		"selected_option_id": selectedOptionID,
+		"saved_answer":       savedAnswerResponse(saved),
+	}
+}
+
+// savedAnswerResponse returns the student's saved answer without grading
+// details, or nil when the question has not been answered yet.
+func savedAnswerResponse(answer *models.QuizAnswer) fiber.Map {
+	if answer == nil {
+		return nil
+	}
+	optionIDs := make([]int64, 0, len(answer.OptionIDs))
+	optionIDs = append(optionIDs, answer.OptionIDs...)
+	return fiber.Map{
+		"option_id":      answer.OptionID,
+		"option_ids":     optionIDs,
+		"text_answer":    answer.TextAnswer,
+		"numeric_answer": answer.NumericAnswer,
+	}
+}
+
+// newQuizAnswer maps a submitted answer onto the model; grading happens in
+// the repository.
+func newQuizAnswer(attemptID uint, item requests.SubmitAnswerItem) models.QuizAnswer {
+	answer := models.QuizAnswer{
+		AttemptID:     attemptID,
+		QuestionID:    item.QuestionID,
+		OptionID:      item.OptionID,
+		TextAnswer:    item.TextAnswer,
+		NumericAnswer: item.NumericAnswer,
+	}
+	for _, id := range item.OptionIDs {
+		answer.OptionIDs = append(answer.OptionIDs, int64(id))
+	}
+	return answer
+}
+
 func (qc *QuizController) GetCurrentQuestion(c *fiber.Ctx) error {
 	quizID, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
@@ -438,13 +961,19 @@ func (qc *QuizController) GetCurrentQuestion(c *fiber.Ctx) error {
 		})
 	}
 
+	attempt, err = qc.enforceTimeLimit(c, attempt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to check attempt time limit",
+		})
+	}
+
 	if attempt == nil {
 		attempt = &models.QuizAttempt{QuizID: uint(quizID), UserID: userID}
 		if err := qc.quizRepo.CreateAttempt(attempt); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"status": "error", "message": "Failed to start quiz attempt",
-			})
+			return qc.startAttemptError(c, err, userID, uint(quizID))
 		}
+		qc.recordAttemptEvent(c, attempt.ID, models.QuizEventAttemptStarted, nil)
 	}
 
 	if attempt.Status == "completed" {
@@ -480,6 +1009,12 @@ func (qc *QuizController) navigateQuestion(c *fiber.Ctx, direction int) error {
 			"status": "error", "message": "Failed to get attempt",
 		})
 	}
+	attempt, err = qc.enforceTimeLimit(c, attempt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to check attempt time limit",
+		})
+	}
 	if attempt == nil {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"status": "fail", "message": "No active attempt. Please start the quiz first.",
@@ -512,6 +1047,10 @@ func (qc *QuizController) navigateQuestion(c *fiber.Ctx, direction int) error {
 			"status": "error", "message": "Failed to update question index",
 		})
 	}
+	qc.recordAttemptEvent(c, attempt.ID, models.QuizEventQuestionNavigated, map[string]any{
+		"from": attempt.CurrentQuestionIndex,
+		"to":   newIndex,
+	})
 	attempt.CurrentQuestionIndex = newIndex
 
 	return qc.returnQuestionAtIndex(c, attempt, newIndex)
@@ -531,12 +1070,11 @@ func (qc *QuizController) returnQuestionAtIndex(c *fiber.Ctx, attempt *models.Qu
 		})
 	}
 
-	options := make([]fiber.Map, len(question.Options))
-	for i, o := range question.Options {
-		options[i] = fiber.Map{
-			"id":          o.ID,
-			"option_text": o.OptionText,
-		}
+	savedAnswers, err := qc.quizRepo.GetSavedAnswers(attempt.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status": "error", "message": "Failed to retrieve saved answers",
+		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -549,15 +1087,71 @@ func (qc *QuizController) returnQuestionAtIndex(c *fiber.Ctx, attempt *models.Qu
 				"has_prev":        index > 0,
 				"has_next":        index < total-1,
 			},
-			"question": fiber.Map{
-				"id":            question.ID,
-				"question_text": question.QuestionText,
-				"options":       options,
+			"attempt": fiber.Map{
+				"id":                attempt.ID,
+				"expires_at":        attempt.ExpiresAt,
+				"remaining_seconds": attempt.RemainingSeconds,
 			},
+			"question": buildQuestionResponse(*question, savedAnswers[question.ID]),
 		},
 	})
 }
 
+// ListQuizzes returns the quizzes assigned to the student's classes, grouped
+// into upcoming, open and closed.
+func (qc *QuizController) ListQuizzes(c *fiber.Ctx) error {
+	userID := uint(c.Locals("userID").(int))
+
+	quizzes, err := qc.quizRepo.ListStudentQuizzes(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quizzes",
+			"error":   err.Error(),
+		})
+	}
+
+	summaries, err := qc.quizRepo.GetStudentAttemptSummaries(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quizzes",
+			"error":   err.Error(),
+		})
+	}
+	summaryByQuiz := make(map[uint]*models.QuizAttemptSummary, len(summaries))
+	for _, summary := range summaries {
+		summaryByQuiz[summary.QuizID] = summary
+	}
+
+	grouped := fiber.Map{
+		models.QuizAvailabilityUpcoming: make([]fiber.Map, 0),
+		models.QuizAvailabilityOpen:     make([]fiber.Map, 0),
+		models.QuizAvailabilityClosed:   make([]fiber.Map, 0),
+	}
+	for _, quiz := range quizzes {
+		item := fiber.Map{
+			"id":                 quiz.ID,
+			"code":               quiz.Code,
+			"title":              quiz.Title,
+			"description":        quiz.Description,
+			"passing_score":      quiz.PassingScore,
+			"time_limit_minutes": quiz.TimeLimitMinutes,
+			"opens_at":           quiz.OpensAt,
+			"closes_at":          quiz.ClosesAt,
+			"availability":       quiz.Availability,
+			"attempt_policy":     summaryByQuiz[quiz.ID],
+		}
+		grouped[quiz.Availability] = append(grouped[quiz.Availability].([]fiber.Map), item)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Quizzes retrieved successfully",
+		"data":    grouped,
+	})
+}
+
 func (qc *QuizController) GetStudentQuizHistories(c *fiber.Ctx) error {
 	userID := uint(c.Locals("userID").(int))
 
@@ -570,6 +1164,15 @@ func (qc *QuizController) GetStudentQuizHistories(c *fiber.Ctx) error {
 		})
 	}
 
+	summaries, err := qc.quizRepo.GetStudentAttemptSummaries(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz histories",
+			"error":   err.Error(),
+		})
+	}
+
 	histories := make([]fiber.Map, len(attempts))
 	for i, attempt := range attempts {
 		history := fiber.Map{
@@ -596,7 +1199,150 @@ func (qc *QuizController) GetStudentQuizHistories(c *fiber.Ctx) error {
 		"message": "Quiz histories retrieved successfully",
 		"data": fiber.Map{
 			"histories": histories,
+			"summaries": summaries,
 			"total":     len(histories),
 		},
 	})
 }
+
+// ReviewAttempt returns a submitted attempt with the student's answers next
+// to the answer key and explanations, when the quiz's review policy allows it.
+func (qc *QuizController) ReviewAttempt(c *fiber.Ctx) error {
+	attemptID, err := strconv.ParseUint(c.Params("aid"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid attempt ID",
+		})
+	}
+
+	userID := uint(c.Locals("userID").(int))
+
+	quiz, attempt, answers, questions, err := qc.quizRepo.GetAttemptReview(uint(attemptID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve attempt review",
+			"error":   err.Error(),
+		})
+	}
+	if quiz == nil || attempt.UserID != userID {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Attempt not found",
+		})
+	}
+	if attempt.Status != "completed" {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Only submitted attempts can be reviewed",
+		})
+	}
+	if !quiz.ReviewAvailable() {
+		message := "Answer review is not available for this quiz"
+		if quiz.ReviewPolicy == models.ReviewPolicyAfterClose {
+			message = "Answer review will be available after the quiz closes"
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": message,
+			"data": fiber.Map{
+				"review_policy": quiz.ReviewPolicy,
+			},
+		})
+	}
+
+	answersByQuestion := make(map[uint]*models.QuizAnswer, len(answers))
+	for i := range answers {
+		answersByQuestion[answers[i].QuestionID] = &answers[i]
+	}
+
+	reviewQuestions := make([]fiber.Map, len(questions))
+	for i, q := range questions {
+		reviewQuestions[i] = buildReviewQuestionResponse(q, answersByQuestion[q.ID])
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Attempt review retrieved successfully",
+		"data": fiber.Map{
+			"quiz": fiber.Map{
+				"id":            quiz.ID,
+				"title":         quiz.Title,
+				"passing_score": quiz.PassingScore,
+			},
+			"attempt": fiber.Map{
+				"id":             attempt.ID,
+				"status":         attempt.Status,
+				"score":          attempt.Score,
+				"raw_score":      attempt.RawScore,
+				"max_score":      attempt.MaxScore,
+				"started_at":     attempt.StartedAt,
+				"submitted_at":   attempt.SubmittedAt,
+				"auto_submitted": attempt.AutoSubmitted,
+			},
+			"questions": reviewQuestions,
+		},
+	})
+}
+
+// buildReviewQuestionResponse shows one question with the student's answer,
+// the grading result and the answer key. answer is nil for blank questions.
+func buildReviewQuestionResponse(q models.QuizQuestion, answer *models.QuizAnswer) fiber.Map {
+	selected := make(map[uint]bool)
+	if answer != nil {
+		if answer.OptionID != nil {
+			selected[*answer.OptionID] = true
+		}
+		for _, id := range answer.OptionIDs {
+			selected[uint(id)] = true
+		}
+	}
+
+	options := make([]fiber.Map, len(q.Options))
+	for j, o := range q.Options {
+		options[j] = fiber.Map{
+			"id":          o.ID,
+			"option_text": o.OptionText,
+			"text_format": o.TextFormat,
+			"is_correct":  o.IsCorrect,
+			"selected":    selected[o.ID],
+			"explanation": o.Explanation,
+			"attachments": attachmentsOrEmpty(o.Attachments),
+		}
+	}
+
+	result := fiber.Map{
+		"id":             q.ID,
+		"question_text":  q.QuestionText,
+		"text_format":    q.TextFormat,
+		"question_type":  q.QuestionType,
+		"explanation":    q.Explanation,
+		"points":         q.Points,
+		"penalty_points": q.PenaltyPoints,
+		"options":        options,
+		"attachments":    attachmentsOrEmpty(q.Attachments),
+		"answered":       answer != nil,
+		"your_answer":    savedAnswerResponse(answer),
+		"is_correct":     false,
+		"credit":         0.0,
+		"points_awarded": 0.0,
+	}
+	if answer != nil {
+		result["is_correct"] = answer.IsCorrect
+		result["credit"] = answer.Credit
+		result["points_awarded"] = answer.PointsAwarded
+	}
+
+	switch q.QuestionType {
+	case models.QuestionTypeShortAnswer:
+		acceptedAnswers := make([]string, 0, len(q.AcceptedAnswers))
+		acceptedAnswers = append(acceptedAnswers, q.AcceptedAnswers...)
+		result["accepted_answers"] = acceptedAnswers
+	case models.QuestionTypeNumeric:
+		result["numeric_answer"] = q.NumericAnswer
+		result["numeric_tolerance"] = q.NumericTolerance
+	}
+
+	return result
+}
@@ -0,0 +1,326 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/database"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/quizio"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+const (
+	maxQuizImportSize   = int64(2 * 1024 * 1024)
+	defaultPassingScore = 70
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /admin/quizzes/import
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) ImportQuiz(c *fiber.Ctx) error {
+	req := new(requests.ImportQuizRequest)
+	if ve, err := validator.ValidateFormData(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz file is required",
+			"error":   err.Error(),
+		})
+	}
+	if file.Size > maxQuizImportSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz file size exceeds the limit of 2MB",
+		})
+	}
+
+	format := req.Format
+	if format == "" {
+		format = quizio.FormatFromFilename(file.Filename)
+	}
+	if format == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Unknown quiz file format, use json, csv or gift",
+		})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to read quiz file",
+			"error":   err.Error(),
+		})
+	}
+	defer src.Close()
+
+	doc, rowErrors, err := quizio.Decode(format, src)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse quiz file",
+			"error":   err.Error(),
+		})
+	}
+
+	if req.Title != nil {
+		doc.Title = *req.Title
+	}
+	if doc.Title == "" {
+		doc.Title = strings.TrimSuffix(filepath.Base(file.Filename), filepath.Ext(file.Filename))
+	}
+	if req.Description != nil {
+		doc.Description = req.Description
+	}
+	if doc.PassingScore == 0 {
+		doc.PassingScore = defaultPassingScore
+	}
+
+	quizReq := quizRequestFromDocument(doc)
+	for field, message := range validator.ValidateStruct(&quizReq) {
+		rowErrors = append(rowErrors, quizio.RowError{Field: field, Message: message})
+	}
+	rowErrors = append(rowErrors, doc.Validate()...)
+
+	quiz := newQuizFromRequest(quizReq)
+	questions := make([]*models.QuizQuestion, len(doc.Questions))
+	for i, item := range doc.Questions {
+		questions[i] = newQuestionFromRequest(models.QuizQuestionOwner{}, questionRequestFromDocument(item))
+		if err := questions[i].ValidateConfig(); err != nil {
+			rowErrors = append(rowErrors, quizio.RowError{Row: item.Row, Message: err.Error()})
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "The quiz file has invalid rows",
+			"errors":  rowErrors,
+		})
+	}
+
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		adminRepo := ac.adminRepo.WithExecutor(tx)
+		if err := adminRepo.CreateQuiz(quiz); err != nil {
+			return err
+		}
+
+		for i, question := range questions {
+			question.QuizID = &quiz.ID
+			if err := adminRepo.CreateQuestion(question); err != nil {
+				return fmt.Errorf("row %d: %w", doc.Questions[i].Row, err)
+			}
+
+			item := doc.Questions[i]
+			if question.QuestionType == models.QuestionTypeTrueFalse {
+				if _, err := adminRepo.SetTrueFalseOptions(question.ID, *item.CorrectAnswer); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, opt := range item.Options {
+				if err := adminRepo.CreateOption(&models.QuizOption{
+					QuestionID:  question.ID,
+					OptionText:  opt.OptionText,
+					TextFormat:  opt.TextFormat,
+					IsCorrect:   opt.IsCorrect,
+					Explanation: opt.Explanation,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to import quiz",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Quiz imported successfully",
+		"data": fiber.Map{
+			"quiz":           quiz,
+			"question_count": len(questions),
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/quizzes/:id/export?format=json|csv|gift
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) ExportQuiz(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	format := strings.ToLower(c.Query("format", quizio.FormatJSON))
+	if format != quizio.FormatJSON && format != quizio.FormatCSV && format != quizio.FormatGIFT {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Unknown export format, use json, csv or gift",
+		})
+	}
+
+	quiz, questions, err := ac.adminRepo.GetQuizDetail(quizID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz",
+			"error":   err.Error(),
+		})
+	}
+	if quiz == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found",
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := quizio.Encode(format, &buf, documentFromQuiz(quiz, questions)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to export quiz",
+			"error":   err.Error(),
+		})
+	}
+
+	c.Attachment(fmt.Sprintf("quiz-%s.%s", quiz.Code, format))
+	if format == quizio.FormatGIFT {
+		c.Type("txt", "utf-8")
+	}
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// quizRequestFromDocument memetakan pengaturan kuis dari file import ke request pembuatan kuis.
+func quizRequestFromDocument(doc *quizio.Document) requests.CreateQuizRequest {
+	return requests.CreateQuizRequest{
+		Title:            doc.Title,
+		Description:      doc.Description,
+		PassingScore:     doc.PassingScore,
+		TimeLimitMinutes: doc.TimeLimitMinutes,
+		MaxAttempts:      doc.MaxAttempts,
+		CooldownMinutes:  doc.CooldownMinutes,
+		ScorePolicy:      doc.ScorePolicy,
+		ReviewPolicy:     doc.ReviewPolicy,
+		IsActive:         doc.IsActive,
+	}
+}
+
+// questionRequestFromDocument memetakan satu soal dari file import ke request pembuatan soal.
+func questionRequestFromDocument(item quizio.Question) requests.CreateQuestionRequest {
+	return requests.CreateQuestionRequest{
+		QuestionText:     item.QuestionText,
+		TextFormat:       item.TextFormat,
+		QuestionType:     item.QuestionType,
+		ScoringMode:      item.ScoringMode,
+		MatchMode:        item.MatchMode,
+		AcceptedAnswers:  item.AcceptedAnswers,
+		NumericAnswer:    item.NumericAnswer,
+		NumericTolerance: item.NumericTolerance,
+		CorrectAnswer:    item.CorrectAnswer,
+		Tags:             item.Tags,
+		Difficulty:       item.Difficulty,
+		Points:           item.Points,
+		PenaltyPoints:    item.PenaltyPoints,
+		Explanation:      item.Explanation,
+	}
+}
+
+// documentFromQuiz membangun dokumen export dari detail kuis, dengan bentuk yang bisa di-import ulang.
+func documentFromQuiz(quiz *models.QuizQuiz, questions []models.QuizQuestion) *quizio.Document {
+	// Di request, max_attempts 0 berarti tanpa batas sedangkan kosong berarti 1.
+	maxAttempts := 0
+	if quiz.MaxAttempts != nil {
+		maxAttempts = *quiz.MaxAttempts
+	}
+
+	doc := &quizio.Document{
+		Title:            quiz.Title,
+		Description:      quiz.Description,
+		PassingScore:     quiz.PassingScore,
+		TimeLimitMinutes: quiz.TimeLimitMinutes,
+		MaxAttempts:      &maxAttempts,
+		CooldownMinutes:  quiz.CooldownMinutes,
+		ScorePolicy:      quiz.ScorePolicy,
+		ReviewPolicy:     quiz.ReviewPolicy,
+		IsActive:         quiz.IsActive,
+		Questions:        make([]quizio.Question, len(questions)),
+	}
+
+	for i, q := range questions {
+		item := quizio.Question{
+			QuestionText:     q.QuestionText,
+			TextFormat:       q.TextFormat,
+			QuestionType:     q.QuestionType,
+			ScoringMode:      q.ScoringMode,
+			MatchMode:        q.MatchMode,
+			AcceptedAnswers:  q.AcceptedAnswers,
+			NumericAnswer:    q.NumericAnswer,
+			NumericTolerance: q.NumericTolerance,
+			Tags:             q.Tags,
+			Difficulty:       q.Difficulty,
+			Explanation:      q.Explanation,
+			Points:           q.Points,
+			PenaltyPoints:    q.PenaltyPoints,
+		}
+
+		if q.QuestionType == models.QuestionTypeTrueFalse {
+			correct := false
+			for _, o := range q.Options {
+				if o.OptionText == "True" {
+					correct = o.IsCorrect
+				}
+			}
+			item.CorrectAnswer = &correct
+		} else {
+			for _, o := range q.Options {
+				item.Options = append(item.Options, quizio.Option{
+					OptionText:  o.OptionText,
+					TextFormat:  o.TextFormat,
+					IsCorrect:   o.IsCorrect,
+					Explanation: o.Explanation,
+				})
+			}
+		}
+
+		doc.Questions[i] = item
+	}
+	return doc
+}
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /quiz/:id/events
+// ─────────────────────────────────────────────────────────────────────────────
+
+// RecordEvents stores the proctoring events the client reports during an
+// in-progress attempt, such as focus loss or tab switches.
+func (qc *QuizController) RecordEvents(c *fiber.Ctx) error {
+	quizID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	userID := uint(c.Locals("userID").(int))
+
+	req := new(requests.RecordQuizEventsRequest)
+	if validationErrors, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationErrors,
+		})
+	}
+
+	attempt, err := qc.quizRepo.GetActiveAttempt(userID, uint(quizID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt",
+			"error":   err.Error(),
+		})
+	}
+	attempt, err = qc.enforceTimeLimit(c, attempt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to check attempt time limit",
+			"error":   err.Error(),
+		})
+	}
+	if attempt == nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "No active attempt. Please start the quiz first.",
+		})
+	}
+	if attempt.Status == "completed" {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz already completed",
+		})
+	}
+
+	now := time.Now()
+	events := make([]models.QuizAttemptEvent, len(req.Events))
+	for i, item := range req.Events {
+		events[i] = newAttemptEvent(c, attempt.ID, item.EventType, item.Details)
+		// Waktu dari klien hanya dipakai jika masih di dalam rentang attempt.
+		if item.OccurredAt != nil && !item.OccurredAt.Before(attempt.StartedAt) && !item.OccurredAt.After(now) {
+			events[i].OccurredAt = *item.OccurredAt
+		}
+	}
+
+	if err := qc.quizRepo.RecordAttemptEvents(events); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to record quiz events",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Quiz events recorded successfully",
+		"data": fiber.Map{
+			"recorded": len(events),
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/quizzes/:id/attempts/:aid/events
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) ListAttemptEvents(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+	attemptID, err := parseID(c, "aid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid attempt ID",
+		})
+	}
+
+	events, err := ac.adminRepo.ListAttemptEvents(quizID, attemptID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve attempt events",
+			"error":   err.Error(),
+		})
+	}
+	if events == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Attempt not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Attempt events retrieved successfully",
+		"data":    events,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// newAttemptEvent membuat event attempt dengan IP dan User-Agent dari request.
+func newAttemptEvent(c *fiber.Ctx, attemptID uint, eventType string, details map[string]any) models.QuizAttemptEvent {
+	event := models.QuizAttemptEvent{
+		AttemptID:  attemptID,
+		EventType:  eventType,
+		OccurredAt: time.Now(),
+	}
+	if len(details) > 0 {
+		event.Details, _ = json.Marshal(details)
+	}
+	if ip := c.IP(); ip != "" {
+		event.IPAddress = &ip
+	}
+	if userAgent := c.Get(fiber.HeaderUserAgent); userAgent != "" {
+		event.UserAgent = &userAgent
+	}
+	return event
+}
+
+// recordAttemptEvent mencatat event dari sisi server. Kegagalannya hanya dicatat di log
+// agar tidak menggagalkan pengerjaan kuis.
+func (qc *QuizController) recordAttemptEvent(c *fiber.Ctx, attemptID uint, eventType string, details map[string]any) {
+	events := []models.QuizAttemptEvent{newAttemptEvent(c, attemptID, eventType, details)}
+	if err := qc.quizRepo.RecordAttemptEvents(events); err != nil {
+		log.Printf("[QUIZ] Failed to record %s event for attempt %d: %v", eventType, attemptID, err)
+	}
+}
@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	publicLeaderboardLimit  = 10
+)
+
+var leaderboardWindows = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /quiz/:id/leaderboard
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetLeaderboard ranks the students of a quiz the user may take. Names of
+// other students are hidden when the quiz is anonymized.
+func (qc *QuizController) GetLeaderboard(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	userID := uint(c.Locals("userID").(int))
+
+	filter, limit, validationErrors, err := parseLeaderboardQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse query parameters",
+			"error":   err.Error(),
+		})
+	} else if len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationErrors,
+		})
+	}
+
+	quiz, err := qc.quizRepo.GetAssignedQuiz(userID, quizID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz",
+			"error":   err.Error(),
+		})
+	}
+	if quiz == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found",
+		})
+	}
+	if quiz.LeaderboardOptOut {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Leaderboard is disabled for this quiz",
+		})
+	}
+
+	entries, err := qc.quizRepo.GetLeaderboard(c.Context(), quiz.ID, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve leaderboard",
+			"error":   err.Error(),
+		})
+	}
+
+	var currentUser *models.QuizLeaderboardEntry
+	for i := range entries {
+		if entries[i].UserID == userID {
+			entries[i].IsCurrentUser = true
+			entry := entries[i]
+			currentUser = &entry
+		}
+	}
+	if quiz.LeaderboardAnonymous {
+		models.AnonymizeLeaderboard(entries, userID)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Leaderboard retrieved successfully",
+		"data":    leaderboardResponse(quiz, entries, limit, currentUser),
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /quiz/leaderboards/:code
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetPublicLeaderboard shows the top 10 of a quiz that opened its
+// leaderboard to the public, such as a tryout event.
+func (qc *QuizController) GetPublicLeaderboard(c *fiber.Ctx) error {
+	code := strings.ToUpper(strings.TrimSpace(c.Params("code")))
+
+	quiz, err := qc.quizRepo.GetQuizByCode(code)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz",
+			"error":   err.Error(),
+		})
+	}
+	if quiz == nil || !quiz.LeaderboardPublic || quiz.LeaderboardOptOut {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Leaderboard not found",
+		})
+	}
+
+	entries, err := qc.quizRepo.GetLeaderboard(c.Context(), quiz.ID, models.QuizLeaderboardFilter{})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve leaderboard",
+			"error":   err.Error(),
+		})
+	}
+	if len(entries) > publicLeaderboardLimit {
+		entries = entries[:publicLeaderboardLimit]
+	}
+	// ID pengguna dan attempt tidak pernah dibuka ke publik.
+	for i := range entries {
+		entries[i].UserID = 0
+		entries[i].AttemptID = 0
+	}
+	if quiz.LeaderboardAnonymous {
+		models.AnonymizeLeaderboard(entries, 0)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Leaderboard retrieved successfully",
+		"data": fiber.Map{
+			"quiz_code":  quiz.Code,
+			"quiz_title": quiz.Title,
+			"entries":    entries,
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/quizzes/:id/leaderboard
+// ─────────────────────────────────────────────────────────────────────────────
+
+// GetLeaderboard shows admins the full ranking with real names, regardless
+// of the quiz's leaderboard settings.
+func (ac *QuizAdminController) GetLeaderboard(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	filter, limit, validationErrors, err := parseLeaderboardQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse query parameters",
+			"error":   err.Error(),
+		})
+	} else if len(validationErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationErrors,
+		})
+	}
+
+	quiz, err := ac.adminRepo.GetQuiz(quizID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz",
+			"error":   err.Error(),
+		})
+	}
+	if quiz == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found",
+		})
+	}
+
+	entries, err := ac.quizRepo.GetLeaderboard(c.Context(), quiz.ID, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve leaderboard",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Leaderboard retrieved successfully",
+		"data":    leaderboardResponse(quiz, entries, limit, nil),
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// parseLeaderboardQuery membaca filter kelas dan rentang waktu dari query string.
+// Window dihitung mundur dari menit berjalan agar hasilnya tetap bisa di-cache.
+func parseLeaderboardQuery(c *fiber.Ctx) (models.QuizLeaderboardFilter, int, map[string]string, error) {
+	var filter models.QuizLeaderboardFilter
+
+	req := new(requests.QuizLeaderboardRequest)
+	if err := c.QueryParser(req); err != nil {
+		return filter, 0, nil, err
+	}
+	if validationErrors := validator.ValidateStruct(req); len(validationErrors) > 0 {
+		return filter, 0, validationErrors, nil
+	}
+
+	if req.ClassID != "" {
+		classID := strings.ToLower(req.ClassID)
+		filter.ClassID = &classID
+	}
+	if window, ok := leaderboardWindows[req.Window]; ok {
+		from := time.Now().Truncate(time.Minute).Add(-window)
+		filter.From = &from
+	} else {
+		if req.From != "" {
+			from, _ := time.Parse(time.RFC3339, req.From)
+			filter.From = &from
+		}
+		if req.To != "" {
+			to, _ := time.Parse(time.RFC3339, req.To)
+			filter.To = &to
+		}
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultLeaderboardLimit
+	}
+	return filter, limit, nil, nil
+}
+
+func leaderboardResponse(quiz *models.QuizQuiz, entries []models.QuizLeaderboardEntry, limit int, currentUser *models.QuizLeaderboardEntry) fiber.Map {
+	total := len(entries)
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return fiber.Map{
+		"quiz_id":            quiz.ID,
+		"quiz_title":         quiz.Title,
+		"anonymous":          quiz.LeaderboardAnonymous,
+		"total_participants": total,
+		"entries":            entries,
+		"current_user":       currentUser,
+	}
+}
+
+// invalidateLeaderboard membuang cache papan peringkat kuis. Kegagalannya hanya
+// dicatat di log; cache tetap kedaluwarsa sendiri setelah TTL.
+func invalidateLeaderboard(c *fiber.Ctx, quizID uint) {
+	if err := models.InvalidateQuizLeaderboard(c.Context(), quizID); err != nil {
+		log.Printf("[QUIZ] Failed to invalidate leaderboard cache of quiz %d: %v", quizID, err)
+	}
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/jobs"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/database"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /admin/quizzes/:id/regrade
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) RegradeQuiz(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	req := new(requests.RegradeQuizRequest)
+	if ve, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
+	}
+
+	var quiz *models.QuizQuiz
+	var keyErrors map[string]string
+	var result *models.QuizRegradeResult
+	syncedKeys := 0
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		adminRepo := ac.adminRepo.WithExecutor(tx)
+
+		quiz, err = adminRepo.GetQuiz(quizID)
+		if err != nil || quiz == nil {
+			return err
+		}
+
+		if req.SyncAnswerKeys {
+			synced, err := adminRepo.SyncDraftAnswerKeys(quizID)
+			if err != nil {
+				return err
+			}
+			// Kunci jawaban draft yang belum lengkap tidak boleh masuk ke versi
+			// yang sudah dipublikasikan, jadi seluruh regrade dibatalkan.
+			if keyErrors = answerKeyErrors(synced); len(keyErrors) > 0 {
+				return models.ErrQuizAnswerKeyInvalid
+			}
+			syncedKeys = len(synced)
+		}
+
+		result, err = adminRepo.RegradeQuiz(quizID)
+		return err
+	})
+	if err == models.ErrQuizAnswerKeyInvalid {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "The draft answer keys cannot be applied to the published versions",
+			"errors":  keyErrors,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to regrade quiz",
+			"error":   err.Error(),
+		})
+	}
+	if quiz == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found",
+		})
+	}
+
+	if result.ScoresChanged > 0 {
+		invalidateLeaderboard(c, quizID)
+	}
+
+	log.Printf("[QUIZ] Quiz %d regraded: %d attempts, %d scores changed, %d pass/fail changed",
+		quizID, result.AttemptCount, result.ScoresChanged, result.PassStatusChanged)
+
+	notified := 0
+	if req.NotifyStudents && len(result.Changes) > 0 {
+		// Regrade sudah tersimpan, jadi kegagalan antrean email hanya dicatat.
+		notified, err = jobs.EnqueueQuizRegradeNotifications(c.Context(), quiz, result.Changes)
+		if err != nil {
+			log.Printf("[QUIZ] Failed to queue regrade notifications for quiz %d: %v", quizID, err)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Quiz regraded successfully",
+		"data": fiber.Map{
+			"regrade":              result,
+			"answer_keys_synced":   syncedKeys,
+			"notifications_queued": notified,
+		},
+	})
+}
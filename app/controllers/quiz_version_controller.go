@@ -0,0 +1,378 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/database"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /admin/quizzes/:id/publish
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) PublishQuiz(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	req := new(requests.PublishQuizRequest)
+	if ve, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
+	}
+
+	adminID := uint(c.Locals("userID").(int))
+
+	var version *models.QuizVersion
+	var draftErrors map[string]string
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		adminRepo := ac.adminRepo.WithExecutor(tx)
+
+		quiz, questions, err := adminRepo.GetQuizDetail(quizID)
+		if err != nil || quiz == nil {
+			return err
+		}
+		rules, err := adminRepo.ListSamplingRules(quizID)
+		if err != nil {
+			return err
+		}
+		if draftErrors = draftPublishErrors(questions, rules); len(draftErrors) > 0 {
+			return nil
+		}
+
+		version, err = adminRepo.PublishVersion(quizID, adminID, req.Notes)
+		return err
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to publish quiz",
+			"error":   err.Error(),
+		})
+	}
+	if len(draftErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "The quiz draft cannot be published yet",
+			"errors":  draftErrors,
+		})
+	}
+	if version == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found",
+		})
+	}
+
+	log.Printf("[QUIZ] Quiz %d published as version %d by user %d", quizID, version.VersionNumber, adminID)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Quiz published successfully",
+		"data":    version,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/quizzes/:id/versions
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) ListVersions(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+
+	versions, err := ac.adminRepo.ListVersions(quizID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz versions",
+			"error":   err.Error(),
+		})
+	}
+	if versions == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Quiz versions retrieved successfully",
+		"data":    versions,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/quizzes/:id/versions/:vid
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) GetVersion(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+	versionID, err := parseID(c, "vid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid version ID",
+		})
+	}
+
+	version, err := ac.adminRepo.GetVersion(quizID, versionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz version",
+			"error":   err.Error(),
+		})
+	}
+	if version == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz version not found",
+		})
+	}
+
+	questions, rules, err := ac.adminRepo.GetVersionDetail(versionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz version",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Quiz version retrieved successfully",
+		"data": fiber.Map{
+			"version":        version,
+			"questions":      buildAdminQuestionsResponse(questions),
+			"sampling_rules": rules,
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PUT /admin/quizzes/:id/versions/:vid/questions/:qid/answer-key
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (ac *QuizAdminController) CorrectAnswerKey(c *fiber.Ctx) error {
+	quizID, err := parseID(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid quiz ID",
+		})
+	}
+	versionID, err := parseID(c, "vid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid version ID",
+		})
+	}
+	questionID, err := parseID(c, "qid")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid question ID",
+		})
+	}
+
+	req := new(requests.CorrectAnswerKeyRequest)
+	if ve, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(ve) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  ve,
+		})
+	}
+
+	version, err := ac.adminRepo.GetVersion(quizID, versionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve quiz version",
+			"error":   err.Error(),
+		})
+	}
+	if version == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Quiz version not found",
+		})
+	}
+
+	var question *models.QuizQuestion
+	var keyErrors map[string]string
+	var result *models.QuizRegradeResult
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		adminRepo := ac.adminRepo.WithExecutor(tx)
+
+		question, err = adminRepo.GetVersionQuestion(versionID, questionID)
+		if err != nil || question == nil {
+			return err
+		}
+		if keyErrors = applyAnswerKey(question, req); len(keyErrors) > 0 {
+			return nil
+		}
+
+		if err := adminRepo.UpdateAnswerKey(question); err != nil {
+			return err
+		}
+		result, err = adminRepo.RegradeQuestion(question)
+		return err
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to correct answer key",
+			"error":   err.Error(),
+		})
+	}
+	if question == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Question not found in this version",
+		})
+	}
+	if len(keyErrors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  keyErrors,
+		})
+	}
+
+	if result.ScoresChanged > 0 {
+		invalidateLeaderboard(c, quizID)
+	}
+
+	log.Printf("[QUIZ] Answer key of question %d (quiz %d, version %d) corrected: %d attempts regraded, %d scores changed",
+		questionID, quizID, version.VersionNumber, result.AttemptCount, result.ScoresChanged)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Answer key corrected and attempts regraded successfully",
+		"data": fiber.Map{
+			"question": buildAdminQuestionResponse(*question),
+			"regrade":  result,
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// draftPublishErrors memeriksa draft kuis sebelum dipublikasikan: harus ada soal atau
+// aturan sampling, dan setiap soal punya kunci jawaban yang lengkap.
+func draftPublishErrors(questions []models.QuizQuestion, rules []models.QuizSamplingRule) map[string]string {
+	errors := answerKeyErrors(questions)
+	if len(questions) == 0 && len(rules) == 0 {
+		errors["questions"] = "Add questions or sampling rules before publishing"
+	}
+	return errors
+}
+
+// answerKeyErrors memeriksa kunci jawaban setiap soal dan mengembalikan pesan error per soal.
+func answerKeyErrors(questions []models.QuizQuestion) map[string]string {
+	errors := make(map[string]string)
+	for _, q := range questions {
+		err := q.ValidateConfig()
+		if err == nil {
+			err = q.ValidateOptions()
+		}
+		if err != nil {
+			errors[fmt.Sprintf("questions.%d", q.ID)] = err.Error()
+		}
+	}
+	return errors
+}
+
+// applyAnswerKey menerapkan kunci jawaban baru ke soal sesuai jenisnya.
+// Nilai kembalian berisi error validasi per field jika kunci jawaban tidak lengkap.
+func applyAnswerKey(q *models.QuizQuestion, req *requests.CorrectAnswerKeyRequest) map[string]string {
+	switch q.QuestionType {
+	case models.QuestionTypeTrueFalse:
+		if req.CorrectAnswer == nil {
+			return map[string]string{"correct_answer": "The correct_answer field is required for true_false questions"}
+		}
+		for i := range q.Options {
+			q.Options[i].IsCorrect = (q.Options[i].OptionText == "True") == *req.CorrectAnswer
+		}
+	case models.QuestionTypeShortAnswer:
+		if len(req.AcceptedAnswers) == 0 {
+			return map[string]string{"accepted_answers": "The accepted_answers field is required for short_answer questions"}
+		}
+		q.AcceptedAnswers = req.AcceptedAnswers
+	case models.QuestionTypeNumeric:
+		if req.NumericAnswer == nil {
+			return map[string]string{"numeric_answer": "The numeric_answer field is required for numeric questions"}
+		}
+		q.NumericAnswer = req.NumericAnswer
+		if req.NumericTolerance != nil {
+			q.NumericTolerance = *req.NumericTolerance
+		}
+	default:
+		if len(req.CorrectOptionIDs) == 0 {
+			return map[string]string{"correct_option_ids": "The correct_option_ids field is required for choice questions"}
+		}
+		correct := make(map[uint]bool, len(req.CorrectOptionIDs))
+		for _, id := range req.CorrectOptionIDs {
+			correct[id] = true
+		}
+		for i := range q.Options {
+			q.Options[i].IsCorrect = correct[q.Options[i].ID]
+			delete(correct, q.Options[i].ID)
+		}
+		if len(correct) > 0 {
+			return map[string]string{"correct_option_ids": "The correct_option_ids field contains an option of another question"}
+		}
+	}
+
+	err := q.ValidateConfig()
+	if err == nil {
+		err = q.ValidateOptions()
+	}
+	if err != nil {
+		return map[string]string{"answer_key": err.Error()}
+	}
+	return nil
+}
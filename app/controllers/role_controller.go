@@ -0,0 +1,374 @@
+package controllers
+
+import (
+	"database/sql"
+	"log"
+	"regexp"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/app/requests"
+	"github.com/studio-senkou/lentera-cendekia-be/database"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+// roleNamePattern keeps role names usable in URLs and in users.role.
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type RoleController struct {
+	permissionRepo *models.PermissionRepository
+}
+
+func NewRoleController() *RoleController {
+	return &RoleController{
+		permissionRepo: models.NewPermissionRepository(database.GetDB()),
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/permissions
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (rc *RoleController) ListPermissions(c *fiber.Ctx) error {
+	permissions, err := rc.permissionRepo.ListPermissions()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve permissions",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Permissions retrieved successfully",
+		"data":    permissions,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/roles
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (rc *RoleController) ListRoles(c *fiber.Ctx) error {
+	roles, err := rc.permissionRepo.ListRoles()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve roles",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Roles retrieved successfully",
+		"data":    roles,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /admin/roles/:name
+// ─────────────────────────────────────────────────────────────────────────────
+
+func (rc *RoleController) GetRole(c *fiber.Ctx) error {
+	role, err := rc.permissionRepo.GetRole(c.Params("name"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve role",
+			"error":   err.Error(),
+		})
+	}
+	if role == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Role retrieved successfully",
+		"data":    role,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /admin/roles
+// ─────────────────────────────────────────────────────────────────────────────
+
+// CreateRole adds a custom role, such as an academic coordinator, with the
+// permissions it grants.
+func (rc *RoleController) CreateRole(c *fiber.Ctx) error {
+	req := new(requests.CreateRoleRequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+	if !roleNamePattern.MatchString(req.Name) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors": fiber.Map{
+				"name": "name may only contain lowercase letters, digits and underscores, starting with a letter",
+			},
+		})
+	}
+
+	err := database.DB.Transaction(func(tx *sql.Tx) error {
+		permissionRepo := rc.permissionRepo.WithExecutor(tx)
+		if err := permissionRepo.CreateRole(req.Name, req.Description); err != nil {
+			return err
+		}
+		return permissionRepo.SetRolePermissions(req.Name, req.Permissions)
+	})
+	if err != nil {
+		return roleError(c, "Failed to create role", err)
+	}
+
+	role, err := rc.permissionRepo.GetRole(req.Name)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve role",
+			"error":   err.Error(),
+		})
+	}
+
+	log.Printf("[AUTH] Admin %v created role %s with %v", c.Locals("userID"), req.Name, req.Permissions)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Role created successfully",
+		"data":    role,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PUT /admin/roles/:name
+// ─────────────────────────────────────────────────────────────────────────────
+
+// UpdateRole replaces the permissions a role grants. The admin role always
+// keeps every permission so admins cannot lock themselves out.
+func (rc *RoleController) UpdateRole(c *fiber.Ctx) error {
+	req := new(requests.UpdateRoleRequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+
+	name := c.Params("name")
+	if name == models.RoleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "The admin role always has every permission",
+		})
+	}
+
+	role, err := rc.permissionRepo.GetRole(name)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve role",
+			"error":   err.Error(),
+		})
+	}
+	if role == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role not found",
+		})
+	}
+
+	err = database.DB.Transaction(func(tx *sql.Tx) error {
+		permissionRepo := rc.permissionRepo.WithExecutor(tx)
+		if err := permissionRepo.UpdateRoleDescription(name, req.Description); err != nil {
+			return err
+		}
+		return permissionRepo.SetRolePermissions(name, req.Permissions)
+	})
+	if err != nil {
+		return roleError(c, "Failed to update role", err)
+	}
+	invalidatePermissions(c)
+
+	role, err = rc.permissionRepo.GetRole(name)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve role",
+			"error":   err.Error(),
+		})
+	}
+
+	log.Printf("[AUTH] Admin %v set permissions of role %s to %v", c.Locals("userID"), name, req.Permissions)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Role updated successfully",
+		"data":    role,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// DELETE /admin/roles/:name
+// ─────────────────────────────────────────────────────────────────────────────
+
+// DeleteRole removes a custom role that no user holds anymore.
+func (rc *RoleController) DeleteRole(c *fiber.Ctx) error {
+	name := c.Params("name")
+	if err := rc.permissionRepo.DeleteRole(name); err != nil {
+		return roleError(c, "Failed to delete role", err)
+	}
+
+	log.Printf("[AUTH] Admin %v deleted role %s", c.Locals("userID"), name)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Role deleted successfully",
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// PUT /users/:id/role
+// ─────────────────────────────────────────────────────────────────────────────
+
+// AssignUserRole gives a user another role. The new permissions apply from
+// the user's next request.
+func (rc *RoleController) AssignUserRole(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid user ID",
+		})
+	}
+
+	req := new(requests.AssignRoleRequest)
+	if validationError, err := validator.ValidateRequest(c, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Cannot parse request body",
+			"error":   err.Error(),
+		})
+	} else if len(validationError) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Bad request",
+			"errors":  validationError,
+		})
+	}
+
+	if userID == c.Locals("userID").(int) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "You cannot change your own role",
+		})
+	}
+
+	role, err := rc.permissionRepo.GetRole(req.Role)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve role",
+			"error":   err.Error(),
+		})
+	}
+	if role == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Role not found",
+		})
+	}
+
+	updated, err := rc.permissionRepo.AssignUserRole(uint(userID), role.Name)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to assign role",
+			"error":   err.Error(),
+		})
+	}
+	if !updated {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	}
+	if err := models.InvalidateUserPermissions(c.Context(), uint(userID)); err != nil {
+		log.Printf("[AUTH] Failed to invalidate permissions of user %d: %v", userID, err)
+	}
+
+	log.Printf("[AUTH] Admin %v assigned role %s to user %d", c.Locals("userID"), role.Name, userID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Role assigned successfully",
+		"data": fiber.Map{
+			"user_id":     userID,
+			"role":        role.Name,
+			"permissions": role.Permissions,
+		},
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+func roleError(c *fiber.Ctx, message string, err error) error {
+	switch err {
+	case models.ErrRoleAlreadyExists, models.ErrRoleInUse:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  "fail",
+			"message": message,
+			"error":   err.Error(),
+		})
+	case models.ErrRoleProtected:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "fail",
+			"message": message,
+			"error":   err.Error(),
+		})
+	case models.ErrPermissionNotFound:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": message,
+			"error":   err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": message,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// invalidatePermissions membuang cache izin semua pengguna setelah izin suatu
+// peran berubah. Kegagalannya hanya dicatat; cache tetap kedaluwarsa sendiri.
+func invalidatePermissions(c *fiber.Ctx) {
+	if err := models.InvalidateAllPermissions(c.Context()); err != nil {
+		log.Printf("[AUTH] Failed to invalidate permission cache: %v", err)
+	}
+}
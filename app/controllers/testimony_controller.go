@@ -45,6 +45,13 @@ func (tc *TestimonyController) CreateTestimony(c *fiber.Ctx) error {
 
 	var uploadedTestimonerPhoto *string
 	if testimonerPhoto != nil {
+		if !storage.IsValidImageExtension(testimonerPhoto.Filename) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid photo type",
+			})
+		}
+
 		maxSize := int64(1 * 1024 * 1024)
 		if testimonerPhoto.Size > maxSize {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -182,6 +189,13 @@ func (tc *TestimonyController) UpdateTestimony(c *fiber.Ctx) error {
 
 	var uploadedTestimonerPhoto *string
 	if testimonerPhoto != nil {
+		if !storage.IsValidImageExtension(testimonerPhoto.Filename) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Invalid photo type",
+			})
+		}
+
 		maxSize := int64(1 * 1024 * 1024)
 		if testimonerPhoto.Size > maxSize {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
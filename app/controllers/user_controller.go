@@ -19,6 +19,7 @@ import (
 
 type UserController struct {
 	jwtManager      *auth.JwtManager
+	lockout         *auth.AccountLockout
 	userRepo        *models.UserRepository
 	studentRepo     *models.StudentRepository
 	studentPlanRepo *models.StudentPlanRepository
@@ -28,10 +29,10 @@ type UserController struct {
 
 func NewUserController() *UserController {
 	db := database.GetDB()
-	authSecret := app.GetEnv("AUTH_SECRET", "")
 
 	return &UserController{
-		jwtManager:      auth.NewJwtManager(authSecret),
+		jwtManager:      auth.GetJwtManager(),
+		lockout:         auth.NewAccountLockout(),
 		userRepo:        models.NewUserRepository(db),
 		studentRepo:     models.NewStudentRepository(db),
 		studentPlanRepo: models.NewStudentPlanRepository(db),
@@ -258,40 +259,19 @@ func (uc *UserController) ActivateUser(c *fiber.Ctx) error {
 	}
 
 	// Authenticate the user after activation
-	accessToken, err := uc.jwtManager.GenerateToken(auth.Payload{
-		UserID: user.ID,
-		Role:   user.Role,
-	}, time.Now().Add(24*time.Hour))
+	tokens, err := startSession(c, uc.jwtManager, uc.authRepo, user, "")
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Failed to generate access token",
-			"error":   "Failed to generate access token: " + err.Error(),
-		})
-	}
-
-	refreshToken, err := uc.jwtManager.GenerateToken(auth.Payload{
-		UserID: user.ID,
-		Role:   user.Role,
-	}, time.Now().Add(30*24*time.Hour))
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Failed to generate refresh token",
-			"error":   "Failed to generate refresh token: " + err.Error(),
+			"message": "Failed to generate authentication tokens",
+			"error":   "Failed to generate authentication tokens: " + err.Error(),
 		})
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"status":  "success",
 		"message": "User activated successfully",
-		"data": fiber.Map{
-			"active_role":          user.Role,
-			"access_token":         accessToken.Token,
-			"access_token_expiry":  accessToken.ExpiresAt,
-			"refresh_token":        refreshToken.Token,
-			"refresh_token_expiry": refreshToken.ExpiresAt,
-		},
+		"data":    tokens,
 	})
 }
 
@@ -647,6 +627,14 @@ func (uc *UserController) UpdatePasswordByToken(c *fiber.Ctx) error {
 		})
 	}
 
+	// Riwayat password diperiksa sebelum token dihabiskan, agar pengguna bisa
+	// mencoba password lain dengan tautan yang sama.
+	if pending, err := auth.CheckOneTimeTokenStatus(updatePasswordRequest.Token); err == nil && pending.Purpose == "password_reset" {
+		if reused, err := uc.rejectReusedPassword(c, pending.UserID, updatePasswordRequest.NewPassword); reused {
+			return err
+		}
+	}
+
 	oneTimeToken, err := auth.ValidateOneTimeToken(updatePasswordRequest.Token, "password_reset")
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -664,6 +652,15 @@ func (uc *UserController) UpdatePasswordByToken(c *fiber.Ctx) error {
 		})
 	}
 
+	// Password direset karena bisa jadi akun diambil alih, jadi semua login dicabut.
+	if _, err := uc.authRepo.RevokeUserSessions(oneTimeToken.UserID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Failed to revoke existing sessions",
+			"error":   err.Error(),
+		})
+	}
+
 	return c.JSON(fiber.Map{
 		"status":  "success",
 		"message": "Password updated successfully",
@@ -708,6 +705,10 @@ func (uc *UserController) UpdateUserPassword(c *fiber.Ctx) error {
 		})
 	}
 
+	if reused, err := uc.rejectReusedPassword(c, uint(userID), updatePasswordRequest.NewPassword); reused {
+		return err
+	}
+
 	if err := uc.userRepo.UpdatePassword(uint(userID), updatePasswordRequest.NewPassword); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "fail",
@@ -747,3 +748,28 @@ func (uc *UserController) DeleteUser(c *fiber.Ctx) error {
 		"message": "User deleted successfully",
 	})
 }
+
+// rejectReusedPassword menolak password baru yang sama dengan salah satu
+// password terakhir pengguna. Bila ditolak, respons sudah ditulis dan
+// error-nya dikembalikan.
+func (uc *UserController) rejectReusedPassword(c *fiber.Ctx, userID uint, password string) (bool, error) {
+	history := validator.GetPasswordPolicy().HistorySize
+
+	reused, err := uc.userRepo.IsPasswordReused(userID, password, history)
+	if err != nil {
+		return true, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Failed to check password history",
+			"error":   err.Error(),
+		})
+	}
+	if reused {
+		return true, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Validation failed",
+			"errors": fiber.Map{
+				"new_password": validator.PasswordReusedMessage("new_password", history),
+			},
+		})
+	}
+	return false, nil
+}
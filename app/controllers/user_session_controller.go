@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ─────────────────────────────────────────────────────────────────────────────
+// GET /users/me/sessions
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ListMySessions shows the devices the user is logged in on.
+func (uc *UserController) ListMySessions(c *fiber.Ctx) error {
+	userID := uint(c.Locals("userID").(int))
+	currentSessionID, _ := c.Locals("sessionID").(string)
+
+	sessions, err := uc.authRepo.ListActiveSessions(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve sessions",
+			"error":   err.Error(),
+		})
+	}
+
+	data := make([]fiber.Map, len(sessions))
+	for i, session := range sessions {
+		data[i] = fiber.Map{
+			"id":           session.ID,
+			"device_name":  session.DeviceName,
+			"ip_address":   session.IPAddress,
+			"user_agent":   session.UserAgent,
+			"created_at":   session.CreatedAt,
+			"last_seen_at": session.LastSeenAt,
+			"expires_at":   session.ExpiresAt,
+			"current":      session.ID == currentSessionID,
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Sessions retrieved successfully",
+		"data":    data,
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// DELETE /users/me/sessions/:id
+// ─────────────────────────────────────────────────────────────────────────────
+
+// RevokeMySession logs one of the user's devices out.
+func (uc *UserController) RevokeMySession(c *fiber.Ctx) error {
+	userID := uint(c.Locals("userID").(int))
+
+	sessionID := c.Params("id")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid session ID",
+		})
+	}
+
+	revoked, err := uc.authRepo.RevokeSession(userID, sessionID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to revoke session",
+			"error":   err.Error(),
+		})
+	}
+	if !revoked {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Session not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Session revoked successfully",
+	})
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// POST /users/:id/force-logout
+// ─────────────────────────────────────────────────────────────────────────────
+
+// ForceLogoutUser lets an admin log a user out on every device.
+func (uc *UserController) ForceLogoutUser(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "Invalid user ID",
+		})
+	}
+
+	user, err := uc.userRepo.GetByID(uint(userID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to retrieve user",
+			"error":   err.Error(),
+		})
+	}
+	if user == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "fail",
+			"message": "User not found",
+		})
+	}
+
+	revoked, err := uc.authRepo.RevokeUserSessions(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Failed to log out user",
+			"error":   err.Error(),
+		})
+	}
+
+	log.Printf("[AUTH] Admin %v force-logged out user %d (%d sessions)", c.Locals("userID"), user.ID, revoked)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "User has been logged out on all devices",
+		"data": fiber.Map{
+			"revoked_sessions": revoked,
+		},
+	})
+}
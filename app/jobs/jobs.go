@@ -0,0 +1,15 @@
+package jobs
+
+import (
+	"github.com/studio-senkou/lentera-cendekia-be/utils/queue"
+)
+
+// Register wires every background job handler and periodic schedule into the
+// given queue service. It must be called before the worker is started.
+func Register(qs *queue.QueueService) error {
+	if err := RegisterQuizJobs(qs); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hibiken/asynq"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/database"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/certificate"
+	gomail "github.com/studio-senkou/lentera-cendekia-be/utils/mail"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/queue"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/storage"
+)
+
+const (
+	TaskQuizFinalizeExpiredAttempts = "quiz:finalize-expired-attempts"
+	TaskQuizRegradeNotification     = "quiz:regrade-notification"
+	TaskQuizIssueCertificate        = "quiz:issue-certificate"
+
+	quizCertificateTemplate = "templates/certificates/quiz_certificate.json"
+)
+
+// quizRegradeNotification is the payload of TaskQuizRegradeNotification.
+type quizRegradeNotification struct {
+	QuizTitle string                   `json:"quiz_title"`
+	Change    models.QuizRegradeChange `json:"change"`
+}
+
+// quizIssueCertificate is the payload of TaskQuizIssueCertificate.
+type quizIssueCertificate struct {
+	CertificateID uint `json:"certificate_id"`
+}
+
+var (
+	queueClient     *queue.QueueService
+	queueClientOnce sync.Once
+)
+
+func RegisterQuizJobs(qs *queue.QueueService) error {
+	qs.RegisterHandlerFunc(TaskQuizFinalizeExpiredAttempts, handleFinalizeExpiredAttempts)
+	qs.RegisterHandlerFunc(TaskQuizRegradeNotification, handleQuizRegradeNotification)
+	qs.RegisterHandlerFunc(TaskQuizIssueCertificate, handleQuizIssueCertificate)
+
+	if _, err := qs.SchedulePeriodicTask("@every 1m", TaskQuizFinalizeExpiredAttempts, nil); err != nil {
+		return fmt.Errorf("failed to schedule %s: %w", TaskQuizFinalizeExpiredAttempts, err)
+	}
+
+	return nil
+}
+
+// handleFinalizeExpiredAttempts auto-submits in-progress attempts whose time
+// limit has passed, scoring whatever answers were saved before the deadline.
+func handleFinalizeExpiredAttempts(ctx context.Context, task *asynq.Task) error {
+	quizRepo := models.NewQuizRepository(database.GetDB())
+
+	attempts, err := quizRepo.FinalizeExpiredAttempts()
+	if err != nil {
+		return fmt.Errorf("failed to finalize expired quiz attempts: %w", err)
+	}
+
+	if len(attempts) > 0 {
+		log.Printf("[QUIZ] Auto-submitted %d expired attempt(s)", len(attempts))
+	}
+
+	invalidated := make(map[uint]bool)
+	for _, attempt := range attempts {
+		if !invalidated[attempt.QuizID] {
+			invalidated[attempt.QuizID] = true
+			if err := models.InvalidateQuizLeaderboard(ctx, attempt.QuizID); err != nil {
+				log.Printf("[QUIZ] Failed to invalidate leaderboard cache of quiz %d: %v", attempt.QuizID, err)
+			}
+		}
+
+		cert, err := quizRepo.IssueCertificate(attempt.UserID, attempt.QuizID)
+		if err != nil {
+			log.Printf("[QUIZ] Failed to issue certificate of quiz %d for user %d: %v", attempt.QuizID, attempt.UserID, err)
+			continue
+		}
+		if cert != nil {
+			if err := EnqueueQuizCertificate(ctx, cert.ID); err != nil {
+				log.Printf("[QUIZ] Failed to queue certificate %d: %v", cert.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// EnqueueQuizRegradeNotifications queues an email to the owner of every
+// attempt whose score changed in a regrade, one task per attempt so a failed
+// send is retried on its own. It returns how many were queued.
+func EnqueueQuizRegradeNotifications(ctx context.Context, quiz *models.QuizQuiz, changes []models.QuizRegradeChange) (int, error) {
+	for i, change := range changes {
+		_, err := getQueueClient().NewJobBuilder(TaskQuizRegradeNotification).
+			WithData("quiz_title", quiz.Title).
+			WithData("change", change).
+			Enqueue(ctx)
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(changes), nil
+}
+
+// handleQuizRegradeNotification emails a student that the score of one of
+// their attempts changed after a regrade.
+func handleQuizRegradeNotification(ctx context.Context, task *asynq.Task) error {
+	var payload quizRegradeNotification
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", TaskQuizRegradeNotification, err)
+	}
+
+	user, err := models.NewUserRepository(database.GetDB()).GetByID(payload.Change.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load user %d: %w", payload.Change.UserID, err)
+	}
+	if user == nil {
+		return nil
+	}
+
+	change := payload.Change
+	email, err := gomail.NewMailFromTemplate(
+		user.Email,
+		"Quiz score updated",
+		"templates/emails/quiz_regraded.html",
+		fiber.Map{
+			"Name":              user.Name,
+			"QuizTitle":         payload.QuizTitle,
+			"PreviousScore":     formatQuizScore(change.PreviousScore),
+			"Score":             formatQuizScore(change.Score),
+			"Passed":            change.Passed,
+			"PassStatusChanged": change.Passed != change.PreviouslyPassed,
+			"AppLink":           app.GetEnv("APP_FE_URL", "http://localhost:3000"),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create quiz regrade email: %w", err)
+	}
+
+	if err := email.Send(); err != nil {
+		return fmt.Errorf("failed to send quiz regrade email to user %d: %w", user.ID, err)
+	}
+
+	log.Printf("[QUIZ] Notified user %d about the regraded attempt %d", user.ID, change.AttemptID)
+	return nil
+}
+
+// EnqueueQuizCertificate queues rendering, storing and emailing of a newly
+// issued certificate.
+func EnqueueQuizCertificate(ctx context.Context, certificateID uint) error {
+	_, err := getQueueClient().NewJobBuilder(TaskQuizIssueCertificate).
+		WithData("certificate_id", certificateID).
+		Enqueue(ctx)
+	return err
+}
+
+// handleQuizIssueCertificate renders the certificate PDF, stores it and
+// emails it to the student. Steps already done in an earlier run are
+// skipped, so a retry after a failed send does not upload the file again.
+func handleQuizIssueCertificate(ctx context.Context, task *asynq.Task) error {
+	var payload quizIssueCertificate
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", TaskQuizIssueCertificate, err)
+	}
+
+	quizRepo := models.NewQuizRepository(database.GetDB())
+	cert, err := quizRepo.GetCertificateByID(payload.CertificateID)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate %d: %w", payload.CertificateID, err)
+	}
+	if cert == nil || (cert.FilePath != nil && cert.EmailedAt != nil) {
+		return nil
+	}
+
+	verifyURL := app.GetEnv("APP_FE_URL", "http://localhost:3000") + "/certificates/" + cert.Code
+	pdf, err := certificate.RenderFile(quizCertificateTemplate, fiber.Map{
+		"StudentName": cert.StudentName,
+		"QuizTitle":   cert.QuizTitle,
+		"Score":       formatQuizScore(&cert.Score),
+		"IssuedDate":  formatIndonesianDate(cert.IssuedAt),
+		"Code":        cert.Code,
+		"VerifyURL":   verifyURL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render certificate %d: %w", cert.ID, err)
+	}
+	filename := "sertifikat-" + cert.Code + ".pdf"
+
+	if cert.FilePath == nil {
+		path, err := storage.UploadBytesToStorage(pdf, filename, "certificates", "CERT")
+		if err != nil {
+			return fmt.Errorf("failed to store certificate %d: %w", cert.ID, err)
+		}
+		if err := quizRepo.SetCertificateFile(cert.ID, path); err != nil {
+			return fmt.Errorf("failed to save certificate %d file path: %w", cert.ID, err)
+		}
+	}
+
+	if cert.EmailedAt == nil {
+		user, err := models.NewUserRepository(database.GetDB()).GetByID(cert.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to load user %d: %w", cert.UserID, err)
+		}
+		if user == nil {
+			return nil
+		}
+
+		email, err := gomail.NewMailFromTemplate(
+			user.Email,
+			"Quiz certificate",
+			"templates/emails/quiz_certificate.html",
+			fiber.Map{
+				"Name":      user.Name,
+				"QuizTitle": cert.QuizTitle,
+				"Score":     formatQuizScore(&cert.Score),
+				"Code":      cert.Code,
+				"VerifyURL": verifyURL,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create quiz certificate email: %w", err)
+		}
+
+		if err := email.Attach(filename, pdf).Send(); err != nil {
+			return fmt.Errorf("failed to send certificate %d to user %d: %w", cert.ID, user.ID, err)
+		}
+		if err := quizRepo.MarkCertificateEmailed(cert.ID); err != nil {
+			return fmt.Errorf("failed to mark certificate %d as emailed: %w", cert.ID, err)
+		}
+	}
+
+	log.Printf("[QUIZ] Issued certificate %s to user %d", cert.Code, cert.UserID)
+	return nil
+}
+
+func getQueueClient() *queue.QueueService {
+	queueClientOnce.Do(func() {
+		queueClient = queue.NewClient()
+	})
+	return queueClient
+}
+
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// formatIndonesianDate formats t as e.g. "18 Oktober 2026".
+func formatIndonesianDate(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+}
+
+func formatQuizScore(score *float64) string {
+	if score == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *score)
+}
@@ -2,19 +2,18 @@ package middlewares
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/studio-senkou/lentera-cendekia-be/app/models"
 	"github.com/studio-senkou/lentera-cendekia-be/database"
-	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
 	"github.com/studio-senkou/lentera-cendekia-be/utils/auth"
 )
 
 var jwtManager *auth.JwtManager
 
 func init() {
-	authSecret := app.GetEnv("AUTH_SECRET", "")
-	jwtManager = auth.NewJwtManager(authSecret)
+	jwtManager = auth.GetJwtManager()
 }
 
 func AuthMiddleware() fiber.Handler {
@@ -87,15 +86,34 @@ func AuthMiddleware() fiber.Handler {
 			})
 		}
 
-		if session, err := authRepository.GetTokenByUserID(userID); err != nil || session == nil {
+		// Access token hanya berlaku selama sesi (perangkat) asalnya belum dicabut.
+		sessionID, _ := payloadMap["session_id"].(string)
+		if sessionID == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Session already invalidated or user logged out, please login again",
+			})
+		}
+		session, err := authRepository.GetActiveSession(uint(userID), sessionID)
+		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Failed to check session",
+			})
+		}
+		if session == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"status":  "fail",
 				"message": "Session already invalidated or user logged out, please login again",
 			})
 		}
+		if err := authRepository.TouchSession(session); err != nil {
+			log.Printf("[AUTH] Failed to update last seen of session %s: %v", session.ID, err)
+		}
 
 		c.Locals("userID", userID)
 		c.Locals("userRole", userRole)
+		c.Locals("sessionID", sessionID)
 
 		return c.Next()
 	}
@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/database"
+)
+
+// RequirePermission lets the request through when the user's role grants any
+// of the permissions. It must run after AuthMiddleware.
+func RequirePermission(permissions ...string) fiber.Handler {
+	permissionRepo := models.NewPermissionRepository(database.GetDB())
+
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("userID").(int)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Unauthorized",
+			})
+		}
+
+		allowed, err := permissionRepo.HasPermission(c.Context(), uint(userID), permissions...)
+		if err != nil {
+			log.Printf("[AUTH] Failed to load permissions of user %d: %v", userID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Failed to check permissions",
+			})
+		}
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Forbidden",
+				"error":   "You do not have permission to access this resource",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/auth"
+)
+
+// ThrottleMiddleware protects a public endpoint that takes an email against
+// guessing. Requests are keyed by client IP and by the email in the body;
+// failed requests are counted and a blocked key gets 429 until its backoff
+// has passed. With countSuccess every request counts, for endpoints such as
+// password reset where a success is what gets abused.
+func ThrottleMiddleware(limiter *auth.Limiter, countSuccess bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var body struct {
+			Email string `json:"email" form:"email"`
+		}
+		_ = c.BodyParser(&body)
+		keys := auth.ThrottleKeys(c.IP(), body.Email)
+
+		wait, err := limiter.Wait(c.Context(), keys...)
+		if err != nil {
+			// Redis bermasalah tidak boleh menutup akses login.
+			log.Printf("[AUTH] Failed to check %s throttle: %v", limiter.Scope, err)
+		} else if wait > 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"status":  "fail",
+				"message": "Too many attempts",
+				"error":   "Please wait before trying again",
+			})
+		}
+
+		handlerErr := c.Next()
+
+		// Kesalahan server bukan tebakan klien, jadi tidak dihitung.
+		status := c.Response().StatusCode()
+		failed := status >= fiber.StatusBadRequest && status != fiber.StatusInternalServerError
+		switch {
+		case failed || countSuccess:
+			err = limiter.Fail(c.Context(), keys...)
+		case status < fiber.StatusMultipleChoices:
+			// Hanya hitungan email yang dilupakan; IP yang menebak banyak akun tetap diperlambat.
+			err = limiter.Reset(c.Context(), keys[1:]...)
+		}
+		if err != nil {
+			log.Printf("[AUTH] Failed to record %s throttle: %v", limiter.Scope, err)
+		}
+
+		return handlerErr
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/studio-senkou/lentera-cendekia-be/database/facades"
+)
+
+// Keys of app_settings.
+const (
+	// SettingMFARequiredForAdmins makes everyone with the admin.panel
+	// permission log in with TOTP, enrolling on their next login if they have
+	// not yet.
+	SettingMFARequiredForAdmins = "mfa_required_for_admins"
+)
+
+// AppSettingRepository reads and writes settings that admins change at
+// runtime. Values are stored as text.
+type AppSettingRepository struct {
+	db facades.DBExecutor
+}
+
+func NewAppSettingRepository(db facades.DBExecutor) *AppSettingRepository {
+	return &AppSettingRepository{db: db}
+}
+
+func (r *AppSettingRepository) WithExecutor(executor facades.DBExecutor) *AppSettingRepository {
+	return &AppSettingRepository{db: executor}
+}
+
+// GetBool returns the setting, or fallback when it was never set.
+func (r *AppSettingRepository) GetBool(key string, fallback bool) (bool, error) {
+	var value string
+	err := r.db.QueryRow(`SELECT value FROM app_settings WHERE key = $1`, key).Scan(&value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fallback, nil
+		}
+		return fallback, err
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback, nil
+	}
+	return parsed, nil
+}
+
+func (r *AppSettingRepository) SetBool(key string, value bool, updatedBy uint) error {
+	query := `
+		INSERT INTO app_settings (key, value, updated_by, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET value = EXCLUDED.value, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+	`
+	_, err := r.db.Exec(query, key, strconv.FormatBool(value), updatedBy)
+	return err
+}
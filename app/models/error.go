@@ -3,7 +3,25 @@ package models
 type ModelError string
 
 const (
-	ErrEmailAlreadyExists ModelError = "email already exists"
+	ErrEmailAlreadyExists     ModelError = "email already exists"
+	ErrQuizAttemptClosed      ModelError = "quiz attempt is no longer in progress"
+	ErrQuizInvalidAnswer      ModelError = "option does not belong to the question"
+	ErrQuizAttemptInProgress  ModelError = "quiz attempt is already in progress"
+	ErrQuizMaxAttemptsReached ModelError = "maximum number of quiz attempts reached"
+	ErrQuizAttemptCooldown    ModelError = "quiz attempt cooldown has not passed yet"
+	ErrQuizNotFound           ModelError = "quiz not found or inactive"
+	ErrQuizNotOpen            ModelError = "quiz is not open yet"
+	ErrQuizClosed             ModelError = "quiz is closed"
+	ErrQuizNotAssigned        ModelError = "quiz is not assigned to any of the student's classes"
+	ErrClassNotFound          ModelError = "class not found"
+	ErrQuizAnswerKeyInvalid   ModelError = "answer key is incomplete"
+	ErrRefreshTokenInvalid    ModelError = "refresh token is invalid or expired"
+	ErrMFAAlreadyEnabled      ModelError = "two-factor authentication is already enabled"
+	ErrRoleAlreadyExists      ModelError = "role already exists"
+	ErrRoleProtected          ModelError = "system roles cannot be deleted"
+	ErrRoleInUse              ModelError = "role is still assigned to users"
+	ErrPermissionNotFound     ModelError = "permission does not exist"
+	ErrIdentityAlreadyLinked  ModelError = "another account of the provider is already linked"
 )
 
 func (e ModelError) Error() string {
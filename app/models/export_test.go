@@ -0,0 +1,11 @@
+package models
+
+// Internals used by the external models_test package.
+
+type AnalyzedAttempt = analyzedAttempt
+type AnalyzedAnswer = analyzedAnswer
+
+var (
+	AnalyzeQuiz     = analyzeQuiz
+	ScoreStatistics = scoreStatistics
+)
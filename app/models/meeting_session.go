@@ -2,8 +2,10 @@ package models
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/studio-senkou/lentera-cendekia-be/database/facades"
 )
 
@@ -24,6 +26,14 @@ type MeetingSession struct {
 	DeletedAt   *time.Time `json:"deleted_at"`
 }
 
+// MeetingSessionOwner identifies who a session belongs to: its mentor and
+// the user account of its student.
+type MeetingSessionOwner struct {
+	SessionID     uint
+	MentorID      uint
+	StudentUserID *uint
+}
+
 type MeetingSessionRepository struct {
 	db  facades.DBExecutor
 	raw *sql.DB // retained for BulkCreate/BulkUpdate which need Begin()
@@ -110,7 +120,9 @@ func (r *MeetingSessionRepository) BulkCreateSessions(sessions []*MeetingSession
 	return tx.Commit()
 }
 
-func (r *MeetingSessionRepository) GetAll(userID uint) ([]*MeetingSession, error) {
+// GetAll returns the sessions of the student with the given user ID and of
+// the given mentor; a zero ID leaves that filter out.
+func (r *MeetingSessionRepository) GetAll(userID, mentorID uint) ([]*MeetingSession, error) {
 
 	query := `
 		SELECT 
@@ -123,17 +135,18 @@ func (r *MeetingSessionRepository) GetAll(userID uint) ([]*MeetingSession, error
 			LEFT JOIN users mu ON mu.id = ms.mentor_id
 	`
 
-	var rows *sql.Rows
-	var err error
-
+	query += " WHERE ms.deleted_at IS NULL"
+	args := make([]any, 0, 2)
 	if userID != 0 {
-		query += " WHERE ms.deleted_at IS NULL AND s.user_id = $1"
-		rows, err = r.db.Query(query, userID)
-	} else {
-		query += " WHERE ms.deleted_at IS NULL"
-		rows, err = r.db.Query(query)
+		args = append(args, userID)
+		query += fmt.Sprintf(" AND s.user_id = $%d", len(args))
+	}
+	if mentorID != 0 {
+		args = append(args, mentorID)
+		query += fmt.Sprintf(" AND ms.mentor_id = $%d", len(args))
 	}
 
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -198,6 +211,37 @@ func (r *MeetingSessionRepository) GetByID(id uint) (*MeetingSession, error) {
 	return session, nil
 }
 
+// GetOwners returns the owners of the sessions keyed by session ID. Sessions
+// that do not exist are left out.
+func (r *MeetingSessionRepository) GetOwners(ids []uint) (map[uint]MeetingSessionOwner, error) {
+	sessionIDs := make([]int64, len(ids))
+	for i, id := range ids {
+		sessionIDs[i] = int64(id)
+	}
+
+	query := `
+		SELECT ms.id, ms.mentor_id, s.user_id
+		FROM meeting_sessions ms
+			LEFT JOIN students s ON s.id = ms.student_id
+		WHERE ms.id = ANY($1) AND ms.deleted_at IS NULL
+	`
+	rows, err := r.db.Query(query, pq.Array(sessionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	owners := make(map[uint]MeetingSessionOwner, len(ids))
+	for rows.Next() {
+		var owner MeetingSessionOwner
+		if err := rows.Scan(&owner.SessionID, &owner.MentorID, &owner.StudentUserID); err != nil {
+			return nil, err
+		}
+		owners[owner.SessionID] = owner
+	}
+	return owners, rows.Err()
+}
+
 func (r *MeetingSessionRepository) BulkUpdate(sessions []*MeetingSession) error {
 	tx, err := r.raw.Begin()
 	if err != nil {
@@ -0,0 +1,300 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/studio-senkou/lentera-cendekia-be/database/facades"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/cache"
+)
+
+// Permissions checked by the routes. New permissions are added with a
+// migration, which should also grant them to the admin role.
+const (
+	PermAdminPanel        = "admin.panel"
+	PermUserView          = "user.view"
+	PermUserManage        = "user.manage"
+	PermRoleManage        = "role.manage"
+	PermSecurityManage    = "security.manage"
+	PermMFAEnroll         = "mfa.enroll"
+	PermClassManage       = "class.manage"
+	PermSessionView       = "session.view"
+	PermSessionCreate     = "session.create"
+	PermSessionManage     = "session.manage"
+	PermSessionManageAll  = "session.manage_all"
+	PermQuizManage        = "quiz.manage"
+	PermBlogPublish       = "blog.publish"
+	PermBlogManageAll     = "blog.manage_all"
+	PermTestimonyManage   = "testimony.manage"
+	PermStaticAssetManage = "static_asset.manage"
+)
+
+// RoleAdmin always holds every permission; its grants cannot be edited.
+const RoleAdmin = "admin"
+
+// UserPermissionsCacheTTL bounds how long a user keeps permissions after a
+// missed invalidation.
+const UserPermissionsCacheTTL = 10 * time.Minute
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Role struct {
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	IsSystem    bool       `json:"is_system"`
+	Permissions []string   `json:"permissions"`
+	UserCount   int        `json:"user_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+type PermissionRepository struct {
+	db facades.DBExecutor
+}
+
+func NewPermissionRepository(db facades.DBExecutor) *PermissionRepository {
+	return &PermissionRepository{db: db}
+}
+
+func (r *PermissionRepository) WithExecutor(executor facades.DBExecutor) *PermissionRepository {
+	return &PermissionRepository{db: executor}
+}
+
+func userPermissionsCacheKey(userID uint) string {
+	return fmt.Sprintf("permissions:user:%d", userID)
+}
+
+// GetUserPermissions returns the permissions the user's role grants. They
+// are cached in Redis per user; when Redis is unavailable they are read from
+// the database.
+func (r *PermissionRepository) GetUserPermissions(ctx context.Context, userID uint) ([]string, error) {
+	if cache.RedisClient == nil {
+		return r.queryUserPermissions(userID)
+	}
+
+	key := userPermissionsCacheKey(userID)
+	var permissions []string
+	if err := cache.Get(ctx, key, &permissions); err == nil {
+		return permissions, nil
+	}
+
+	permissions, err := r.queryUserPermissions(userID)
+	if err != nil {
+		return nil, err
+	}
+	_ = cache.Set(ctx, key, permissions, UserPermissionsCacheTTL)
+	return permissions, nil
+}
+
+// HasPermission reports whether the user holds any of the permissions.
+func (r *PermissionRepository) HasPermission(ctx context.Context, userID uint, permissions ...string) (bool, error) {
+	granted, err := r.GetUserPermissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, permission := range permissions {
+		if slices.Contains(granted, permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *PermissionRepository) queryUserPermissions(userID uint) ([]string, error) {
+	query := `
+		SELECT rp.permission_name
+		FROM users u
+			JOIN role_permissions rp ON rp.role_name = u.role
+		WHERE u.id = $1 AND u.deleted_at IS NULL
+		ORDER BY rp.permission_name
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make([]string, 0)
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, rows.Err()
+}
+
+// InvalidateUserPermissions drops the cached permissions of one user, after
+// their role changed.
+func InvalidateUserPermissions(ctx context.Context, userID uint) error {
+	if cache.RedisClient == nil {
+		return nil
+	}
+	return cache.Delete(ctx, userPermissionsCacheKey(userID))
+}
+
+// InvalidateAllPermissions drops every cached permission set, after the
+// grants of a role changed.
+func InvalidateAllPermissions(ctx context.Context) error {
+	if cache.RedisClient == nil {
+		return nil
+	}
+	return cache.DeletePattern(ctx, "permissions:user:*")
+}
+
+func (r *PermissionRepository) ListPermissions() ([]Permission, error) {
+	rows, err := r.db.Query(`SELECT name, description FROM permissions ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make([]Permission, 0)
+	for rows.Next() {
+		var p Permission
+		if err := rows.Scan(&p.Name, &p.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, rows.Err()
+}
+
+const roleSelect = `
+	SELECT r.name, r.description, r.is_system, r.created_at, r.updated_at,
+		COALESCE((SELECT ARRAY_AGG(rp.permission_name ORDER BY rp.permission_name)
+			FROM role_permissions rp WHERE rp.role_name = r.name), '{}'),
+		(SELECT COUNT(*) FROM users u WHERE u.role = r.name AND u.deleted_at IS NULL)
+	FROM roles r
+`
+
+func scanRole(row interface{ Scan(...any) error }) (*Role, error) {
+	var role Role
+	var permissions pq.StringArray
+	if err := row.Scan(
+		&role.Name, &role.Description, &role.IsSystem, &role.CreatedAt, &role.UpdatedAt, &permissions, &role.UserCount,
+	); err != nil {
+		return nil, err
+	}
+	role.Permissions = []string(permissions)
+	return &role, nil
+}
+
+func (r *PermissionRepository) ListRoles() ([]Role, error) {
+	rows, err := r.db.Query(roleSelect + ` ORDER BY r.is_system DESC, r.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make([]Role, 0)
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, *role)
+	}
+	return roles, rows.Err()
+}
+
+// GetRole returns the role with its permissions, or nil when it does not exist.
+func (r *PermissionRepository) GetRole(name string) (*Role, error) {
+	role, err := scanRole(r.db.QueryRow(roleSelect+` WHERE r.name = $1`, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+// CreateRole adds a custom role. It returns ErrRoleAlreadyExists when the
+// name is taken.
+func (r *PermissionRepository) CreateRole(name string, description *string) error {
+	res, err := r.db.Exec(`
+		INSERT INTO roles (name, description) VALUES ($1, $2)
+		ON CONFLICT (name) DO NOTHING
+	`, name, description)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrRoleAlreadyExists
+	}
+	return nil
+}
+
+func (r *PermissionRepository) UpdateRoleDescription(name string, description *string) error {
+	_, err := r.db.Exec(`
+		UPDATE roles SET description = $2, updated_at = NOW() WHERE name = $1
+	`, name, description)
+	return err
+}
+
+// SetRolePermissions replaces the role's grants. It returns
+// ErrPermissionNotFound when one of the permissions does not exist.
+func (r *PermissionRepository) SetRolePermissions(name string, permissions []string) error {
+	var known int
+	if err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM permissions WHERE name = ANY($1)
+	`, pq.Array(permissions)).Scan(&known); err != nil {
+		return err
+	}
+	if known != len(slices.Compact(slices.Sorted(slices.Values(permissions)))) {
+		return ErrPermissionNotFound
+	}
+
+	if _, err := r.db.Exec(`DELETE FROM role_permissions WHERE role_name = $1`, name); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO role_permissions (role_name, permission_name)
+		SELECT $1, UNNEST($2::VARCHAR[])
+		ON CONFLICT DO NOTHING
+	`, name, pq.Array(permissions))
+	return err
+}
+
+// DeleteRole removes a custom role. System roles and roles still assigned to
+// users are kept, reported by ErrRoleProtected and ErrRoleInUse.
+func (r *PermissionRepository) DeleteRole(name string) error {
+	role, err := r.GetRole(name)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return nil
+	}
+	if role.IsSystem {
+		return ErrRoleProtected
+	}
+	if role.UserCount > 0 {
+		return ErrRoleInUse
+	}
+
+	_, err = r.db.Exec(`DELETE FROM roles WHERE name = $1 AND is_system = FALSE`, name)
+	return err
+}
+
+// AssignUserRole changes the user's role. It reports false when the user
+// does not exist.
+func (r *PermissionRepository) AssignUserRole(userID uint, role string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+	`, userID, role)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	mathrand "math/rand"
+	"sort"
 	"strconv"
 	"time"
 
@@ -14,16 +15,81 @@ import (
 )
 
 type QuizQuiz struct {
-	ID               uint       `json:"id"`
-	Code             string     `json:"code"`
-	Title            string     `json:"title"`
-	Description      *string    `json:"description,omitempty"`
-	PassingScore     int        `json:"passing_score"`
-	TimeLimitMinutes *int       `json:"time_limit_minutes,omitempty"`
-	IsActive         bool       `json:"is_active"`
-	CreatedAt        time.Time  `json:"created_at"`
-	UpdatedAt        *time.Time `json:"updated_at"`
-	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+	ID                   uint           `json:"id"`
+	Code                 string         `json:"code"`
+	Title                string         `json:"title"`
+	Description          *string        `json:"description,omitempty"`
+	PassingScore         int            `json:"passing_score"`
+	TimeLimitMinutes     *int           `json:"time_limit_minutes,omitempty"`
+	MaxAttempts          *int           `json:"max_attempts"`
+	CooldownMinutes      int            `json:"cooldown_minutes"`
+	ScorePolicy          string         `json:"score_policy"`
+	ReviewPolicy         string         `json:"review_policy"`
+	OpensAt              *time.Time     `json:"opens_at"`
+	ClosesAt             *time.Time     `json:"closes_at"`
+	Availability         string         `json:"availability"`
+	ClassIDs             pq.StringArray `json:"class_ids"`
+	PublishedVersionID   *uint          `json:"published_version_id"`
+	CertificateEnabled   bool           `json:"certificate_enabled"`
+	LeaderboardOptOut    bool           `json:"leaderboard_opt_out"`
+	LeaderboardAnonymous bool           `json:"leaderboard_anonymous"`
+	LeaderboardPublic    bool           `json:"leaderboard_public"`
+	IsActive             bool           `json:"is_active"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            *time.Time     `json:"updated_at"`
+	DeletedAt            *time.Time     `json:"deleted_at,omitempty"`
+}
+
+const (
+	ScorePolicyBest    = "best"
+	ScorePolicyLatest  = "latest"
+	ScorePolicyAverage = "average"
+
+	ReviewPolicyNever       = "never"
+	ReviewPolicyImmediately = "immediately"
+	ReviewPolicyAfterClose  = "after_close"
+)
+
+// quizQuizColumns is the column list read by scanQuizQuiz. It must be
+// selected from quiz_quizzes without a table alias.
+const quizQuizColumns = `
+	id, code, title, description, passing_score, time_limit_minutes,
+	max_attempts, cooldown_minutes, score_policy, review_policy,
+	opens_at, closes_at, ` + quizAvailabilityExpr + `,
+	ARRAY(
+		SELECT qca.class_id::TEXT FROM quiz_class_assignments qca
+		WHERE qca.quiz_id = quiz_quizzes.id ORDER BY qca.class_id
+	),
+	published_version_id, certificate_enabled, leaderboard_opt_out, leaderboard_anonymous,
+	leaderboard_public, is_active, created_at, updated_at
+`
+
+func scanQuizQuiz(row rowScanner) (*QuizQuiz, error) {
+	quiz := new(QuizQuiz)
+	if err := row.Scan(
+		&quiz.ID, &quiz.Code, &quiz.Title, &quiz.Description, &quiz.PassingScore, &quiz.TimeLimitMinutes,
+		&quiz.MaxAttempts, &quiz.CooldownMinutes, &quiz.ScorePolicy, &quiz.ReviewPolicy,
+		&quiz.OpensAt, &quiz.ClosesAt, &quiz.Availability, &quiz.ClassIDs,
+		&quiz.PublishedVersionID, &quiz.CertificateEnabled, &quiz.LeaderboardOptOut, &quiz.LeaderboardAnonymous,
+		&quiz.LeaderboardPublic, &quiz.IsActive, &quiz.CreatedAt, &quiz.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+// ReviewAvailable reports whether students may see the answer key of their
+// submitted attempts. A quiz counts as closed once its window has ended or it
+// has been deactivated.
+func (q *QuizQuiz) ReviewAvailable() bool {
+	switch q.ReviewPolicy {
+	case ReviewPolicyImmediately:
+		return true
+	case ReviewPolicyAfterClose:
+		return !q.IsActive || q.Availability == QuizAvailabilityClosed
+	default:
+		return false
+	}
 }
 
 func generateQuizCode() (string, error) {
@@ -39,21 +105,58 @@ func generateQuizCode() (string, error) {
 }
 
 type QuizQuestion struct {
-	ID           uint         `json:"id"`
-	QuizID       uint         `json:"quiz_id"`
-	QuestionText string       `json:"question_text"`
-	Options      []QuizOption `json:"options,omitempty"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    *time.Time   `json:"updated_at"`
+	ID               uint             `json:"id"`
+	QuizID           *uint            `json:"quiz_id,omitempty"`
+	BankID           *uint            `json:"bank_id,omitempty"`
+	VersionID        *uint            `json:"-"`
+	QuestionText     string           `json:"question_text"`
+	TextFormat       string           `json:"text_format"`
+	QuestionType     string           `json:"question_type"`
+	ScoringMode      string           `json:"scoring_mode"`
+	MatchMode        *string          `json:"-"`
+	AcceptedAnswers  pq.StringArray   `json:"-"`
+	NumericAnswer    *float64         `json:"-"`
+	NumericTolerance float64          `json:"-"`
+	Tags             pq.StringArray   `json:"-"`
+	Difficulty       *string          `json:"-"`
+	Explanation      *string          `json:"-"`
+	Points           float64          `json:"points"`
+	PenaltyPoints    float64          `json:"penalty_points"`
+	Options          []QuizOption     `json:"options,omitempty"`
+	Attachments      []QuizAttachment `json:"attachments,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        *time.Time       `json:"updated_at"`
+}
+
+// quizQuestionColumns is the column list read by scanQuizQuestion.
+const quizQuestionColumns = `
+	id, quiz_id, bank_id, version_id, question_text, text_format, question_type, scoring_mode, match_mode,
+	accepted_answers, numeric_answer, numeric_tolerance, tags, difficulty, explanation,
+	points, penalty_points, created_at, updated_at
+`
+
+func scanQuizQuestion(row rowScanner) (*QuizQuestion, error) {
+	q := new(QuizQuestion)
+	if err := row.Scan(
+		&q.ID, &q.QuizID, &q.BankID, &q.VersionID, &q.QuestionText, &q.TextFormat, &q.QuestionType, &q.ScoringMode, &q.MatchMode,
+		&q.AcceptedAnswers, &q.NumericAnswer, &q.NumericTolerance, &q.Tags, &q.Difficulty, &q.Explanation,
+		&q.Points, &q.PenaltyPoints, &q.CreatedAt, &q.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return q, nil
 }
 
 type QuizOption struct {
-	ID         uint       `json:"id"`
-	QuestionID uint       `json:"question_id"`
-	OptionText string     `json:"option_text"`
-	IsCorrect  bool       `json:"-"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  *time.Time `json:"updated_at"`
+	ID          uint             `json:"id"`
+	QuestionID  uint             `json:"question_id"`
+	OptionText  string           `json:"option_text"`
+	TextFormat  string           `json:"text_format"`
+	IsCorrect   bool             `json:"-"`
+	Explanation *string          `json:"-"`
+	Attachments []QuizAttachment `json:"attachments,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   *time.Time       `json:"updated_at"`
 }
 
 type QuizOptionAdmin struct {
@@ -68,27 +171,257 @@ type QuizOptionAdmin struct {
 type QuizAttempt struct {
 	ID                   uint            `json:"id"`
 	QuizID               uint            `json:"quiz_id"`
+	VersionID            *uint           `json:"version_id"`
 	UserID               uint            `json:"user_id"`
 	Status               string          `json:"status"`
 	Score                *float64        `json:"score"`
+	RawScore             *float64        `json:"raw_score"`
+	MaxScore             *float64        `json:"max_score"`
 	QuestionIDs          pq.Int64Array   `json:"-"`
 	OptionOrder          json.RawMessage `json:"-"`
 	CurrentQuestionIndex int             `json:"-"`
 	StartedAt            time.Time       `json:"started_at"`
 	SubmittedAt          *time.Time      `json:"submitted_at"`
+	ExpiresAt            *time.Time      `json:"expires_at"`
+	RemainingSeconds     *int            `json:"remaining_seconds,omitempty"`
+	AutoSubmitted        bool            `json:"auto_submitted"`
 	ResetAt              *time.Time      `json:"reset_at,omitempty"`
 	ResetBy              *uint           `json:"reset_by,omitempty"`
 	CreatedAt            time.Time       `json:"created_at"`
 	UpdatedAt            *time.Time      `json:"updated_at"`
 }
 
+// HasQuestion reports whether the question was drawn for the attempt.
+func (a *QuizAttempt) HasQuestion(questionID uint) bool {
+	for _, id := range a.QuestionIDs {
+		if uint(id) == questionID {
+			return true
+		}
+	}
+	return false
+}
+
+// QuizSubmitGracePeriod is how long after the deadline a submission is still
+// accepted, to absorb latency between the client timer and the server.
+const QuizSubmitGracePeriod = 30 * time.Second
+
+// quizAttemptColumns is the column list read by scanQuizAttempt. The remaining
+// time is computed by the database so it shares the clock used for expires_at.
+const quizAttemptColumns = `
+	id, quiz_id, version_id, user_id, status, score, raw_score, max_score, question_ids, option_order,
+	current_question_index, started_at, submitted_at, expires_at,
+	CASE
+		WHEN expires_at IS NULL THEN NULL
+		ELSE GREATEST(CEIL(EXTRACT(EPOCH FROM (expires_at - NOW())))::INT, 0)
+	END AS remaining_seconds,
+	auto_submitted, reset_at, reset_by, created_at, updated_at
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanQuizAttempt(row rowScanner) (*QuizAttempt, error) {
+	attempt := new(QuizAttempt)
+	if err := row.Scan(
+		&attempt.ID, &attempt.QuizID, &attempt.VersionID, &attempt.UserID, &attempt.Status, &attempt.Score, &attempt.RawScore, &attempt.MaxScore,
+		&attempt.QuestionIDs, &attempt.OptionOrder, &attempt.CurrentQuestionIndex,
+		&attempt.StartedAt, &attempt.SubmittedAt, &attempt.ExpiresAt, &attempt.RemainingSeconds,
+		&attempt.AutoSubmitted, &attempt.ResetAt, &attempt.ResetBy, &attempt.CreatedAt, &attempt.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// Raw and maximum points of an attempt, both counted over the questions drawn
+// for it. Questions drawn but left blank add their points to the maximum and
+// nothing to the raw score. score is the raw points as a percentage of the
+// maximum, floored at 0 so penalties never push it negative.
+const (
+	attemptRawScoreExpr = `COALESCE((SELECT SUM(ans.points_awarded) FROM quiz_answers ans WHERE ans.attempt_id = quiz_attempts.id AND ans.question_id = ANY(quiz_attempts.question_ids)), 0)`
+	attemptMaxScoreExpr = `COALESCE((SELECT SUM(q.points) FROM quiz_questions q WHERE q.id = ANY(quiz_attempts.question_ids)), 0)`
+	attemptScoreExpr    = `CASE
+		        WHEN ` + attemptMaxScoreExpr + ` > 0
+		        THEN ROUND(GREATEST(` + attemptRawScoreExpr + `, 0) / ` + attemptMaxScoreExpr + ` * 100, 2)
+		        ELSE 0
+		    END`
+)
+
+// finalizeAttemptsQuery completes the in-progress attempts matching condition
+// and scores them against every question drawn for the attempt. $1 flags
+// whether the system submitted the attempt; auto-submitted attempts are
+// stamped at their deadline rather than at NOW().
+func finalizeAttemptsQuery(condition string) string {
+	return `
+		UPDATE quiz_attempts
+		SET status         = 'completed',
+		    raw_score      = ` + attemptRawScoreExpr + `,
+		    max_score      = ` + attemptMaxScoreExpr + `,
+		    score          = ` + attemptScoreExpr + `,
+		    auto_submitted = $1,
+		    submitted_at   = CASE WHEN $1 THEN LEAST(NOW(), COALESCE(expires_at, NOW())) ELSE NOW() END,
+		    updated_at     = NOW()
+		WHERE status = 'in_progress' AND ` + condition + `
+		RETURNING ` + quizAttemptColumns
+}
+
+const attemptExpiredCondition = `expires_at IS NOT NULL AND expires_at + make_interval(secs => $2) < NOW()`
+
+// QuizAttemptSummary describes how a student stands against a quiz's attempt
+// policy and which score counts for them.
+type QuizAttemptSummary struct {
+	QuizID            uint       `json:"quiz_id"`
+	MaxAttempts       *int       `json:"max_attempts"`
+	CooldownMinutes   int        `json:"cooldown_minutes"`
+	ScorePolicy       string     `json:"score_policy"`
+	AttemptsUsed      int        `json:"attempts_used"`
+	AttemptsRemaining *int       `json:"attempts_remaining"`
+	HasActiveAttempt  bool       `json:"has_active_attempt"`
+	NextAttemptAt     *time.Time `json:"next_attempt_at"`
+	CanStartAttempt   bool       `json:"can_start_attempt"`
+	FinalScore        *float64   `json:"final_score"`
+}
+
+// attemptSummaryQuery aggregates a user's non-reset attempts per quiz. $1 is
+// the user; condition filters the quizzes. Cooldown is evaluated against the
+// database clock like the other attempt deadlines.
+func attemptSummaryQuery(condition string) string {
+	return `
+		SELECT
+			q.id, q.max_attempts, q.cooldown_minutes, q.score_policy,
+			COUNT(a.id),
+			COUNT(a.id) FILTER (WHERE a.status = 'in_progress') > 0,
+			CASE
+				WHEN MAX(a.submitted_at) + make_interval(mins => q.cooldown_minutes) > NOW()
+				THEN MAX(a.submitted_at) + make_interval(mins => q.cooldown_minutes)
+			END,
+			CASE q.score_policy
+				WHEN 'best' THEN MAX(a.score)
+				WHEN 'average' THEN ROUND(AVG(a.score), 2)
+				ELSE (ARRAY_AGG(a.score ORDER BY a.submitted_at DESC) FILTER (WHERE a.status = 'completed'))[1]
+			END
+		FROM quiz_quizzes q
+			LEFT JOIN quiz_attempts a ON a.quiz_id = q.id AND a.user_id = $1 AND a.status != 'reset'
+		WHERE ` + condition + `
+		GROUP BY q.id
+	`
+}
+
+func scanAttemptSummary(row rowScanner) (*QuizAttemptSummary, error) {
+	summary := new(QuizAttemptSummary)
+	if err := row.Scan(
+		&summary.QuizID, &summary.MaxAttempts, &summary.CooldownMinutes, &summary.ScorePolicy,
+		&summary.AttemptsUsed, &summary.HasActiveAttempt, &summary.NextAttemptAt, &summary.FinalScore,
+	); err != nil {
+		return nil, err
+	}
+
+	if summary.MaxAttempts != nil {
+		remaining := max(*summary.MaxAttempts-summary.AttemptsUsed, 0)
+		summary.AttemptsRemaining = &remaining
+	}
+	summary.CanStartAttempt = !summary.HasActiveAttempt &&
+		(summary.AttemptsRemaining == nil || *summary.AttemptsRemaining > 0) &&
+		summary.NextAttemptAt == nil
+	return summary, nil
+}
+
 type QuizAnswer struct {
-	ID         uint      `json:"id"`
-	AttemptID  uint      `json:"attempt_id"`
-	QuestionID uint      `json:"question_id"`
-	OptionID   uint      `json:"option_id"`
-	IsCorrect  bool      `json:"is_correct"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID            uint          `json:"id"`
+	AttemptID     uint          `json:"attempt_id"`
+	QuestionID    uint          `json:"question_id"`
+	OptionID      *uint         `json:"option_id"`
+	OptionIDs     pq.Int64Array `json:"option_ids,omitempty"`
+	TextAnswer    *string       `json:"text_answer,omitempty"`
+	NumericAnswer *float64      `json:"numeric_answer,omitempty"`
+	IsCorrect     bool          `json:"is_correct"`
+	Credit        float64       `json:"credit"`
+	PointsAwarded float64       `json:"points_awarded"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// quizAnswerColumns is the column list read by scanQuizAnswer.
+const quizAnswerColumns = `
+	id, attempt_id, question_id, option_id, option_ids, text_answer,
+	numeric_answer, is_correct, credit, points_awarded, created_at
+`
+
+func scanQuizAnswer(row rowScanner) (*QuizAnswer, error) {
+	ans := new(QuizAnswer)
+	if err := row.Scan(
+		&ans.ID, &ans.AttemptID, &ans.QuestionID, &ans.OptionID, &ans.OptionIDs, &ans.TextAnswer,
+		&ans.NumericAnswer, &ans.IsCorrect, &ans.Credit, &ans.PointsAwarded, &ans.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+// upsertAnswerQuery stores one graded answer per (attempt, question).
+const upsertAnswerQuery = `
+	INSERT INTO quiz_answers (
+		attempt_id, question_id, option_id, option_ids, text_answer, numeric_answer,
+		is_correct, credit, points_awarded
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (attempt_id, question_id) DO UPDATE
+		SET option_id      = EXCLUDED.option_id,
+		    option_ids     = EXCLUDED.option_ids,
+		    text_answer    = EXCLUDED.text_answer,
+		    numeric_answer = EXCLUDED.numeric_answer,
+		    is_correct     = EXCLUDED.is_correct,
+		    credit         = EXCLUDED.credit,
+		    points_awarded = EXCLUDED.points_awarded
+	RETURNING ` + quizAnswerColumns
+
+// saveGradedAnswer grades the answer against its question's answer key and
+// upserts it. Answers that do not fit the question return ErrQuizInvalidAnswer.
+func saveGradedAnswer(db facades.DBExecutor, answer *QuizAnswer) error {
+	question, err := scanQuizQuestion(db.QueryRow(
+		`SELECT `+quizQuestionColumns+` FROM quiz_questions WHERE id = $1`,
+		answer.QuestionID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrQuizInvalidAnswer
+		}
+		return err
+	}
+
+	rows, err := db.Query(
+		`SELECT id, question_id, option_text, is_correct, created_at, updated_at FROM quiz_options WHERE question_id = $1`,
+		question.ID,
+	)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var opt QuizOption
+		if err := rows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.IsCorrect, &opt.CreatedAt, &opt.UpdatedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		question.Options = append(question.Options, opt)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := question.Grade(answer); err != nil {
+		return err
+	}
+
+	saved, err := scanQuizAnswer(db.QueryRow(upsertAnswerQuery,
+		answer.AttemptID, answer.QuestionID, answer.OptionID, answer.OptionIDs,
+		answer.TextAnswer, answer.NumericAnswer, answer.IsCorrect, answer.Credit, answer.PointsAwarded,
+	))
+	if err != nil {
+		return err
+	}
+	*answer = *saved
+	return nil
 }
 
 type QuizRepository struct {
@@ -106,15 +439,11 @@ func (r *QuizRepository) WithExecutor(executor facades.DBExecutor) *QuizReposito
 
 func (r *QuizRepository) GetActiveQuizWithQuestions(quizID uint, questionIDs []int64) (*QuizQuiz, []QuizQuestion, error) {
 	quizQuery := `
-		SELECT id, code, title, description, passing_score, time_limit_minutes, is_active, created_at, updated_at
+		SELECT ` + quizQuizColumns + `
 		FROM quiz_quizzes
-		WHERE id = $1 AND is_active = TRUE AND deleted_at IS NULL
+		WHERE id = $1 AND is_active = TRUE AND deleted_at IS NULL AND published_version_id IS NOT NULL
 	`
-	quiz := new(QuizQuiz)
-	err := r.db.QueryRow(quizQuery, quizID).Scan(
-		&quiz.ID, &quiz.Code, &quiz.Title, &quiz.Description, &quiz.PassingScore,
-		&quiz.TimeLimitMinutes, &quiz.IsActive, &quiz.CreatedAt, &quiz.UpdatedAt,
-	)
+	quiz, err := scanQuizQuiz(r.db.QueryRow(quizQuery, quizID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil, nil
@@ -125,7 +454,7 @@ func (r *QuizRepository) GetActiveQuizWithQuestions(quizID uint, questionIDs []i
 	var qRows *sql.Rows
 	if len(questionIDs) > 0 {
 		questionsQuery := `
-			SELECT id, quiz_id, question_text, created_at, updated_at
+			SELECT ` + quizQuestionColumns + `
 			FROM quiz_questions
 			WHERE id = ANY($1)
 			ORDER BY array_position($1, id)
@@ -133,12 +462,12 @@ func (r *QuizRepository) GetActiveQuizWithQuestions(quizID uint, questionIDs []i
 		qRows, err = r.db.Query(questionsQuery, pq.Array(questionIDs))
 	} else {
 		questionsQuery := `
-			SELECT id, quiz_id, question_text, created_at, updated_at
+			SELECT ` + quizQuestionColumns + `
 			FROM quiz_questions
-			WHERE quiz_id = $1
+			WHERE quiz_id = $1 AND version_id = $2
 			ORDER BY id ASC
 		`
-		qRows, err = r.db.Query(questionsQuery, quizID)
+		qRows, err = r.db.Query(questionsQuery, quizID, quiz.PublishedVersionID)
 	}
 
 	if err != nil {
@@ -151,18 +480,18 @@ func (r *QuizRepository) GetActiveQuizWithQuestions(quizID uint, questionIDs []i
 	questionMap := make(map[uint]*QuizQuestion)
 
 	for qRows.Next() {
-		q := QuizQuestion{}
-		if err := qRows.Scan(&q.ID, &q.QuizID, &q.QuestionText, &q.CreatedAt, &q.UpdatedAt); err != nil {
+		q, err := scanQuizQuestion(qRows)
+		if err != nil {
 			return nil, nil, err
 		}
 		q.Options = make([]QuizOption, 0)
-		questions = append(questions, q)
+		questions = append(questions, *q)
 		loadedIDs = append(loadedIDs, q.ID)
 	}
 
 	if len(loadedIDs) > 0 {
 		optionsQuery := `
-			SELECT id, question_id, option_text, created_at, updated_at
+			SELECT id, question_id, option_text, text_format, created_at, updated_at
 			FROM quiz_options
 			WHERE question_id = ANY($1)
 		`
@@ -183,7 +512,7 @@ func (r *QuizRepository) GetActiveQuizWithQuestions(quizID uint, questionIDs []i
 
 		for oRows.Next() {
 			opt := QuizOption{}
-			if err := oRows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.CreatedAt, &opt.UpdatedAt); err != nil {
+			if err := oRows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.TextFormat, &opt.CreatedAt, &opt.UpdatedAt); err != nil {
 				return nil, nil, err
 			}
 			if q, ok := questionMap[opt.QuestionID]; ok {
@@ -198,6 +527,10 @@ func (r *QuizRepository) GetActiveQuizWithQuestions(quizID uint, questionIDs []i
 		}
 	}
 
+	if err := loadQuizAttachments(r.db, questions); err != nil {
+		return nil, nil, err
+	}
+
 	return quiz, questions, nil
 }
 
@@ -207,35 +540,42 @@ func (r *QuizRepository) GetActiveQuizWithQuestionsV2(quizID uint, attempt *Quiz
 		return nil, nil, err
 	}
 
-	if attempt.OptionOrder != nil {
-		var optionOrder map[string][]int64
-		if err := json.Unmarshal(attempt.OptionOrder, &optionOrder); err == nil {
-			for i, q := range questions {
-				qIDStr := strconv.FormatUint(uint64(q.ID), 10)
-				if order, ok := optionOrder[qIDStr]; ok {
-					orderedOptions := make([]QuizOption, 0, len(q.Options))
-					optMap := make(map[int64]QuizOption)
-					for _, opt := range q.Options {
-						optMap[int64(opt.ID)] = opt
-					}
-					for _, optID := range order {
-						if opt, found := optMap[optID]; found {
-							orderedOptions = append(orderedOptions, opt)
-						}
-					}
-					questions[i].Options = orderedOptions
+	applyOptionOrder(attempt, questions)
+	return quiz, questions, nil
+}
+
+// applyOptionOrder reorders each question's options the way they were
+// shuffled for the attempt.
+func applyOptionOrder(attempt *QuizAttempt, questions []QuizQuestion) {
+	if attempt.OptionOrder == nil {
+		return
+	}
+	var optionOrder map[string][]int64
+	if err := json.Unmarshal(attempt.OptionOrder, &optionOrder); err != nil {
+		return
+	}
+	for i, q := range questions {
+		qIDStr := strconv.FormatUint(uint64(q.ID), 10)
+		if order, ok := optionOrder[qIDStr]; ok {
+			orderedOptions := make([]QuizOption, 0, len(q.Options))
+			optMap := make(map[int64]QuizOption)
+			for _, opt := range q.Options {
+				optMap[int64(opt.ID)] = opt
+			}
+			for _, optID := range order {
+				if opt, found := optMap[optID]; found {
+					orderedOptions = append(orderedOptions, opt)
 				}
 			}
+			questions[i].Options = orderedOptions
 		}
 	}
-
-	return quiz, questions, nil
 }
 
 func (r *QuizRepository) GetQuizIDByCode(code string) (uint, error) {
 	var id uint
 	err := r.db.QueryRow(
-		`SELECT id FROM quiz_quizzes WHERE code = $1 AND is_active = TRUE AND deleted_at IS NULL`,
+		`SELECT id FROM quiz_quizzes WHERE code = $1 AND is_active = TRUE AND deleted_at IS NULL AND published_version_id IS NOT NULL`,
 		code,
 	).Scan(&id)
 	if err != nil {
@@ -249,21 +589,13 @@ func (r *QuizRepository) GetQuizIDByCode(code string) (uint, error) {
 
 func (r *QuizRepository) GetActiveAttempt(userID, quizID uint) (*QuizAttempt, error) {
 	query := `
-		SELECT id, quiz_id, user_id, status, score, question_ids, option_order,
-		       current_question_index, started_at, submitted_at, reset_at, reset_by,
-		       created_at, updated_at
+		SELECT ` + quizAttemptColumns + `
 		FROM quiz_attempts
 		WHERE user_id = $1 AND quiz_id = $2 AND status != 'reset'
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
-	attempt := new(QuizAttempt)
-	err := r.db.QueryRow(query, userID, quizID).Scan(
-		&attempt.ID, &attempt.QuizID, &attempt.UserID, &attempt.Status, &attempt.Score,
-		&attempt.QuestionIDs, &attempt.OptionOrder, &attempt.CurrentQuestionIndex,
-		&attempt.StartedAt, &attempt.SubmittedAt,
-		&attempt.ResetAt, &attempt.ResetBy, &attempt.CreatedAt, &attempt.UpdatedAt,
-	)
+	attempt, err := scanQuizAttempt(r.db.QueryRow(query, userID, quizID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -287,11 +619,10 @@ func (r *QuizRepository) GetQuestionByAttemptIndex(attempt *QuizAttempt, index i
 	}
 	qID := attempt.QuestionIDs[index]
 
-	q := &QuizQuestion{}
-	err := r.db.QueryRow(
-		`SELECT id, quiz_id, question_text, created_at, updated_at FROM quiz_questions WHERE id = $1`,
+	q, err := scanQuizQuestion(r.db.QueryRow(
+		`SELECT `+quizQuestionColumns+` FROM quiz_questions WHERE id = $1`,
 		qID,
-	).Scan(&q.ID, &q.QuizID, &q.QuestionText, &q.CreatedAt, &q.UpdatedAt)
+	))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -312,7 +643,7 @@ func (r *QuizRepository) GetQuestionByAttemptIndex(attempt *QuizAttempt, index i
 
 	if len(optionIDs) > 0 {
 		rows, err := r.db.Query(
-			`SELECT id, question_id, option_text, created_at, updated_at FROM quiz_options WHERE id = ANY($1)`,
+			`SELECT id, question_id, option_text, text_format, created_at, updated_at FROM quiz_options WHERE id = ANY($1)`,
 			pq.Array(optionIDs),
 		)
 		if err != nil {
@@ -322,7 +653,7 @@ func (r *QuizRepository) GetQuestionByAttemptIndex(attempt *QuizAttempt, index i
 		optMap := make(map[int64]QuizOption)
 		for rows.Next() {
 			var opt QuizOption
-			if err := rows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.CreatedAt, &opt.UpdatedAt); err != nil {
+			if err := rows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.TextFormat, &opt.CreatedAt, &opt.UpdatedAt); err != nil {
 				return nil, err
 			}
 			optMap[int64(opt.ID)] = opt
@@ -334,7 +665,7 @@ func (r *QuizRepository) GetQuestionByAttemptIndex(attempt *QuizAttempt, index i
 		}
 	} else {
 		rows, err := r.db.Query(
-			`SELECT id, question_id, option_text, created_at, updated_at FROM quiz_options WHERE question_id = $1 ORDER BY id ASC`,
+			`SELECT id, question_id, option_text, text_format, created_at, updated_at FROM quiz_options WHERE question_id = $1 ORDER BY id ASC`,
 			qID,
 		)
 		if err != nil {
@@ -343,14 +674,19 @@ func (r *QuizRepository) GetQuestionByAttemptIndex(attempt *QuizAttempt, index i
 		defer rows.Close()
 		for rows.Next() {
 			var opt QuizOption
-			if err := rows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.CreatedAt, &opt.UpdatedAt); err != nil {
+			if err := rows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.TextFormat, &opt.CreatedAt, &opt.UpdatedAt); err != nil {
 				return nil, err
 			}
 			q.Options = append(q.Options, opt)
 		}
 	}
 
-	return q, nil
+	loaded := []QuizQuestion{*q}
+	if err := loadQuizAttachments(r.db, loaded); err != nil {
+		return nil, err
+	}
+
+	return &loaded[0], nil
 }
 
 func (r *QuizRepository) CreateAttempt(attempt *QuizAttempt) error {
@@ -360,8 +696,44 @@ func (r *QuizRepository) CreateAttempt(attempt *QuizAttempt) error {
 	}
 	defer tx.Rollback()
 
+	// Serialize attempt creation per user and quiz so concurrent requests
+	// cannot both pass the policy check.
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1, $2)`, int32(attempt.QuizID), int32(attempt.UserID)); err != nil {
+		return err
+	}
+
+	if err := checkQuizAccess(tx, attempt.QuizID, attempt.UserID); err != nil {
+		return err
+	}
+
+	summary, err := scanAttemptSummary(tx.QueryRow(attemptSummaryQuery(`q.id = $2`), attempt.UserID, attempt.QuizID))
+	if err != nil {
+		return err
+	}
+	switch {
+	case summary.HasActiveAttempt:
+		return ErrQuizAttemptInProgress
+	case summary.AttemptsRemaining != nil && *summary.AttemptsRemaining == 0:
+		return ErrQuizMaxAttemptsReached
+	case summary.NextAttemptAt != nil:
+		return ErrQuizAttemptCooldown
+	}
+
+	// The attempt is pinned to the published version so later edits of the
+	// draft never change what it contains.
+	var versionID uint
+	if err := tx.QueryRow(
+		`SELECT published_version_id FROM quiz_quizzes WHERE id = $1`, attempt.QuizID,
+	).Scan(&versionID); err != nil {
+		return err
+	}
+
 	var questionIDs []int64
-	qRows, err := tx.Query(`SELECT id FROM quiz_questions WHERE quiz_id = $1 ORDER BY id ASC`, attempt.QuizID)
+	questionTypes := make(map[int64]string)
+	qRows, err := tx.Query(
+		`SELECT id, question_type FROM quiz_questions WHERE quiz_id = $1 AND version_id = $2 ORDER BY id ASC`,
+		attempt.QuizID, versionID,
+	)
 	if err != nil {
 		return err
 	}
@@ -369,12 +741,20 @@ func (r *QuizRepository) CreateAttempt(attempt *QuizAttempt) error {
 
 	for qRows.Next() {
 		var id int64
-		if err := qRows.Scan(&id); err != nil {
+		var questionType string
+		if err := qRows.Scan(&id, &questionType); err != nil {
 			return err
 		}
 		questionIDs = append(questionIDs, id)
+		questionTypes[id] = questionType
 	}
 
+	sampledIDs, err := sampleBankQuestions(tx, versionID, questionIDs, questionTypes)
+	if err != nil {
+		return err
+	}
+	questionIDs = append(questionIDs, sampledIDs...)
+
 	source := mathrand.NewSource(time.Now().UnixNano())
 	rng := mathrand.New(source)
 
@@ -388,7 +768,7 @@ func (r *QuizRepository) CreateAttempt(attempt *QuizAttempt) error {
 	optionOrder := make(map[string][]int64)
 	for _, qID := range questionIDs {
 		var optIDs []int64
-		rows, err := tx.Query(`SELECT id FROM quiz_options WHERE question_id = $1`, qID)
+		rows, err := tx.Query(`SELECT id FROM quiz_options WHERE question_id = $1 ORDER BY id ASC`, qID)
 		if err != nil {
 			return err
 		}
@@ -399,7 +779,8 @@ func (r *QuizRepository) CreateAttempt(attempt *QuizAttempt) error {
 		}
 		rows.Close()
 
-		if len(optIDs) > 1 {
+		// True/False keeps its fixed order so "True" always comes first.
+		if len(optIDs) > 1 && questionTypes[qID] != QuestionTypeTrueFalse {
 			rng.Shuffle(len(optIDs), func(i, j int) {
 				optIDs[i], optIDs[j] = optIDs[j], optIDs[i]
 			})
@@ -410,73 +791,127 @@ func (r *QuizRepository) CreateAttempt(attempt *QuizAttempt) error {
 	attempt.OptionOrder = optOrderJSON
 
 	query := `
-		INSERT INTO quiz_attempts (quiz_id, user_id, status, question_ids, option_order, current_question_index, started_at)
-		VALUES ($1, $2, 'in_progress', $3, $4, 0, NOW())
-		RETURNING id, started_at, created_at, updated_at
-	`
-	err = tx.QueryRow(query, attempt.QuizID, attempt.UserID, pq.Array(attempt.QuestionIDs), attempt.OptionOrder).Scan(
-		&attempt.ID, &attempt.StartedAt, &attempt.CreatedAt, &attempt.UpdatedAt,
-	)
+		INSERT INTO quiz_attempts (
+			quiz_id, version_id, user_id, status, question_ids, option_order, current_question_index, started_at, expires_at
+		)
+		VALUES (
+			$1, $2, $3, 'in_progress', $4, $5, 0, NOW(),
+			(SELECT LEAST(NOW() + make_interval(mins => time_limit_minutes), closes_at) FROM quiz_quizzes WHERE id = $1)
+		)
+		RETURNING ` + quizAttemptColumns
+	created, err := scanQuizAttempt(tx.QueryRow(query,
+		attempt.QuizID, versionID, attempt.UserID, pq.Array(attempt.QuestionIDs), attempt.OptionOrder,
+	))
 	if err != nil {
 		return err
 	}
-	attempt.CurrentQuestionIndex = 0
+	*attempt = *created
 
 	return tx.Commit()
 }
 
-func (r *QuizRepository) SubmitAnswers(attemptID uint, answers []QuizAnswer) (*QuizAttempt, error) {
+// SaveAnswer grades and upserts a single answer for an in-progress attempt.
+func (r *QuizRepository) SaveAnswer(answer *QuizAnswer) error {
+	return saveGradedAnswer(r.db, answer)
+}
+
+// ClearAnswer removes the saved answer to a question, leaving it blank so it
+// earns neither points nor a penalty. It reports false when there was none.
+func (r *QuizRepository) ClearAnswer(attemptID, questionID uint) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM quiz_answers WHERE attempt_id = $1 AND question_id = $2`, attemptID, questionID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// GetSavedAnswers returns the saved answer per question for an attempt.
+func (r *QuizRepository) GetSavedAnswers(attemptID uint) (map[uint]*QuizAnswer, error) {
+	rows, err := r.db.Query(`SELECT `+quizAnswerColumns+` FROM quiz_answers WHERE attempt_id = $1`, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	saved := make(map[uint]*QuizAnswer)
+	for rows.Next() {
+		ans, err := scanQuizAnswer(rows)
+		if err != nil {
+			return nil, err
+		}
+		saved[ans.QuestionID] = ans
+	}
+	return saved, rows.Err()
+}
+
+// SubmitAnswers saves any answers sent with the final submission and then
+// finalizes the attempt. Answers saved earlier through SaveAnswer are kept.
+// Answers to questions not drawn for the attempt return ErrQuizInvalidAnswer.
+func (r *QuizRepository) SubmitAnswers(attempt *QuizAttempt, answers []QuizAnswer) (*QuizAttempt, error) {
+	for _, answer := range answers {
+		if !attempt.HasQuestion(answer.QuestionID) {
+			return nil, ErrQuizInvalidAnswer
+		}
+	}
+
 	tx, err := r.raw.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	insertAnswerQuery := `
-		INSERT INTO quiz_answers (attempt_id, question_id, option_id, is_correct)
-		VALUES ($1, $2, $3, (SELECT is_correct FROM quiz_options WHERE id = $3))
-		ON CONFLICT (attempt_id, question_id) DO UPDATE
-			SET option_id  = EXCLUDED.option_id,
-			    is_correct = EXCLUDED.is_correct
-	`
-	for _, ans := range answers {
-		if _, err := tx.Exec(insertAnswerQuery, attemptID, ans.QuestionID, ans.OptionID); err != nil {
+	for i := range answers {
+		answers[i].AttemptID = attempt.ID
+		if err := saveGradedAnswer(tx, &answers[i]); err != nil {
 			return nil, err
 		}
 	}
 
-	scoreQuery := `
-		SELECT
-			ROUND(
-				(COUNT(*) FILTER (WHERE a.is_correct = TRUE)::NUMERIC
-				/ NULLIF(COUNT(*), 0)) * 100,
-			2) AS score
-		FROM quiz_answers a
-		WHERE a.attempt_id = $1
-	`
-	var score float64
-	if err := tx.QueryRow(scoreQuery, attemptID).Scan(&score); err != nil {
+	completed, err := scanQuizAttempt(tx.QueryRow(finalizeAttemptsQuery(`id = $2`), false, attempt.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrQuizAttemptClosed
+		}
 		return nil, err
 	}
 
-	attempt := new(QuizAttempt)
-	updateQuery := `
-		UPDATE quiz_attempts
-		SET status       = 'completed',
-		    score        = $1,
-		    submitted_at = NOW(),
-		    updated_at   = NOW()
-		WHERE id = $2
-		RETURNING id, quiz_id, user_id, status, score, started_at, submitted_at, created_at, updated_at
-	`
-	if err := tx.QueryRow(updateQuery, score, attemptID).Scan(
-		&attempt.ID, &attempt.QuizID, &attempt.UserID, &attempt.Status, &attempt.Score,
-		&attempt.StartedAt, &attempt.SubmittedAt, &attempt.CreatedAt, &attempt.UpdatedAt,
-	); err != nil {
+	return completed, tx.Commit()
+}
+
+// FinalizeIfExpired auto-submits the attempt with the answers saved so far
+// when its deadline plus QuizSubmitGracePeriod has passed. It returns nil when
+// the attempt is still within its time limit.
+func (r *QuizRepository) FinalizeIfExpired(attemptID uint) (*QuizAttempt, error) {
+	query := finalizeAttemptsQuery(attemptExpiredCondition + ` AND id = $3`)
+	attempt, err := scanQuizAttempt(r.db.QueryRow(query, true, QuizSubmitGracePeriod.Seconds(), attemptID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
 		return nil, err
 	}
+	return attempt, nil
+}
 
-	return attempt, tx.Commit()
+// FinalizeExpiredAttempts auto-submits every in-progress attempt whose
+// deadline has passed. It is run periodically by the quiz background job.
+func (r *QuizRepository) FinalizeExpiredAttempts() ([]*QuizAttempt, error) {
+	rows, err := r.db.Query(finalizeAttemptsQuery(attemptExpiredCondition), true, QuizSubmitGracePeriod.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := make([]*QuizAttempt, 0)
+	for rows.Next() {
+		attempt, err := scanQuizAttempt(rows)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, attempt)
+	}
+	return attempts, rows.Err()
 }
 
 func (r *QuizRepository) ResetAttempt(userID, quizID uint, adminID uint) error {
@@ -505,16 +940,12 @@ func (r *QuizRepository) ResetAttempt(userID, quizID uint, adminID uint) error {
 
 func (r *QuizRepository) GetAttemptByID(attemptID uint) (*QuizAttempt, []QuizAnswer, error) {
 	attemptQuery := `
-		SELECT id, quiz_id, user_id, status, score, question_ids, started_at, submitted_at, reset_at, reset_by, created_at, updated_at
+		SELECT ` + quizAttemptColumns + `
 		FROM quiz_attempts
 		WHERE id = $1
 	`
-	attempt := new(QuizAttempt)
-	if err := r.db.QueryRow(attemptQuery, attemptID).Scan(
-		&attempt.ID, &attempt.QuizID, &attempt.UserID, &attempt.Status, &attempt.Score,
-		&attempt.QuestionIDs, &attempt.OptionOrder, &attempt.StartedAt, &attempt.SubmittedAt, &attempt.ResetAt,
-		&attempt.ResetBy, &attempt.CreatedAt, &attempt.UpdatedAt,
-	); err != nil {
+	attempt, err := scanQuizAttempt(r.db.QueryRow(attemptQuery, attemptID))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil, nil
 		}
@@ -522,7 +953,7 @@ func (r *QuizRepository) GetAttemptByID(attemptID uint) (*QuizAttempt, []QuizAns
 	}
 
 	answersQuery := `
-		SELECT id, attempt_id, question_id, option_id, is_correct, created_at
+		SELECT ` + quizAnswerColumns + `
 		FROM quiz_answers
 		WHERE attempt_id = $1
 	`
@@ -534,21 +965,66 @@ func (r *QuizRepository) GetAttemptByID(attemptID uint) (*QuizAttempt, []QuizAns
 
 	answers := make([]QuizAnswer, 0)
 	for rows.Next() {
-		ans := QuizAnswer{}
-		if err := rows.Scan(&ans.ID, &ans.AttemptID, &ans.QuestionID, &ans.OptionID, &ans.IsCorrect, &ans.CreatedAt); err != nil {
+		ans, err := scanQuizAnswer(rows)
+		if err != nil {
 			return nil, nil, err
 		}
-		answers = append(answers, ans)
+		answers = append(answers, *ans)
 	}
 
 	return attempt, answers, nil
 }
 
+// GetAttemptReview returns a submitted attempt with its answers, the quiz
+// (even when inactive) and the attempt's questions in the order they were
+// shown, including the answer key and explanations. The quiz is nil when the
+// attempt does not exist or its quiz has been deleted.
+func (r *QuizRepository) GetAttemptReview(attemptID uint) (*QuizQuiz, *QuizAttempt, []QuizAnswer, []QuizQuestion, error) {
+	attempt, answers, err := r.GetAttemptByID(attemptID)
+	if err != nil || attempt == nil {
+		return nil, nil, nil, nil, err
+	}
+
+	quiz, err := scanQuizQuiz(r.db.QueryRow(`
+		SELECT `+quizQuizColumns+`
+		FROM quiz_quizzes
+		WHERE id = $1 AND deleted_at IS NULL
+	`, attempt.QuizID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, nil, nil, nil
+		}
+		return nil, nil, nil, nil, err
+	}
+
+	var questions []QuizQuestion
+	if len(attempt.QuestionIDs) > 0 {
+		questions, err = listQuestionsWithAnswers(r.db, `id = ANY($1)`, pq.Array(attempt.QuestionIDs))
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		position := make(map[uint]int, len(attempt.QuestionIDs))
+		for i, id := range attempt.QuestionIDs {
+			position[uint(id)] = i
+		}
+		sort.Slice(questions, func(i, j int) bool {
+			return position[questions[i].ID] < position[questions[j].ID]
+		})
+	} else {
+		questions, err = listQuestionsWithAnswers(r.db,
+			`quiz_id = $1 AND version_id IS NOT DISTINCT FROM $2`, attempt.QuizID, attempt.VersionID)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	applyOptionOrder(attempt, questions)
+
+	return quiz, attempt, answers, questions, nil
+}
+
 func (r *QuizRepository) GetStudentQuizHistories(userID uint) ([]*QuizAttempt, error) {
 	query := `
-		SELECT id, quiz_id, user_id, status, score, question_ids, option_order,
-		       current_question_index, started_at, submitted_at, reset_at, reset_by,
-		       created_at, updated_at
+		SELECT ` + quizAttemptColumns + `
 		FROM quiz_attempts
 		WHERE user_id = $1 AND status IN ('completed', 'reset')
 		ORDER BY COALESCE(submitted_at, reset_at) DESC
@@ -561,13 +1037,8 @@ func (r *QuizRepository) GetStudentQuizHistories(userID uint) ([]*QuizAttempt, e
 
 	attempts := make([]*QuizAttempt, 0)
 	for rows.Next() {
-		attempt := new(QuizAttempt)
-		if err := rows.Scan(
-			&attempt.ID, &attempt.QuizID, &attempt.UserID, &attempt.Status, &attempt.Score,
-			&attempt.QuestionIDs, &attempt.OptionOrder, &attempt.CurrentQuestionIndex,
-			&attempt.StartedAt, &attempt.SubmittedAt, &attempt.ResetAt, &attempt.ResetBy,
-			&attempt.CreatedAt, &attempt.UpdatedAt,
-		); err != nil {
+		attempt, err := scanQuizAttempt(rows)
+		if err != nil {
 			return nil, err
 		}
 		attempts = append(attempts, attempt)
@@ -575,6 +1046,40 @@ func (r *QuizRepository) GetStudentQuizHistories(userID uint) ([]*QuizAttempt, e
 	return attempts, nil
 }
 
+// GetAttemptSummary returns the user's standing against the quiz's attempt
+// policy, or nil when the quiz does not exist.
+func (r *QuizRepository) GetAttemptSummary(userID, quizID uint) (*QuizAttemptSummary, error) {
+	summary, err := scanAttemptSummary(r.db.QueryRow(attemptSummaryQuery(`q.id = $2`), userID, quizID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return summary, nil
+}
+
+// GetStudentAttemptSummaries returns the attempt summary of every quiz the
+// user has attempted since their last reset.
+func (r *QuizRepository) GetStudentAttemptSummaries(userID uint) ([]*QuizAttemptSummary, error) {
+	query := attemptSummaryQuery(`q.deleted_at IS NULL`) + `HAVING COUNT(a.id) > 0 ORDER BY q.id`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := make([]*QuizAttemptSummary, 0)
+	for rows.Next() {
+		summary, err := scanAttemptSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
 type QuizAdminRepository struct {
 	db facades.DBExecutor
 }
@@ -589,7 +1094,7 @@ func (r *QuizAdminRepository) WithExecutor(executor facades.DBExecutor) *QuizAdm
 
 func (r *QuizAdminRepository) ListQuizzes() ([]*QuizQuiz, error) {
 	query := `
-		SELECT id, code, title, description, passing_score, time_limit_minutes, is_active, created_at, updated_at
+		SELECT ` + quizQuizColumns + `
 		FROM quiz_quizzes
 		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
@@ -602,11 +1107,8 @@ func (r *QuizAdminRepository) ListQuizzes() ([]*QuizQuiz, error) {
 
 	quizzes := make([]*QuizQuiz, 0)
 	for rows.Next() {
-		q := new(QuizQuiz)
-		if err := rows.Scan(
-			&q.ID, &q.Code, &q.Title, &q.Description, &q.PassingScore,
-			&q.TimeLimitMinutes, &q.IsActive, &q.CreatedAt, &q.UpdatedAt,
-		); err != nil {
+		q, err := scanQuizQuiz(rows)
+		if err != nil {
 			return nil, err
 		}
 		quizzes = append(quizzes, q)
@@ -614,33 +1116,51 @@ func (r *QuizAdminRepository) ListQuizzes() ([]*QuizQuiz, error) {
 	return quizzes, nil
 }
 
-func (r *QuizAdminRepository) GetQuizDetail(quizID uint) (*QuizQuiz, []QuizQuestion, error) {
+// GetQuiz returns the quiz without its questions, or nil when it does not
+// exist.
+func (r *QuizAdminRepository) GetQuiz(quizID uint) (*QuizQuiz, error) {
 	quizQuery := `
-		SELECT id, code, title, description, passing_score, time_limit_minutes, is_active, created_at, updated_at
+		SELECT ` + quizQuizColumns + `
 		FROM quiz_quizzes
 		WHERE id = $1 AND deleted_at IS NULL
 	`
-	quiz := new(QuizQuiz)
-	err := r.db.QueryRow(quizQuery, quizID).Scan(
-		&quiz.ID, &quiz.Code, &quiz.Title, &quiz.Description, &quiz.PassingScore,
-		&quiz.TimeLimitMinutes, &quiz.IsActive, &quiz.CreatedAt, &quiz.UpdatedAt,
-	)
+	quiz, err := scanQuizQuiz(r.db.QueryRow(quizQuery, quizID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, nil, nil
+			return nil, nil
 		}
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (r *QuizAdminRepository) GetQuizDetail(quizID uint) (*QuizQuiz, []QuizQuestion, error) {
+	quiz, err := r.GetQuiz(quizID)
+	if err != nil || quiz == nil {
+		return nil, nil, err
+	}
+
+	questions, err := listQuestionsWithAnswers(r.db, `quiz_id = $1 AND version_id IS NULL`, quizID)
+	if err != nil {
 		return nil, nil, err
 	}
 
+	return quiz, questions, nil
+}
+
+// listQuestionsWithAnswers loads the questions matching condition, with
+// their options including the correct flags and explanations. It backs the
+// admin views and the student answer review.
+func listQuestionsWithAnswers(db facades.DBExecutor, condition string, args ...any) ([]QuizQuestion, error) {
 	questionsQuery := `
-		SELECT id, quiz_id, question_text, created_at, updated_at
+		SELECT ` + quizQuestionColumns + `
 		FROM quiz_questions
-		WHERE quiz_id = $1
+		WHERE ` + condition + `
 		ORDER BY id ASC
 	`
-	qRows, err := r.db.Query(questionsQuery, quizID)
+	qRows, err := db.Query(questionsQuery, args...)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer qRows.Close()
 
@@ -649,25 +1169,25 @@ func (r *QuizAdminRepository) GetQuizDetail(quizID uint) (*QuizQuiz, []QuizQuest
 	questionMap := make(map[uint]*QuizQuestion)
 
 	for qRows.Next() {
-		q := QuizQuestion{}
-		if err := qRows.Scan(&q.ID, &q.QuizID, &q.QuestionText, &q.CreatedAt, &q.UpdatedAt); err != nil {
-			return nil, nil, err
+		q, err := scanQuizQuestion(qRows)
+		if err != nil {
+			return nil, err
 		}
 		q.Options = make([]QuizOption, 0)
-		questions = append(questions, q)
+		questions = append(questions, *q)
 		questionIDs = append(questionIDs, int64(q.ID))
 	}
 
 	if len(questionIDs) > 0 {
 		optionsQuery := `
-			SELECT id, question_id, option_text, is_correct, created_at, updated_at
+			SELECT id, question_id, option_text, text_format, is_correct, explanation, created_at, updated_at
 			FROM quiz_options
 			WHERE question_id = ANY($1)
 			ORDER BY question_id, id ASC
 		`
-		oRows, err := r.db.Query(optionsQuery, pq.Array(questionIDs))
+		oRows, err := db.Query(optionsQuery, pq.Array(questionIDs))
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		defer oRows.Close()
 
@@ -678,8 +1198,10 @@ func (r *QuizAdminRepository) GetQuizDetail(quizID uint) (*QuizQuiz, []QuizQuest
 
 		for oRows.Next() {
 			opt := QuizOption{}
-			if err := oRows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.IsCorrect, &opt.CreatedAt, &opt.UpdatedAt); err != nil {
-				return nil, nil, err
+			if err := oRows.Scan(
+				&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.TextFormat, &opt.IsCorrect, &opt.Explanation, &opt.CreatedAt, &opt.UpdatedAt,
+			); err != nil {
+				return nil, err
 			}
 			if q, ok := questionMap[opt.QuestionID]; ok {
 				q.Options = append(q.Options, opt)
@@ -693,7 +1215,11 @@ func (r *QuizAdminRepository) GetQuizDetail(quizID uint) (*QuizQuiz, []QuizQuest
 		}
 	}
 
-	return quiz, questions, nil
+	if err := loadQuizAttachments(db, questions); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
 }
 
 func (r *QuizAdminRepository) CreateQuiz(quiz *QuizQuiz) error {
@@ -704,31 +1230,52 @@ func (r *QuizAdminRepository) CreateQuiz(quiz *QuizQuiz) error {
 	quiz.Code = code
 
 	query := `
-		INSERT INTO quiz_quizzes (code, title, description, passing_score, time_limit_minutes, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at, updated_at
+		INSERT INTO quiz_quizzes (
+			code, title, description, passing_score, time_limit_minutes,
+			max_attempts, cooldown_minutes, score_policy, review_policy,
+			opens_at, closes_at, certificate_enabled, leaderboard_opt_out,
+			leaderboard_anonymous, leaderboard_public, is_active
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::TIMESTAMPTZ, $11::TIMESTAMPTZ, $12, $13, $14, $15, $16)
+		RETURNING id, ` + quizAvailabilityExpr + `, created_at, updated_at
 	`
 	return r.db.QueryRow(query,
-		quiz.Code, quiz.Title, quiz.Description, quiz.PassingScore, quiz.TimeLimitMinutes, quiz.IsActive,
-	).Scan(&quiz.ID, &quiz.CreatedAt, &quiz.UpdatedAt)
+		quiz.Code, quiz.Title, quiz.Description, quiz.PassingScore, quiz.TimeLimitMinutes,
+		quiz.MaxAttempts, quiz.CooldownMinutes, quiz.ScorePolicy, quiz.ReviewPolicy,
+		quiz.OpensAt, quiz.ClosesAt, quiz.CertificateEnabled, quiz.LeaderboardOptOut,
+		quiz.LeaderboardAnonymous, quiz.LeaderboardPublic, quiz.IsActive,
+	).Scan(&quiz.ID, &quiz.Availability, &quiz.CreatedAt, &quiz.UpdatedAt)
 }
 
 func (r *QuizAdminRepository) UpdateQuiz(quiz *QuizQuiz) error {
 	query := `
 		UPDATE quiz_quizzes
-		SET title               = $1,
-		    description         = $2,
-		    passing_score       = $3,
-		    time_limit_minutes  = $4,
-		    is_active           = $5,
-		    updated_at          = NOW()
-		WHERE id = $6 AND deleted_at IS NULL
-		RETURNING updated_at
+		SET title                 = $1,
+		    description           = $2,
+		    passing_score         = $3,
+		    time_limit_minutes    = $4,
+		    max_attempts          = $5,
+		    cooldown_minutes      = $6,
+		    score_policy          = $7,
+		    review_policy         = $8,
+		    opens_at              = $9::TIMESTAMPTZ,
+		    closes_at             = $10::TIMESTAMPTZ,
+		    certificate_enabled   = $11,
+		    leaderboard_opt_out   = $12,
+		    leaderboard_anonymous = $13,
+		    leaderboard_public    = $14,
+		    is_active             = $15,
+		    updated_at            = NOW()
+		WHERE id = $16 AND deleted_at IS NULL
+		RETURNING code, ` + quizAvailabilityExpr + `, updated_at
 	`
 	result := r.db.QueryRow(query,
-		quiz.Title, quiz.Description, quiz.PassingScore, quiz.TimeLimitMinutes, quiz.IsActive, quiz.ID,
+		quiz.Title, quiz.Description, quiz.PassingScore, quiz.TimeLimitMinutes,
+		quiz.MaxAttempts, quiz.CooldownMinutes, quiz.ScorePolicy, quiz.ReviewPolicy,
+		quiz.OpensAt, quiz.ClosesAt, quiz.CertificateEnabled, quiz.LeaderboardOptOut,
+		quiz.LeaderboardAnonymous, quiz.LeaderboardPublic, quiz.IsActive, quiz.ID,
 	)
-	return result.Scan(&quiz.UpdatedAt)
+	return result.Scan(&quiz.Code, &quiz.Availability, &quiz.UpdatedAt)
 }
 
 func (r *QuizAdminRepository) DeleteQuiz(quizID uint) error {
@@ -746,28 +1293,51 @@ func (r *QuizAdminRepository) DeleteQuiz(quizID uint) error {
 
 func (r *QuizAdminRepository) CreateQuestion(q *QuizQuestion) error {
 	query := `
-		INSERT INTO quiz_questions (quiz_id, question_text)
-		VALUES ($1, $2)
+		INSERT INTO quiz_questions (
+			quiz_id, bank_id, question_text, question_type, scoring_mode, match_mode,
+			accepted_answers, numeric_answer, numeric_tolerance, tags, difficulty,
+			explanation, points, penalty_points, text_format
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING id, created_at, updated_at
 	`
-	return r.db.QueryRow(query, q.QuizID, q.QuestionText).
-		Scan(&q.ID, &q.CreatedAt, &q.UpdatedAt)
+	return r.db.QueryRow(query,
+		q.QuizID, q.BankID, q.QuestionText, q.QuestionType, q.ScoringMode, q.MatchMode,
+		q.AcceptedAnswers, q.NumericAnswer, q.NumericTolerance, tagsOrEmpty(q.Tags), q.Difficulty,
+		q.Explanation, q.Points, q.PenaltyPoints, q.TextFormat,
+	).Scan(&q.ID, &q.CreatedAt, &q.UpdatedAt)
 }
 
 func (r *QuizAdminRepository) UpdateQuestion(q *QuizQuestion) error {
 	query := `
 		UPDATE quiz_questions
-		SET question_text = $1,
-		    updated_at    = NOW()
-		WHERE id = $2 AND quiz_id = $3
+		SET question_text     = $1,
+		    question_type     = $2,
+		    scoring_mode      = $3,
+		    match_mode        = $4,
+		    accepted_answers  = $5,
+		    numeric_answer    = $6,
+		    numeric_tolerance = $7,
+		    tags              = $8,
+		    difficulty        = $9,
+		    explanation       = $10,
+		    points            = $11,
+		    penalty_points    = $12,
+		    text_format       = $13,
+		    updated_at        = NOW()
+		WHERE id = $14 AND ` + questionOwnerCondition(15) + `
 		RETURNING updated_at
 	`
-	return r.db.QueryRow(query, q.QuestionText, q.ID, q.QuizID).Scan(&q.UpdatedAt)
+	return r.db.QueryRow(query,
+		q.QuestionText, q.QuestionType, q.ScoringMode, q.MatchMode,
+		q.AcceptedAnswers, q.NumericAnswer, q.NumericTolerance, tagsOrEmpty(q.Tags), q.Difficulty,
+		q.Explanation, q.Points, q.PenaltyPoints, q.TextFormat, q.ID, q.QuizID, q.BankID,
+	).Scan(&q.UpdatedAt)
 }
 
-func (r *QuizAdminRepository) DeleteQuestion(questionID, quizID uint) error {
-	query := `DELETE FROM quiz_questions WHERE id = $1 AND quiz_id = $2`
-	res, err := r.db.Exec(query, questionID, quizID)
+func (r *QuizAdminRepository) DeleteQuestion(questionID uint, owner QuizQuestionOwner) error {
+	query := `DELETE FROM quiz_questions WHERE id = $1 AND ` + questionOwnerCondition(2)
+	res, err := r.db.Exec(query, questionID, owner.QuizID, owner.BankID)
 	if err != nil {
 		return err
 	}
@@ -778,31 +1348,119 @@ func (r *QuizAdminRepository) DeleteQuestion(questionID, quizID uint) error {
 	return nil
 }
 
+// GetQuestionType returns the type of a question owned by the quiz or bank,
+// or an empty string when the question does not exist.
+func (r *QuizAdminRepository) GetQuestionType(questionID uint, owner QuizQuestionOwner) (string, error) {
+	var questionType string
+	err := r.db.QueryRow(
+		`SELECT question_type FROM quiz_questions WHERE id = $1 AND `+questionOwnerCondition(2),
+		questionID, owner.QuizID, owner.BankID,
+	).Scan(&questionType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return questionType, nil
+}
+
+// SetTrueFalseOptions makes sure a question has exactly the "True" and
+// "False" options and marks the one matching correctAnswer as correct.
+// Existing True/False options are updated in place so saved answers survive.
+func (r *QuizAdminRepository) SetTrueFalseOptions(questionID uint, correctAnswer bool) ([]QuizOption, error) {
+	var trueFalseCount, total int
+	err := r.db.QueryRow(
+		`SELECT COUNT(*) FILTER (WHERE option_text IN ('True', 'False')), COUNT(*) FROM quiz_options WHERE question_id = $1`,
+		questionID,
+	).Scan(&trueFalseCount, &total)
+	if err != nil {
+		return nil, err
+	}
+
+	if trueFalseCount == 2 && total == 2 {
+		rows, err := r.db.Query(`
+			UPDATE quiz_options
+			SET is_correct = ((option_text = 'True') = $1),
+			    updated_at = NOW()
+			WHERE question_id = $2
+			RETURNING id, question_id, option_text, text_format, is_correct, created_at, updated_at
+		`, correctAnswer, questionID)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		options := make([]QuizOption, 2)
+		for rows.Next() {
+			var opt QuizOption
+			if err := rows.Scan(&opt.ID, &opt.QuestionID, &opt.OptionText, &opt.TextFormat, &opt.IsCorrect, &opt.CreatedAt, &opt.UpdatedAt); err != nil {
+				return nil, err
+			}
+			if opt.OptionText == "True" {
+				options[0] = opt
+			} else {
+				options[1] = opt
+			}
+		}
+		return options, rows.Err()
+	}
+
+	if err := r.ClearOptions(questionID); err != nil {
+		return nil, err
+	}
+	options := []QuizOption{
+		{QuestionID: questionID, OptionText: "True", IsCorrect: correctAnswer},
+		{QuestionID: questionID, OptionText: "False", IsCorrect: !correctAnswer},
+	}
+	for i := range options {
+		if err := r.CreateOption(&options[i]); err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+// ClearOptions removes every option of a question, used when it changes to a
+// type that is not answered by picking options.
+func (r *QuizAdminRepository) ClearOptions(questionID uint) error {
+	_, err := r.db.Exec(`DELETE FROM quiz_options WHERE question_id = $1`, questionID)
+	return err
+}
+
 func (r *QuizAdminRepository) CreateOption(opt *QuizOption) error {
+	if opt.TextFormat == "" {
+		opt.TextFormat = TextFormatPlain
+	}
 	query := `
-		INSERT INTO quiz_options (question_id, option_text, is_correct)
-		VALUES ($1, $2, $3)
+		INSERT INTO quiz_options (question_id, option_text, is_correct, explanation, text_format)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at, updated_at
 	`
-	return r.db.QueryRow(query, opt.QuestionID, opt.OptionText, opt.IsCorrect).
+	return r.db.QueryRow(query, opt.QuestionID, opt.OptionText, opt.IsCorrect, opt.Explanation, opt.TextFormat).
 		Scan(&opt.ID, &opt.CreatedAt, &opt.UpdatedAt)
 }
 
 func (r *QuizAdminRepository) UpdateOption(opt *QuizOption) error {
+	if opt.TextFormat == "" {
+		opt.TextFormat = TextFormatPlain
+	}
 	query := `
 		UPDATE quiz_options
 		SET option_text  = $1,
 		    is_correct   = $2,
+		    explanation  = $3,
+		    text_format  = $4,
 		    updated_at   = NOW()
-		WHERE id = $3 AND question_id = $4
+		WHERE id = $5 AND question_id = $6 AND ` + draftOptionCondition + `
 		RETURNING updated_at
 	`
-	return r.db.QueryRow(query, opt.OptionText, opt.IsCorrect, opt.ID, opt.QuestionID).
+	return r.db.QueryRow(query, opt.OptionText, opt.IsCorrect, opt.Explanation, opt.TextFormat, opt.ID, opt.QuestionID).
 		Scan(&opt.UpdatedAt)
 }
 
 func (r *QuizAdminRepository) DeleteOption(optionID, questionID uint) error {
-	query := `DELETE FROM quiz_options WHERE id = $1 AND question_id = $2`
+	query := `DELETE FROM quiz_options WHERE id = $1 AND question_id = $2 AND ` + draftOptionCondition
 	res, err := r.db.Exec(query, optionID, questionID)
 	if err != nil {
 		return err
@@ -817,12 +1475,14 @@ func (r *QuizAdminRepository) DeleteOption(optionID, questionID uint) error {
 func (r *QuizAdminRepository) ListAttempts(quizID uint) ([]QuizAttemptWithUser, error) {
 	query := `
 		SELECT
-			a.id, a.quiz_id, a.user_id, a.status, a.score,
+			a.id, a.quiz_id, a.version_id, a.user_id, a.status, a.score, a.raw_score, a.max_score,
 			a.started_at, a.submitted_at, a.reset_at, a.reset_by,
 			a.created_at, a.updated_at,
-			u.name, u.email
+			u.name, u.email,
+			` + quizIntegrityColumns + `
 		FROM quiz_attempts a
 			LEFT JOIN users u ON u.id = a.user_id
+			` + quizIntegrityJoin + `
 		WHERE a.quiz_id = $1
 		ORDER BY a.created_at DESC
 	`
@@ -832,17 +1492,20 @@ func (r *QuizAdminRepository) ListAttempts(quizID uint) ([]QuizAttemptWithUser,
 	}
 	defer rows.Close()
 
+	thresholds := LoadQuizIntegrityThresholds()
 	attempts := make([]QuizAttemptWithUser, 0)
 	for rows.Next() {
 		var a QuizAttemptWithUser
-		if err := rows.Scan(
-			&a.ID, &a.QuizID, &a.UserID, &a.Status, &a.Score,
+		dest := []any{
+			&a.ID, &a.QuizID, &a.VersionID, &a.UserID, &a.Status, &a.Score, &a.RawScore, &a.MaxScore,
 			&a.StartedAt, &a.SubmittedAt, &a.ResetAt, &a.ResetBy,
 			&a.CreatedAt, &a.UpdatedAt,
 			&a.UserName, &a.UserEmail,
-		); err != nil {
+		}
+		if err := rows.Scan(append(dest, integritySummaryDest(&a.Integrity)...)...); err != nil {
 			return nil, err
 		}
+		a.Integrity.Evaluate(thresholds)
 		attempts = append(attempts, a)
 	}
 	return attempts, nil
@@ -850,6 +1513,7 @@ func (r *QuizAdminRepository) ListAttempts(quizID uint) ([]QuizAttemptWithUser,
 
 type QuizAttemptWithUser struct {
 	QuizAttempt
-	UserName  string `json:"user_name"`
-	UserEmail string `json:"user_email"`
+	UserName  string               `json:"user_name"`
+	UserEmail string               `json:"user_email"`
+	Integrity QuizIntegritySummary `json:"integrity"`
 }
@@ -0,0 +1,367 @@
+package models
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+
+	"github.com/lib/pq"
+)
+
+// discriminationGroupShare is the share of attempts in the upper and lower
+// groups used for the discrimination index (Kelley's 27%).
+const discriminationGroupShare = 0.27
+
+// scoreHistogramBuckets splits the 0-100 score range into buckets of 10.
+const scoreHistogramBuckets = 10
+
+// QuizAnalysis is the item analysis of a quiz, computed from its completed
+// attempts. Reset attempts are left out.
+type QuizAnalysis struct {
+	QuizID       uint                   `json:"quiz_id"`
+	AttemptCount int                    `json:"attempt_count"`
+	Scores       QuizScoreStatistics    `json:"scores"`
+	Questions    []QuizQuestionAnalysis `json:"questions"`
+}
+
+// QuizScoreStatistics summarises the attempt scores. The pointers are nil
+// when there are no attempts yet.
+type QuizScoreStatistics struct {
+	Mean         *float64          `json:"mean"`
+	Median       *float64          `json:"median"`
+	StdDev       *float64          `json:"std_dev"`
+	Min          *float64          `json:"min"`
+	Max          *float64          `json:"max"`
+	PassingScore int               `json:"passing_score"`
+	PassCount    int               `json:"pass_count"`
+	PassRate     *float64          `json:"pass_rate"`
+	Histogram    []QuizScoreBucket `json:"histogram"`
+}
+
+// QuizScoreBucket counts the scores from From up to but excluding To; the
+// last bucket includes 100.
+type QuizScoreBucket struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+// QuizQuestionAnalysis describes how one question performed. Difficulty is
+// the percentage of attempts that got it right (higher means easier) and
+// Discrimination is the difference in that share between the top and bottom
+// 27% of attempts by score, from -1 to 1. Each published version has its own
+// copy of a question, so a question edited between versions is listed once
+// per version.
+type QuizQuestionAnalysis struct {
+	QuestionID      uint                 `json:"question_id"`
+	VersionID       *uint                `json:"version_id"`
+	QuestionText    string               `json:"question_text"`
+	QuestionType    string               `json:"question_type"`
+	PresentedCount  int                  `json:"presented_count"`
+	AnsweredCount   int                  `json:"answered_count"`
+	UnansweredCount int                  `json:"unanswered_count"`
+	CorrectCount    int                  `json:"correct_count"`
+	Difficulty      *float64             `json:"difficulty"`
+	AverageCredit   *float64             `json:"average_credit"`
+	Discrimination  *float64             `json:"discrimination"`
+	Options         []QuizOptionAnalysis `json:"options"`
+}
+
+// QuizOptionAnalysis is the distractor analysis of one option: how often it
+// was picked overall and within the upper and lower groups.
+type QuizOptionAnalysis struct {
+	OptionID      uint     `json:"option_id"`
+	OptionText    string   `json:"option_text"`
+	IsCorrect     bool     `json:"is_correct"`
+	SelectedCount int      `json:"selected_count"`
+	SelectedRate  *float64 `json:"selected_rate"`
+	UpperCount    int      `json:"upper_count"`
+	LowerCount    int      `json:"lower_count"`
+}
+
+type analyzedAttempt struct {
+	ID          uint
+	VersionID   *uint
+	Score       float64
+	QuestionIDs pq.Int64Array
+}
+
+type analyzedAnswer struct {
+	AttemptID  uint
+	QuestionID uint
+	OptionID   *uint
+	OptionIDs  pq.Int64Array
+	IsCorrect  bool
+	Credit     float64
+}
+
+// GetQuizAnalysis computes the item analysis of a quiz. It returns nil when
+// the quiz does not exist.
+func (r *QuizAdminRepository) GetQuizAnalysis(quizID uint) (*QuizAnalysis, error) {
+	var passingScore int
+	err := r.db.QueryRow(
+		`SELECT passing_score FROM quiz_quizzes WHERE id = $1 AND deleted_at IS NULL`,
+		quizID,
+	).Scan(&passingScore)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	attemptRows, err := r.db.Query(`
+		SELECT id, version_id, COALESCE(score, 0), question_ids
+		FROM quiz_attempts
+		WHERE quiz_id = $1 AND status = 'completed'
+		ORDER BY id ASC
+	`, quizID)
+	if err != nil {
+		return nil, err
+	}
+	defer attemptRows.Close()
+
+	attempts := make([]analyzedAttempt, 0)
+	for attemptRows.Next() {
+		var a analyzedAttempt
+		if err := attemptRows.Scan(&a.ID, &a.VersionID, &a.Score, &a.QuestionIDs); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+	if err := attemptRows.Err(); err != nil {
+		return nil, err
+	}
+
+	answerRows, err := r.db.Query(`
+		SELECT ans.attempt_id, ans.question_id, ans.option_id, ans.option_ids, ans.is_correct, ans.credit
+		FROM quiz_answers ans
+			JOIN quiz_attempts a ON a.id = ans.attempt_id
+		WHERE a.quiz_id = $1 AND a.status = 'completed'
+	`, quizID)
+	if err != nil {
+		return nil, err
+	}
+	defer answerRows.Close()
+
+	answers := make([]analyzedAnswer, 0)
+	for answerRows.Next() {
+		var ans analyzedAnswer
+		if err := answerRows.Scan(
+			&ans.AttemptID, &ans.QuestionID, &ans.OptionID, &ans.OptionIDs, &ans.IsCorrect, &ans.Credit,
+		); err != nil {
+			return nil, err
+		}
+		answers = append(answers, ans)
+	}
+	if err := answerRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Attempts from before sampling have no question_ids and were given the
+	// quiz's own questions of their version.
+	questionIDs := make([]int64, 0)
+	legacyVersionIDs := make([]int64, 0)
+	for _, a := range attempts {
+		questionIDs = append(questionIDs, a.QuestionIDs...)
+		if len(a.QuestionIDs) == 0 && a.VersionID != nil {
+			legacyVersionIDs = append(legacyVersionIDs, int64(*a.VersionID))
+		}
+	}
+	questions, err := listQuestionsWithAnswers(r.db,
+		`(id = ANY($2) OR (quiz_id = $1 AND version_id = ANY($3)))`,
+		quizID, pq.Array(questionIDs), pq.Array(legacyVersionIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := analyzeQuiz(attempts, answers, questions, passingScore)
+	analysis.QuizID = quizID
+	return analysis, nil
+}
+
+// analyzeQuiz does the calculations of GetQuizAnalysis on loaded data.
+func analyzeQuiz(attempts []analyzedAttempt, answers []analyzedAnswer, questions []QuizQuestion, passingScore int) *QuizAnalysis {
+	analysis := &QuizAnalysis{
+		AttemptCount: len(attempts),
+		Scores:       scoreStatistics(attempts, passingScore),
+		Questions:    make([]QuizQuestionAnalysis, 0, len(questions)),
+	}
+
+	// Rank the attempts by score to find the upper and lower groups. Ties
+	// keep the attempt order so the groups are stable between requests.
+	ranked := make([]analyzedAttempt, len(attempts))
+	copy(ranked, attempts)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+
+	groupSize := 0
+	if len(ranked) >= 2 {
+		groupSize = int(math.Max(1, math.Round(float64(len(ranked))*discriminationGroupShare)))
+	}
+	group := make(map[uint]int, len(ranked)) // 1 = upper group, -1 = lower group
+	for i := 0; i < groupSize; i++ {
+		group[ranked[i].ID] = 1
+		group[ranked[len(ranked)-1-i].ID] = -1
+	}
+
+	answerOf := make(map[uint]map[uint]analyzedAnswer, len(attempts))
+	for _, ans := range answers {
+		if answerOf[ans.AttemptID] == nil {
+			answerOf[ans.AttemptID] = make(map[uint]analyzedAnswer)
+		}
+		answerOf[ans.AttemptID][ans.QuestionID] = ans
+	}
+
+	for _, q := range questions {
+		item := QuizQuestionAnalysis{
+			QuestionID:   q.ID,
+			VersionID:    q.VersionID,
+			QuestionText: q.QuestionText,
+			QuestionType: q.QuestionType,
+			Options:      make([]QuizOptionAnalysis, len(q.Options)),
+		}
+		optionIndex := make(map[uint]int, len(q.Options))
+		for i, o := range q.Options {
+			optionIndex[o.ID] = i
+			item.Options[i] = QuizOptionAnalysis{OptionID: o.ID, OptionText: o.OptionText, IsCorrect: o.IsCorrect}
+		}
+
+		var creditSum float64
+		var upperPresented, upperCorrect, lowerPresented, lowerCorrect int
+		for _, a := range attempts {
+			if !attemptPresented(a, q) {
+				continue
+			}
+			item.PresentedCount++
+			switch group[a.ID] {
+			case 1:
+				upperPresented++
+			case -1:
+				lowerPresented++
+			}
+
+			ans, answered := answerOf[a.ID][q.ID]
+			if !answered {
+				item.UnansweredCount++
+				continue
+			}
+			item.AnsweredCount++
+			creditSum += ans.Credit
+			if ans.IsCorrect {
+				item.CorrectCount++
+				switch group[a.ID] {
+				case 1:
+					upperCorrect++
+				case -1:
+					lowerCorrect++
+				}
+			}
+
+			selected := ans.OptionIDs
+			if ans.OptionID != nil {
+				selected = pq.Int64Array{int64(*ans.OptionID)}
+			}
+			for _, optionID := range selected {
+				i, ok := optionIndex[uint(optionID)]
+				if !ok {
+					continue
+				}
+				item.Options[i].SelectedCount++
+				switch group[a.ID] {
+				case 1:
+					item.Options[i].UpperCount++
+				case -1:
+					item.Options[i].LowerCount++
+				}
+			}
+		}
+
+		if item.PresentedCount > 0 {
+			n := float64(item.PresentedCount)
+			item.Difficulty = roundedPtr(float64(item.CorrectCount) / n * 100)
+			item.AverageCredit = roundedPtr(creditSum / n)
+			for i := range item.Options {
+				item.Options[i].SelectedRate = roundedPtr(float64(item.Options[i].SelectedCount) / n * 100)
+			}
+		}
+		if upperPresented > 0 && lowerPresented > 0 {
+			item.Discrimination = roundedPtr(
+				float64(upperCorrect)/float64(upperPresented) - float64(lowerCorrect)/float64(lowerPresented),
+			)
+		}
+
+		analysis.Questions = append(analysis.Questions, item)
+	}
+
+	return analysis
+}
+
+// attemptPresented reports whether the question was part of the attempt.
+func attemptPresented(a analyzedAttempt, q QuizQuestion) bool {
+	if len(a.QuestionIDs) == 0 {
+		return q.QuizID != nil && a.VersionID != nil && q.VersionID != nil && *a.VersionID == *q.VersionID
+	}
+	for _, id := range a.QuestionIDs {
+		if uint(id) == q.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func scoreStatistics(attempts []analyzedAttempt, passingScore int) QuizScoreStatistics {
+	stats := QuizScoreStatistics{
+		PassingScore: passingScore,
+		Histogram:    make([]QuizScoreBucket, scoreHistogramBuckets),
+	}
+	width := 100.0 / scoreHistogramBuckets
+	for i := range stats.Histogram {
+		stats.Histogram[i] = QuizScoreBucket{From: float64(i) * width, To: float64(i+1) * width}
+	}
+	if len(attempts) == 0 {
+		return stats
+	}
+
+	scores := make([]float64, len(attempts))
+	var sum float64
+	for i, a := range attempts {
+		scores[i] = a.Score
+		sum += a.Score
+		if a.Score >= float64(passingScore) {
+			stats.PassCount++
+		}
+		bucket := int(a.Score / width)
+		if bucket >= scoreHistogramBuckets {
+			bucket = scoreHistogramBuckets - 1
+		}
+		if bucket < 0 {
+			bucket = 0
+		}
+		stats.Histogram[bucket].Count++
+	}
+	sort.Float64s(scores)
+
+	n := float64(len(scores))
+	mean := sum / n
+	var squares float64
+	for _, s := range scores {
+		squares += (s - mean) * (s - mean)
+	}
+	median := scores[len(scores)/2]
+	if len(scores)%2 == 0 {
+		median = (scores[len(scores)/2-1] + scores[len(scores)/2]) / 2
+	}
+
+	stats.Mean = roundedPtr(mean)
+	stats.Median = roundedPtr(median)
+	stats.StdDev = roundedPtr(math.Sqrt(squares / n))
+	stats.Min = roundedPtr(scores[0])
+	stats.Max = roundedPtr(scores[len(scores)-1])
+	stats.PassRate = roundedPtr(float64(stats.PassCount) / n * 100)
+	return stats
+}
+
+func roundedPtr(value float64) *float64 {
+	rounded := math.Round(value*100) / 100
+	return &rounded
+}
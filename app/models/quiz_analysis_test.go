@@ -0,0 +1,329 @@
+package models_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	. "github.com/studio-senkou/lentera-cendekia-be/app/models"
+)
+
+func formatPtr(v *float64) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*v)
+}
+
+func checkFloatPtr(t *testing.T, field string, got, want *float64) {
+	t.Helper()
+	if (got == nil) != (want == nil) || got != nil && *got != *want {
+		t.Errorf("expected %s %s, got %s", field, formatPtr(want), formatPtr(got))
+	}
+}
+
+func scoredAttempts(scores ...float64) []AnalyzedAttempt {
+	attempts := make([]AnalyzedAttempt, len(scores))
+	for i, score := range scores {
+		attempts[i] = AnalyzedAttempt{ID: uint(i + 1), Score: score}
+	}
+	return attempts
+}
+
+func TestScoreStatistics(t *testing.T) {
+	tests := []struct {
+		name         string
+		scores       []float64
+		passingScore int
+		wantMean     *float64
+		wantMedian   *float64
+		wantStdDev   *float64
+		wantMin      *float64
+		wantMax      *float64
+		wantPass     int
+		wantPassRate *float64
+		wantBuckets  [10]int
+	}{
+		{
+			name:         "no attempts",
+			passingScore: 70,
+		},
+		{
+			name:         "one attempt of exactly 100 lands in the last bucket",
+			scores:       []float64{100},
+			passingScore: 70,
+			wantMean:     float64Ptr(100),
+			wantMedian:   float64Ptr(100),
+			wantStdDev:   float64Ptr(0),
+			wantMin:      float64Ptr(100),
+			wantMax:      float64Ptr(100),
+			wantPass:     1,
+			wantPassRate: float64Ptr(100),
+			wantBuckets:  [10]int{9: 1},
+		},
+		{
+			name:         "two attempts use the mean of the middle scores",
+			scores:       []float64{90, 40},
+			passingScore: 70,
+			wantMean:     float64Ptr(65),
+			wantMedian:   float64Ptr(65),
+			wantStdDev:   float64Ptr(25),
+			wantMin:      float64Ptr(40),
+			wantMax:      float64Ptr(90),
+			wantPass:     1,
+			wantPassRate: float64Ptr(50),
+			wantBuckets:  [10]int{4: 1, 9: 1},
+		},
+		{
+			name:         "three attempts use the middle score and count the passing score as passed",
+			scores:       []float64{95, 60, 70},
+			passingScore: 70,
+			wantMean:     float64Ptr(75),
+			wantMedian:   float64Ptr(70),
+			wantStdDev:   float64Ptr(14.72),
+			wantMin:      float64Ptr(60),
+			wantMax:      float64Ptr(95),
+			wantPass:     2,
+			wantPassRate: float64Ptr(66.67),
+			wantBuckets:  [10]int{6: 1, 7: 1, 9: 1},
+		},
+		{
+			name:         "tied scores",
+			scores:       []float64{80, 20, 80, 80},
+			passingScore: 80,
+			wantMean:     float64Ptr(65),
+			wantMedian:   float64Ptr(80),
+			wantStdDev:   float64Ptr(25.98),
+			wantMin:      float64Ptr(20),
+			wantMax:      float64Ptr(80),
+			wantPass:     3,
+			wantPassRate: float64Ptr(75),
+			wantBuckets:  [10]int{2: 1, 8: 3},
+		},
+		{
+			name:         "bucket edges",
+			scores:       []float64{0, 9.5, 10, 89.5, 90, 100},
+			passingScore: 70,
+			wantMean:     float64Ptr(49.83),
+			wantMedian:   float64Ptr(49.75),
+			wantStdDev:   float64Ptr(43.59),
+			wantMin:      float64Ptr(0),
+			wantMax:      float64Ptr(100),
+			wantPass:     3,
+			wantPassRate: float64Ptr(50),
+			wantBuckets:  [10]int{0: 2, 1: 1, 8: 1, 9: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := ScoreStatistics(scoredAttempts(tt.scores...), tt.passingScore)
+
+			checkFloatPtr(t, "mean", stats.Mean, tt.wantMean)
+			checkFloatPtr(t, "median", stats.Median, tt.wantMedian)
+			checkFloatPtr(t, "std_dev", stats.StdDev, tt.wantStdDev)
+			checkFloatPtr(t, "min", stats.Min, tt.wantMin)
+			checkFloatPtr(t, "max", stats.Max, tt.wantMax)
+			checkFloatPtr(t, "pass_rate", stats.PassRate, tt.wantPassRate)
+			if stats.PassingScore != tt.passingScore {
+				t.Errorf("expected passing score %d, got %d", tt.passingScore, stats.PassingScore)
+			}
+			if stats.PassCount != tt.wantPass {
+				t.Errorf("expected %d passed, got %d", tt.wantPass, stats.PassCount)
+			}
+
+			if len(stats.Histogram) != len(tt.wantBuckets) {
+				t.Fatalf("expected %d buckets, got %d", len(tt.wantBuckets), len(stats.Histogram))
+			}
+			for i, bucket := range stats.Histogram {
+				if bucket.From != float64(i*10) || bucket.To != float64(i*10+10) {
+					t.Errorf("expected bucket %d to span %d-%d, got %v-%v", i, i*10, i*10+10, bucket.From, bucket.To)
+				}
+				if bucket.Count != tt.wantBuckets[i] {
+					t.Errorf("expected %d scores in bucket %d, got %d", tt.wantBuckets[i], i, bucket.Count)
+				}
+			}
+		})
+	}
+}
+
+// analysisQuestion returns a quiz question of version 1 with a correct option
+// id*10+1 and a wrong option id*10+2.
+func analysisQuestion(id uint, quizID *uint) QuizQuestion {
+	return QuizQuestion{
+		ID:           id,
+		QuizID:       quizID,
+		VersionID:    uintPtr(1),
+		QuestionType: QuestionTypeSingleChoice,
+		Options: []QuizOption{
+			{ID: id*10 + 1, IsCorrect: true},
+			{ID: id*10 + 2},
+		},
+	}
+}
+
+func sampledAttempt(id uint, score float64, questionIDs ...int64) AnalyzedAttempt {
+	return AnalyzedAttempt{ID: id, VersionID: uintPtr(1), Score: score, QuestionIDs: pq.Int64Array(questionIDs)}
+}
+
+// pick answers the question with its correct or wrong option.
+func pick(attemptID, questionID uint, correct bool) AnalyzedAnswer {
+	ans := AnalyzedAnswer{AttemptID: attemptID, QuestionID: questionID, OptionID: uintPtr(questionID*10 + 2)}
+	if correct {
+		ans.OptionID = uintPtr(questionID*10 + 1)
+		ans.IsCorrect = true
+		ans.Credit = 1
+	}
+	return ans
+}
+
+type optionCounts struct {
+	selected, upper, lower int
+}
+
+type questionExpectation struct {
+	presented, answered, unanswered, correct  int
+	difficulty, averageCredit, discrimination *float64
+	options                                   []optionCounts
+}
+
+func TestAnalyzeQuiz(t *testing.T) {
+	quizID := uintPtr(1)
+
+	tests := []struct {
+		name      string
+		attempts  []AnalyzedAttempt
+		answers   []AnalyzedAnswer
+		questions []QuizQuestion
+		want      []questionExpectation
+	}{
+		{
+			name:      "one attempt has no groups to compare",
+			attempts:  []AnalyzedAttempt{sampledAttempt(1, 100, 1)},
+			answers:   []AnalyzedAnswer{pick(1, 1, true)},
+			questions: []QuizQuestion{analysisQuestion(1, quizID)},
+			want: []questionExpectation{{
+				presented: 1, answered: 1, correct: 1,
+				difficulty: float64Ptr(100), averageCredit: float64Ptr(1),
+				options: []optionCounts{{selected: 1}, {}},
+			}},
+		},
+		{
+			name:      "two attempts form groups of one",
+			attempts:  []AnalyzedAttempt{sampledAttempt(1, 0, 1), sampledAttempt(2, 100, 1)},
+			answers:   []AnalyzedAnswer{pick(1, 1, false), pick(2, 1, true)},
+			questions: []QuizQuestion{analysisQuestion(1, quizID)},
+			want: []questionExpectation{{
+				presented: 2, answered: 2, correct: 1,
+				difficulty: float64Ptr(50), averageCredit: float64Ptr(0.5), discrimination: float64Ptr(1),
+				options: []optionCounts{{selected: 1, upper: 1}, {selected: 1, lower: 1}},
+			}},
+		},
+		{
+			name: "three attempts leave the middle attempt out of the groups",
+			attempts: []AnalyzedAttempt{
+				sampledAttempt(1, 80, 1), sampledAttempt(2, 40, 1), sampledAttempt(3, 60, 1),
+			},
+			answers:   []AnalyzedAnswer{pick(1, 1, true), pick(2, 1, false)},
+			questions: []QuizQuestion{analysisQuestion(1, quizID)},
+			want: []questionExpectation{{
+				presented: 3, answered: 2, unanswered: 1, correct: 1,
+				difficulty: float64Ptr(33.33), averageCredit: float64Ptr(0.33), discrimination: float64Ptr(1),
+				options: []optionCounts{{selected: 1, upper: 1}, {selected: 1, lower: 1}},
+			}},
+		},
+		{
+			name: "tied scores keep the attempt order",
+			attempts: []AnalyzedAttempt{
+				sampledAttempt(1, 50, 1), sampledAttempt(2, 50, 1), sampledAttempt(3, 50, 1),
+			},
+			answers:   []AnalyzedAnswer{pick(1, 1, false), pick(2, 1, true), pick(3, 1, true)},
+			questions: []QuizQuestion{analysisQuestion(1, quizID)},
+			want: []questionExpectation{{
+				presented: 3, answered: 3, correct: 2,
+				difficulty: float64Ptr(66.67), averageCredit: float64Ptr(0.67), discrimination: float64Ptr(-1),
+				options: []optionCounts{{selected: 2, lower: 1}, {selected: 1, upper: 1}},
+			}},
+		},
+		{
+			name: "six attempts form groups of 27%",
+			attempts: []AnalyzedAttempt{
+				sampledAttempt(1, 100, 1), sampledAttempt(2, 90, 1), sampledAttempt(3, 80, 1),
+				sampledAttempt(4, 30, 1), sampledAttempt(5, 20, 1), sampledAttempt(6, 10, 1),
+			},
+			answers: []AnalyzedAnswer{
+				pick(1, 1, true), pick(2, 1, false), pick(3, 1, true),
+				pick(4, 1, false), pick(5, 1, true), pick(6, 1, false),
+			},
+			questions: []QuizQuestion{analysisQuestion(1, quizID)},
+			want: []questionExpectation{{
+				presented: 6, answered: 6, correct: 3,
+				difficulty: float64Ptr(50), averageCredit: float64Ptr(0.5), discrimination: float64Ptr(0),
+				options: []optionCounts{{selected: 3, upper: 1, lower: 1}, {selected: 3, upper: 1, lower: 1}},
+			}},
+		},
+		{
+			name: "legacy attempts were given the quiz questions of their version",
+			attempts: []AnalyzedAttempt{
+				{ID: 1, VersionID: uintPtr(1), Score: 90},
+				sampledAttempt(2, 10, 2),
+			},
+			answers: []AnalyzedAnswer{pick(1, 1, true), pick(2, 2, false)},
+			questions: []QuizQuestion{
+				analysisQuestion(1, quizID),
+				analysisQuestion(2, nil),
+			},
+			want: []questionExpectation{
+				{
+					presented: 1, answered: 1, correct: 1,
+					difficulty: float64Ptr(100), averageCredit: float64Ptr(1),
+					options: []optionCounts{{selected: 1, upper: 1}, {}},
+				},
+				{
+					presented: 1, answered: 1,
+					difficulty: float64Ptr(0), averageCredit: float64Ptr(0),
+					options: []optionCounts{{}, {selected: 1, lower: 1}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeQuiz(tt.attempts, tt.answers, tt.questions, 70)
+			if analysis.AttemptCount != len(tt.attempts) {
+				t.Errorf("expected %d attempts, got %d", len(tt.attempts), analysis.AttemptCount)
+			}
+			if len(analysis.Questions) != len(tt.want) {
+				t.Fatalf("expected %d questions, got %d", len(tt.want), len(analysis.Questions))
+			}
+
+			for i, want := range tt.want {
+				got := analysis.Questions[i]
+				if got.QuestionID != tt.questions[i].ID {
+					t.Errorf("expected question %d, got %d", tt.questions[i].ID, got.QuestionID)
+				}
+				if got.PresentedCount != want.presented || got.AnsweredCount != want.answered ||
+					got.UnansweredCount != want.unanswered || got.CorrectCount != want.correct {
+					t.Errorf("question %d: expected presented/answered/unanswered/correct %d/%d/%d/%d, got %d/%d/%d/%d",
+						got.QuestionID, want.presented, want.answered, want.unanswered, want.correct,
+						got.PresentedCount, got.AnsweredCount, got.UnansweredCount, got.CorrectCount)
+				}
+				checkFloatPtr(t, "difficulty", got.Difficulty, want.difficulty)
+				checkFloatPtr(t, "average credit", got.AverageCredit, want.averageCredit)
+				checkFloatPtr(t, "discrimination", got.Discrimination, want.discrimination)
+
+				if len(got.Options) != len(want.options) {
+					t.Fatalf("expected %d options, got %d", len(want.options), len(got.Options))
+				}
+				for j, o := range want.options {
+					g := got.Options[j]
+					if g.SelectedCount != o.selected || g.UpperCount != o.upper || g.LowerCount != o.lower {
+						t.Errorf("option %d: expected selected/upper/lower %d/%d/%d, got %d/%d/%d",
+							g.OptionID, o.selected, o.upper, o.lower, g.SelectedCount, g.UpperCount, g.LowerCount)
+					}
+				}
+			}
+		})
+	}
+}
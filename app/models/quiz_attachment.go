@@ -0,0 +1,173 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/studio-senkou/lentera-cendekia-be/database/facades"
+)
+
+const (
+	AttachmentKindImage = "image"
+	AttachmentKindAudio = "audio"
+)
+
+// QuizAttachment is an image or audio file shown with a question, or with one
+// of its options when OptionID is set. FilePath is the storage path returned
+// by storage.UploadFileToStorage.
+type QuizAttachment struct {
+	ID          uint      `json:"id"`
+	QuestionID  uint      `json:"question_id"`
+	OptionID    *uint     `json:"option_id,omitempty"`
+	Kind        string    `json:"kind"`
+	FilePath    string    `json:"file_path"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	FileSize    int64     `json:"file_size"`
+	SortOrder   int       `json:"sort_order"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// quizAttachmentColumns is the column list read by scanQuizAttachment.
+const quizAttachmentColumns = `
+	id, question_id, option_id, kind, file_path, file_name, content_type,
+	file_size, sort_order, created_at
+`
+
+func scanQuizAttachment(row rowScanner) (*QuizAttachment, error) {
+	a := new(QuizAttachment)
+	if err := row.Scan(
+		&a.ID, &a.QuestionID, &a.OptionID, &a.Kind, &a.FilePath, &a.FileName, &a.ContentType,
+		&a.FileSize, &a.SortOrder, &a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// loadQuizAttachments fills in the attachments of the questions and their
+// options, in display order.
+func loadQuizAttachments(db facades.DBExecutor, questions []QuizQuestion) error {
+	if len(questions) == 0 {
+		return nil
+	}
+	questionIDs := make([]int64, len(questions))
+	for i, q := range questions {
+		questionIDs[i] = int64(q.ID)
+	}
+
+	rows, err := db.Query(`
+		SELECT `+quizAttachmentColumns+`
+		FROM quiz_attachments
+		WHERE question_id = ANY($1)
+		ORDER BY sort_order ASC, id ASC
+	`, pq.Array(questionIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byQuestion := make(map[uint][]QuizAttachment)
+	byOption := make(map[uint][]QuizAttachment)
+	for rows.Next() {
+		a, err := scanQuizAttachment(rows)
+		if err != nil {
+			return err
+		}
+		if a.OptionID != nil {
+			byOption[*a.OptionID] = append(byOption[*a.OptionID], *a)
+		} else {
+			byQuestion[a.QuestionID] = append(byQuestion[a.QuestionID], *a)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range questions {
+		questions[i].Attachments = byQuestion[questions[i].ID]
+		for j := range questions[i].Options {
+			questions[i].Options[j].Attachments = byOption[questions[i].Options[j].ID]
+		}
+	}
+	return nil
+}
+
+// CreateAttachment adds the attachment after the existing ones of the same
+// question or option. It returns sql.ErrNoRows when the option does not
+// belong to the question.
+func (r *QuizAdminRepository) CreateAttachment(a *QuizAttachment) error {
+	query := `
+		INSERT INTO quiz_attachments (
+			question_id, option_id, kind, file_path, file_name, content_type, file_size, sort_order
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, COALESCE((
+			SELECT MAX(sort_order) + 1
+			FROM quiz_attachments
+			WHERE question_id = $1 AND option_id IS NOT DISTINCT FROM $2
+		), 0)
+		WHERE $2::INTEGER IS NULL
+		   OR EXISTS (SELECT 1 FROM quiz_options WHERE id = $2 AND question_id = $1)
+		RETURNING id, sort_order, created_at
+	`
+	return r.db.QueryRow(query,
+		a.QuestionID, a.OptionID, a.Kind, a.FilePath, a.FileName, a.ContentType, a.FileSize,
+	).Scan(&a.ID, &a.SortOrder, &a.CreatedAt)
+}
+
+// DeleteAttachment removes an attachment of the question and returns it so
+// its file can be removed from storage. It returns nil when it does not exist.
+func (r *QuizAdminRepository) DeleteAttachment(attachmentID, questionID uint) (*QuizAttachment, error) {
+	a, err := scanQuizAttachment(r.db.QueryRow(`
+		DELETE FROM quiz_attachments
+		WHERE id = $1 AND question_id = $2
+		RETURNING `+quizAttachmentColumns,
+		attachmentID, questionID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// ReorderAttachments sets the display order of the question's attachments to
+// the order of the given IDs. IDs of other questions are ignored.
+func (r *QuizAdminRepository) ReorderAttachments(questionID uint, attachmentIDs []int64) error {
+	_, err := r.db.Exec(`
+		UPDATE quiz_attachments a
+		SET sort_order = o.position - 1
+		FROM unnest($1::INTEGER[]) WITH ORDINALITY AS o(id, position)
+		WHERE a.id = o.id AND a.question_id = $2
+	`, pq.Array(attachmentIDs), questionID)
+	return err
+}
+
+// ListAttachmentPaths returns the storage paths of the question's
+// attachments, including those of its options, or only those of one option
+// when optionID is set. It is called before deleting the question or option
+// so the files can be removed afterwards.
+func (r *QuizAdminRepository) ListAttachmentPaths(questionID uint, optionID *uint) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT file_path
+		FROM quiz_attachments
+		WHERE question_id = $1 AND ($2::INTEGER IS NULL OR option_id = $2)
+	`, questionID, optionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	paths := make([]string, 0)
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, rows.Err()
+}
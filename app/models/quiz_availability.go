@@ -0,0 +1,127 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/studio-senkou/lentera-cendekia-be/database/facades"
+)
+
+const (
+	QuizAvailabilityUpcoming = "upcoming"
+	QuizAvailabilityOpen     = "open"
+	QuizAvailabilityClosed   = "closed"
+)
+
+// quizAvailabilityExpr derives the availability of a quiz_quizzes row from its
+// window using the database clock, like the attempt deadlines.
+const quizAvailabilityExpr = `CASE
+		WHEN closes_at IS NOT NULL AND closes_at <= NOW() THEN 'closed'
+		WHEN opens_at IS NOT NULL AND opens_at > NOW() THEN 'upcoming'
+		ELSE 'open'
+	END`
+
+// quizAssignedCondition matches quizzes the user at $1 may take: quizzes
+// assigned to a class the user is enrolled in. A quiz without assigned classes
+// is available to nobody. Listing and access checks share it so a quiz is
+// never startable without showing up in the student's list.
+const quizAssignedCondition = `EXISTS (
+	SELECT 1
+	FROM quiz_class_assignments qca
+		JOIN students s ON s.class_id = qca.class_id AND s.deleted_at IS NULL
+		JOIN classes c ON c.id = qca.class_id AND c.deleted_at IS NULL
+	WHERE qca.quiz_id = quiz_quizzes.id AND s.user_id = $1
+)`
+
+// checkQuizAccess returns an error when the user may not start an attempt of
+// the quiz right now.
+func checkQuizAccess(db facades.DBExecutor, quizID, userID uint) error {
+	var availability string
+	var assigned bool
+	err := db.QueryRow(`
+		SELECT `+quizAvailabilityExpr+`, `+quizAssignedCondition+`
+		FROM quiz_quizzes
+		WHERE id = $2 AND is_active = TRUE AND deleted_at IS NULL AND published_version_id IS NOT NULL
+	`, userID, quizID).Scan(&availability, &assigned)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrQuizNotFound
+		}
+		return err
+	}
+
+	switch {
+	case !assigned:
+		return ErrQuizNotAssigned
+	case availability == QuizAvailabilityUpcoming:
+		return ErrQuizNotOpen
+	case availability == QuizAvailabilityClosed:
+		return ErrQuizClosed
+	}
+	return nil
+}
+
+// CheckAccess reports whether the user may start the quiz now. It returns one
+// of ErrQuizNotFound, ErrQuizNotAssigned, ErrQuizNotOpen or ErrQuizClosed.
+func (r *QuizRepository) CheckAccess(userID, quizID uint) error {
+	return checkQuizAccess(r.db, quizID, userID)
+}
+
+// ListStudentQuizzes returns the active, published quizzes assigned to the
+// user's classes, soonest closing first.
+func (r *QuizRepository) ListStudentQuizzes(userID uint) ([]*QuizQuiz, error) {
+	query := `
+		SELECT ` + quizQuizColumns + `
+		FROM quiz_quizzes
+		WHERE is_active = TRUE AND deleted_at IS NULL AND published_version_id IS NOT NULL
+		  AND ` + quizAssignedCondition + `
+		ORDER BY closes_at ASC NULLS LAST, opens_at ASC NULLS FIRST, id ASC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quizzes := make([]*QuizQuiz, 0)
+	for rows.Next() {
+		quiz, err := scanQuizQuiz(rows)
+		if err != nil {
+			return nil, err
+		}
+		quizzes = append(quizzes, quiz)
+	}
+	return quizzes, rows.Err()
+}
+
+// ReplaceQuizClasses assigns the quiz to exactly the given classes; an empty
+// list leaves it unavailable to students. It returns ErrClassNotFound when one
+// of the classes does not exist. Run it inside a transaction.
+func (r *QuizAdminRepository) ReplaceQuizClasses(quizID uint, classIDs []string) error {
+	if _, err := r.db.Exec(`DELETE FROM quiz_class_assignments WHERE quiz_id = $1`, quizID); err != nil {
+		return err
+	}
+	if len(classIDs) == 0 {
+		return nil
+	}
+
+	unique := make(map[string]bool, len(classIDs))
+	for _, id := range classIDs {
+		unique[id] = true
+	}
+
+	res, err := r.db.Exec(`
+		INSERT INTO quiz_class_assignments (quiz_id, class_id)
+		SELECT $1, c.id
+		FROM classes c
+		WHERE c.id = ANY($2::UUID[]) AND c.deleted_at IS NULL
+	`, quizID, pq.Array(classIDs))
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if int(n) != len(unique) {
+		return ErrClassNotFound
+	}
+	return nil
+}
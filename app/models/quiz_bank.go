@@ -0,0 +1,281 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/studio-senkou/lentera-cendekia-be/database/facades"
+)
+
+type QuizQuestionBank struct {
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	Description   *string    `json:"description,omitempty"`
+	QuestionCount int        `json:"question_count"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+}
+
+// QuizSamplingRule draws QuestionCount random questions from a bank for every
+// attempt of a quiz, optionally limited to one tag and/or difficulty.
+type QuizSamplingRule struct {
+	ID            uint      `json:"id"`
+	QuizID        uint      `json:"quiz_id"`
+	BankID        uint      `json:"bank_id"`
+	Tag           *string   `json:"tag"`
+	Difficulty    *string   `json:"difficulty"`
+	QuestionCount int       `json:"question_count"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// QuizQuestionOwner identifies whether a question belongs to a quiz or to a
+// question bank. Exactly one of the IDs is set.
+type QuizQuestionOwner struct {
+	QuizID *uint
+	BankID *uint
+}
+
+// questionOwnerCondition matches the draft quiz_questions rows owned by the
+// quiz and bank IDs bound at $n and $n+1; the unused one is bound as NULL.
+// Rows frozen into a published version never match, so they cannot be edited.
+func questionOwnerCondition(n int) string {
+	return fmt.Sprintf(`quiz_id IS NOT DISTINCT FROM $%d AND bank_id IS NOT DISTINCT FROM $%d AND version_id IS NULL`, n, n+1)
+}
+
+// draftOptionCondition matches quiz_options rows of draft questions.
+const draftOptionCondition = `question_id IN (SELECT id FROM quiz_questions WHERE version_id IS NULL)`
+
+func tagsOrEmpty(tags pq.StringArray) pq.StringArray {
+	if tags == nil {
+		return pq.StringArray{}
+	}
+	return tags
+}
+
+const quizBankColumns = `
+	b.id, b.name, b.description,
+	(SELECT COUNT(*) FROM quiz_questions q WHERE q.bank_id = b.id AND q.version_id IS NULL),
+	b.created_at, b.updated_at
+`
+
+func scanQuizBank(row rowScanner) (*QuizQuestionBank, error) {
+	bank := new(QuizQuestionBank)
+	if err := row.Scan(
+		&bank.ID, &bank.Name, &bank.Description, &bank.QuestionCount, &bank.CreatedAt, &bank.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return bank, nil
+}
+
+// sampleQuestionsQuery picks random questions from the version's copy of a
+// bank that match the rule and are not in the excluded list, so overlapping
+// rules never repeat a question.
+const sampleQuestionsQuery = `
+	SELECT q.id, q.question_type
+	FROM quiz_questions q
+		JOIN quiz_question_banks b ON b.id = q.bank_id AND b.deleted_at IS NULL
+	WHERE q.bank_id = $1 AND q.version_id = $6
+	  AND ($2::TEXT IS NULL OR $2 = ANY(q.tags))
+	  AND ($3::TEXT IS NULL OR q.difficulty = $3)
+	  AND q.id <> ALL($4)
+	ORDER BY random()
+	LIMIT $5
+`
+
+// sampleBankQuestions draws the questions of every sampling rule of the
+// published version. Rules are applied in order and a bank that runs short
+// gives what it has.
+func sampleBankQuestions(db facades.DBExecutor, versionID uint, exclude []int64, questionTypes map[int64]string) ([]int64, error) {
+	rules, err := listSamplingRules(db, `version_id = $1`, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sampled := make([]int64, 0)
+	for _, rule := range rules {
+		taken := append(append([]int64{}, exclude...), sampled...)
+		rows, err := db.Query(sampleQuestionsQuery,
+			rule.BankID, rule.Tag, rule.Difficulty, pq.Array(taken), rule.QuestionCount, versionID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id int64
+			var questionType string
+			if err := rows.Scan(&id, &questionType); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			sampled = append(sampled, id)
+			questionTypes[id] = questionType
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return sampled, nil
+}
+
+// listSamplingRules loads the sampling rules matching condition. Draft rules
+// have no version_id; publishing copies them into the version.
+func listSamplingRules(db facades.DBExecutor, condition string, args ...any) ([]QuizSamplingRule, error) {
+	rows, err := db.Query(`
+		SELECT id, quiz_id, bank_id, tag, difficulty, question_count, created_at
+		FROM quiz_sampling_rules
+		WHERE `+condition+`
+		ORDER BY id ASC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]QuizSamplingRule, 0)
+	for rows.Next() {
+		var rule QuizSamplingRule
+		if err := rows.Scan(
+			&rule.ID, &rule.QuizID, &rule.BankID, &rule.Tag, &rule.Difficulty, &rule.QuestionCount, &rule.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *QuizAdminRepository) ListBanks() ([]*QuizQuestionBank, error) {
+	query := `
+		SELECT ` + quizBankColumns + `
+		FROM quiz_question_banks b
+		WHERE b.deleted_at IS NULL
+		ORDER BY b.created_at DESC
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	banks := make([]*QuizQuestionBank, 0)
+	for rows.Next() {
+		bank, err := scanQuizBank(rows)
+		if err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank)
+	}
+	return banks, rows.Err()
+}
+
+func (r *QuizAdminRepository) GetBankDetail(bankID uint) (*QuizQuestionBank, []QuizQuestion, error) {
+	bank, err := scanQuizBank(r.db.QueryRow(`
+		SELECT `+quizBankColumns+`
+		FROM quiz_question_banks b
+		WHERE b.id = $1 AND b.deleted_at IS NULL
+	`, bankID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	questions, err := listQuestionsWithAnswers(r.db, `bank_id = $1 AND version_id IS NULL`, bankID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bank, questions, nil
+}
+
+func (r *QuizAdminRepository) CreateBank(bank *QuizQuestionBank) error {
+	query := `
+		INSERT INTO quiz_question_banks (name, description)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRow(query, bank.Name, bank.Description).
+		Scan(&bank.ID, &bank.CreatedAt, &bank.UpdatedAt)
+}
+
+func (r *QuizAdminRepository) UpdateBank(bank *QuizQuestionBank) error {
+	query := `
+		UPDATE quiz_question_banks
+		SET name        = $1,
+		    description = $2,
+		    updated_at  = NOW()
+		WHERE id = $3 AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+	return r.db.QueryRow(query, bank.Name, bank.Description, bank.ID).Scan(&bank.UpdatedAt)
+}
+
+// DeleteBank soft-deletes the bank. Quizzes that sample from it stop drawing
+// its questions, while attempts that already drew them keep working.
+func (r *QuizAdminRepository) DeleteBank(bankID uint) error {
+	query := `UPDATE quiz_question_banks SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	res, err := r.db.Exec(query, bankID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *QuizAdminRepository) ListSamplingRules(quizID uint) ([]QuizSamplingRule, error) {
+	return listSamplingRules(r.db, `quiz_id = $1 AND version_id IS NULL`, quizID)
+}
+
+// CountBankQuestions returns how many bank questions match the tag and
+// difficulty filters, or -1 when the bank does not exist.
+func (r *QuizAdminRepository) CountBankQuestions(bankID uint, tag, difficulty *string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(q.id)
+		FROM quiz_question_banks b
+			LEFT JOIN quiz_questions q ON q.bank_id = b.id AND q.version_id IS NULL
+				AND ($2::TEXT IS NULL OR $2 = ANY(q.tags))
+				AND ($3::TEXT IS NULL OR q.difficulty = $3)
+		WHERE b.id = $1 AND b.deleted_at IS NULL
+		GROUP BY b.id
+	`, bankID, tag, difficulty).Scan(&count)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return -1, nil
+		}
+		return 0, err
+	}
+	return count, nil
+}
+
+// ReplaceSamplingRules swaps the quiz's sampling rules for the given set.
+// Run it inside a transaction so the quiz never has a partial rule set.
+func (r *QuizAdminRepository) ReplaceSamplingRules(quizID uint, rules []QuizSamplingRule) error {
+	if _, err := r.db.Exec(`DELETE FROM quiz_sampling_rules WHERE quiz_id = $1 AND version_id IS NULL`, quizID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO quiz_sampling_rules (quiz_id, bank_id, tag, difficulty, question_count)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	for i := range rules {
+		rules[i].QuizID = quizID
+		if err := r.db.QueryRow(query,
+			quizID, rules[i].BankID, rules[i].Tag, rules[i].Difficulty, rules[i].QuestionCount,
+		).Scan(&rules[i].ID, &rules[i].CreatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// QuizCertificate is issued once per student and quiz when a student first
+// passes a quiz that has certificates enabled. The student name, quiz title
+// and score are copied at issue time so verification keeps showing what was
+// certified. AttemptID is the attempt whose score was certified, nil when the
+// quiz averages its attempts.
+type QuizCertificate struct {
+	ID          uint       `json:"id"`
+	Code        string     `json:"code"`
+	QuizID      uint       `json:"quiz_id"`
+	UserID      uint       `json:"user_id"`
+	AttemptID   *uint      `json:"attempt_id"`
+	StudentName string     `json:"student_name"`
+	QuizTitle   string     `json:"quiz_title"`
+	Score       float64    `json:"score"`
+	FilePath    *string    `json:"file_path"`
+	IssuedAt    time.Time  `json:"issued_at"`
+	EmailedAt   *time.Time `json:"emailed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+const quizCertificateColumns = `
+	id, code, quiz_id, user_id, attempt_id, student_name, quiz_title, score,
+	file_path, issued_at, emailed_at, created_at, updated_at
+`
+
+func scanQuizCertificate(row interface{ Scan(...any) error }) (*QuizCertificate, error) {
+	var cert QuizCertificate
+	if err := row.Scan(
+		&cert.ID, &cert.Code, &cert.QuizID, &cert.UserID, &cert.AttemptID, &cert.StudentName, &cert.QuizTitle, &cert.Score,
+		&cert.FilePath, &cert.IssuedAt, &cert.EmailedAt, &cert.CreatedAt, &cert.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// IssueCertificate issues the user's certificate for the quiz when the quiz
+// has certificates enabled and the user's final score reaches the passing
+// score. It returns nil when no certificate is due or one was already issued.
+func (r *QuizRepository) IssueCertificate(userID, quizID uint) (*QuizCertificate, error) {
+	summary, err := r.GetAttemptSummary(userID, quizID)
+	if err != nil || summary == nil || summary.FinalScore == nil {
+		return nil, err
+	}
+
+	attemptID, err := r.scoredAttemptID(userID, quizID, summary.ScorePolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := generateQuizCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate code: %w", err)
+	}
+
+	query := `
+		INSERT INTO quiz_certificates (code, quiz_id, user_id, attempt_id, student_name, quiz_title, score)
+		SELECT $1, q.id, u.id, $4::INTEGER, u.name, q.title, $5::DOUBLE PRECISION
+		FROM quiz_quizzes q
+		JOIN users u ON u.id = $3
+		WHERE q.id = $2 AND q.certificate_enabled = TRUE AND $5::DOUBLE PRECISION >= q.passing_score
+		ON CONFLICT (quiz_id, user_id) DO NOTHING
+		RETURNING ` + quizCertificateColumns
+	cert, err := scanQuizCertificate(r.db.QueryRow(query, "LC-"+code, quizID, userID, attemptID, *summary.FinalScore))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cert, nil
+}
+
+// scoredAttemptID returns the completed attempt whose score the score policy
+// counts: the earliest attempt with the top score for "best" and the latest
+// one otherwise. An average is not the score of any one attempt, so it
+// returns nil for "average".
+func (r *QuizRepository) scoredAttemptID(userID, quizID uint, scorePolicy string) (*uint, error) {
+	order := `submitted_at DESC`
+	switch scorePolicy {
+	case "average":
+		return nil, nil
+	case "best":
+		order = `score DESC, submitted_at ASC`
+	}
+
+	var attemptID uint
+	err := r.db.QueryRow(`
+		SELECT id FROM quiz_attempts
+		WHERE quiz_id = $1 AND user_id = $2 AND status = 'completed'
+		ORDER BY `+order+`
+		LIMIT 1
+	`, quizID, userID).Scan(&attemptID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attemptID, nil
+}
+
+// GetCertificateByID returns the certificate, or nil when it does not exist.
+func (r *QuizRepository) GetCertificateByID(certificateID uint) (*QuizCertificate, error) {
+	query := `SELECT ` + quizCertificateColumns + ` FROM quiz_certificates WHERE id = $1`
+	cert, err := scanQuizCertificate(r.db.QueryRow(query, certificateID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cert, nil
+}
+
+// GetCertificateByCode returns the certificate with the verification code,
+// or nil when it does not exist.
+func (r *QuizRepository) GetCertificateByCode(code string) (*QuizCertificate, error) {
+	query := `SELECT ` + quizCertificateColumns + ` FROM quiz_certificates WHERE code = $1`
+	cert, err := scanQuizCertificate(r.db.QueryRow(query, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cert, nil
+}
+
+// SetCertificateFile records the storage path of the rendered PDF.
+func (r *QuizRepository) SetCertificateFile(certificateID uint, path string) error {
+	_, err := r.db.Exec(`
+		UPDATE quiz_certificates SET file_path = $1, updated_at = NOW() WHERE id = $2
+	`, path, certificateID)
+	return err
+}
+
+// MarkCertificateEmailed records that the certificate was sent to the student.
+func (r *QuizRepository) MarkCertificateEmailed(certificateID uint) error {
+	_, err := r.db.Exec(`
+		UPDATE quiz_certificates SET emailed_at = NOW(), updated_at = NOW() WHERE id = $1
+	`, certificateID)
+	return err
+}
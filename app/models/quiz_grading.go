@@ -0,0 +1,278 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+const (
+	QuestionTypeSingleChoice   = "single_choice"
+	QuestionTypeMultipleChoice = "multiple_choice"
+	QuestionTypeTrueFalse      = "true_false"
+	QuestionTypeShortAnswer    = "short_answer"
+	QuestionTypeNumeric        = "numeric"
+
+	ScoringModeAllOrNothing = "all_or_nothing"
+	ScoringModePartial      = "partial"
+
+	MatchModeExact           = "exact"
+	MatchModeCaseInsensitive = "case_insensitive"
+	MatchModeRegex           = "regex"
+
+	TextFormatPlain    = "plain"
+	TextFormatMarkdown = "markdown"
+	TextFormatLatex    = "latex"
+)
+
+// HasOptions reports whether the question is answered by picking options.
+func (q *QuizQuestion) HasOptions() bool {
+	switch q.QuestionType {
+	case QuestionTypeShortAnswer, QuestionTypeNumeric:
+		return false
+	default:
+		return true
+	}
+}
+
+// ApplyDefaults fills in the settings that are optional for the question type.
+func (q *QuizQuestion) ApplyDefaults() {
+	if q.QuestionType == "" {
+		q.QuestionType = QuestionTypeSingleChoice
+	}
+	if q.ScoringMode == "" {
+		q.ScoringMode = ScoringModeAllOrNothing
+	}
+	if q.TextFormat == "" {
+		q.TextFormat = TextFormatPlain
+	}
+	if q.Points == 0 {
+		q.Points = 1
+	}
+	if q.QuestionType == QuestionTypeShortAnswer && q.MatchMode == nil {
+		mode := MatchModeCaseInsensitive
+		q.MatchMode = &mode
+	}
+	if q.QuestionType != QuestionTypeShortAnswer {
+		q.MatchMode = nil
+		q.AcceptedAnswers = nil
+	}
+	if q.QuestionType != QuestionTypeNumeric {
+		q.NumericAnswer = nil
+		q.NumericTolerance = 0
+	}
+}
+
+// ValidateConfig checks the answer key of text and numeric questions.
+func (q *QuizQuestion) ValidateConfig() error {
+	switch q.QuestionType {
+	case QuestionTypeShortAnswer:
+		if len(q.AcceptedAnswers) == 0 {
+			return fmt.Errorf("short answer questions need at least one accepted answer")
+		}
+		if q.MatchMode != nil && *q.MatchMode == MatchModeRegex {
+			for _, pattern := range q.AcceptedAnswers {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("invalid accepted answer pattern %q: %w", pattern, err)
+				}
+			}
+		}
+	case QuestionTypeNumeric:
+		if q.NumericAnswer == nil {
+			return fmt.Errorf("numeric questions need a numeric answer")
+		}
+		if q.NumericTolerance < 0 {
+			return fmt.Errorf("numeric tolerance cannot be negative")
+		}
+	}
+	return nil
+}
+
+// ValidateOptions checks that a question answered by picking options has
+// enough options and a usable answer key. The options must be loaded with
+// their IsCorrect flags.
+func (q *QuizQuestion) ValidateOptions() error {
+	if !q.HasOptions() {
+		return nil
+	}
+	correct := 0
+	for _, o := range q.Options {
+		if o.IsCorrect {
+			correct++
+		}
+	}
+	switch {
+	case len(q.Options) < 2:
+		return fmt.Errorf("question needs at least two options")
+	case correct == 0:
+		return fmt.Errorf("question needs a correct option")
+	case correct > 1 && q.QuestionType != QuestionTypeMultipleChoice:
+		return fmt.Errorf("question can only have one correct option")
+	}
+	return nil
+}
+
+// Grade checks the answer against the question's answer key and sets
+// IsCorrect, Credit (the fraction 0..1 of the question earned) and
+// PointsAwarded. The question's options must be loaded with their IsCorrect
+// flags. It returns ErrQuizInvalidAnswer when the answer does not fit the
+// question type.
+func (q *QuizQuestion) Grade(answer *QuizAnswer) error {
+	answer.IsCorrect = false
+	answer.Credit = 0
+	answer.PointsAwarded = 0
+
+	var err error
+	switch q.QuestionType {
+	case QuestionTypeMultipleChoice:
+		err = q.gradeMultipleChoice(answer)
+	case QuestionTypeShortAnswer:
+		err = q.gradeShortAnswer(answer)
+	case QuestionTypeNumeric:
+		err = q.gradeNumeric(answer)
+	default:
+		err = q.gradeSingleChoice(answer)
+	}
+	if err != nil {
+		return err
+	}
+
+	answer.PointsAwarded = q.pointsFor(answer.Credit)
+	return nil
+}
+
+// pointsFor converts earned credit to points. Any credit earns its share of
+// the question's points; a wrong answer costs the penalty. Blank questions are
+// never graded, so they stay at 0.
+func (q *QuizQuestion) pointsFor(credit float64) float64 {
+	if credit > 0 {
+		return math.Round(credit*q.Points*100) / 100
+	}
+	return -q.PenaltyPoints
+}
+
+func (q *QuizQuestion) gradeSingleChoice(answer *QuizAnswer) error {
+	if answer.OptionID == nil {
+		return ErrQuizInvalidAnswer
+	}
+	for _, opt := range q.Options {
+		if opt.ID == *answer.OptionID {
+			answer.OptionIDs = nil
+			answer.TextAnswer = nil
+			answer.NumericAnswer = nil
+			if opt.IsCorrect {
+				answer.IsCorrect = true
+				answer.Credit = 1
+			}
+			return nil
+		}
+	}
+	return ErrQuizInvalidAnswer
+}
+
+func (q *QuizQuestion) gradeMultipleChoice(answer *QuizAnswer) error {
+	if len(answer.OptionIDs) == 0 {
+		return ErrQuizInvalidAnswer
+	}
+
+	correct := make(map[int64]bool, len(q.Options))
+	totalCorrect := 0
+	for _, opt := range q.Options {
+		correct[int64(opt.ID)] = opt.IsCorrect
+		if opt.IsCorrect {
+			totalCorrect++
+		}
+	}
+
+	selected := make(map[int64]bool, len(answer.OptionIDs))
+	uniqueIDs := make([]int64, 0, len(answer.OptionIDs))
+	hits, misses := 0, 0
+	for _, id := range answer.OptionIDs {
+		isCorrect, ok := correct[id]
+		if !ok {
+			return ErrQuizInvalidAnswer
+		}
+		if selected[id] {
+			continue
+		}
+		selected[id] = true
+		uniqueIDs = append(uniqueIDs, id)
+		if isCorrect {
+			hits++
+		} else {
+			misses++
+		}
+	}
+
+	answer.OptionIDs = uniqueIDs
+	answer.OptionID = nil
+	answer.TextAnswer = nil
+	answer.NumericAnswer = nil
+
+	answer.IsCorrect = totalCorrect > 0 && hits == totalCorrect && misses == 0
+	switch {
+	case answer.IsCorrect:
+		answer.Credit = 1
+	case q.ScoringMode == ScoringModePartial && totalCorrect > 0:
+		// Each wrong pick cancels one right pick, so selecting every option
+		// earns nothing unless most options are correct.
+		answer.Credit = math.Max(0, float64(hits-misses)/float64(totalCorrect))
+	}
+	return nil
+}
+
+func (q *QuizQuestion) gradeShortAnswer(answer *QuizAnswer) error {
+	if answer.TextAnswer == nil || strings.TrimSpace(*answer.TextAnswer) == "" {
+		return ErrQuizInvalidAnswer
+	}
+	answer.OptionID = nil
+	answer.OptionIDs = nil
+	answer.NumericAnswer = nil
+
+	given := normalizeShortAnswer(*answer.TextAnswer)
+	mode := MatchModeCaseInsensitive
+	if q.MatchMode != nil {
+		mode = *q.MatchMode
+	}
+
+	for _, accepted := range q.AcceptedAnswers {
+		var matched bool
+		switch mode {
+		case MatchModeExact:
+			matched = given == normalizeShortAnswer(accepted)
+		case MatchModeRegex:
+			pattern, err := regexp.Compile(`^(?:` + accepted + `)$`)
+			matched = err == nil && pattern.MatchString(given)
+		default:
+			matched = strings.EqualFold(given, normalizeShortAnswer(accepted))
+		}
+		if matched {
+			answer.IsCorrect = true
+			answer.Credit = 1
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *QuizQuestion) gradeNumeric(answer *QuizAnswer) error {
+	if answer.NumericAnswer == nil {
+		return ErrQuizInvalidAnswer
+	}
+	answer.OptionID = nil
+	answer.OptionIDs = nil
+	answer.TextAnswer = nil
+
+	if q.NumericAnswer != nil && math.Abs(*answer.NumericAnswer-*q.NumericAnswer) <= q.NumericTolerance {
+		answer.IsCorrect = true
+		answer.Credit = 1
+	}
+	return nil
+}
+
+// normalizeShortAnswer trims the answer and collapses inner whitespace so
+// stray spaces never decide whether an answer is right.
+func normalizeShortAnswer(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
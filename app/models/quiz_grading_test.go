@@ -0,0 +1,316 @@
+package models_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lib/pq"
+	. "github.com/studio-senkou/lentera-cendekia-be/app/models"
+)
+
+func uintPtr(v uint) *uint          { return &v }
+func stringPtr(v string) *string    { return &v }
+func float64Ptr(v float64) *float64 { return &v }
+
+// choiceQuestion returns a question whose options 1..n are correct when
+// listed in correct.
+func choiceQuestion(questionType, scoringMode string, points, penalty float64, n int, correct ...uint) QuizQuestion {
+	q := QuizQuestion{
+		QuestionType:  questionType,
+		ScoringMode:   scoringMode,
+		Points:        points,
+		PenaltyPoints: penalty,
+	}
+	for id := uint(1); id <= uint(n); id++ {
+		option := QuizOption{ID: id}
+		for _, c := range correct {
+			if c == id {
+				option.IsCorrect = true
+			}
+		}
+		q.Options = append(q.Options, option)
+	}
+	return q
+}
+
+func textQuestion(matchMode *string, accepted ...string) QuizQuestion {
+	return QuizQuestion{
+		QuestionType:    QuestionTypeShortAnswer,
+		MatchMode:       matchMode,
+		AcceptedAnswers: pq.StringArray(accepted),
+		Points:          2,
+		PenaltyPoints:   1,
+	}
+}
+
+func numericQuestion(answer, tolerance float64) QuizQuestion {
+	return QuizQuestion{
+		QuestionType:     QuestionTypeNumeric,
+		NumericAnswer:    float64Ptr(answer),
+		NumericTolerance: tolerance,
+		Points:           1,
+	}
+}
+
+func TestQuizQuestionGrade(t *testing.T) {
+	single := choiceQuestion(QuestionTypeSingleChoice, ScoringModeAllOrNothing, 4, 1, 3, 2)
+	trueFalse := choiceQuestion(QuestionTypeTrueFalse, ScoringModeAllOrNothing, 1, 0, 2, 1)
+	allOrNothing := choiceQuestion(QuestionTypeMultipleChoice, ScoringModeAllOrNothing, 3, 1, 4, 1, 2, 3)
+	partial := choiceQuestion(QuestionTypeMultipleChoice, ScoringModePartial, 3, 1, 4, 1, 2, 3)
+	halfCorrect := choiceQuestion(QuestionTypeMultipleChoice, ScoringModePartial, 2, 1, 4, 1, 2)
+
+	tests := []struct {
+		name        string
+		question    QuizQuestion
+		answer      QuizAnswer
+		wantErr     error
+		wantCorrect bool
+		wantCredit  float64
+		wantPoints  float64
+	}{
+		{
+			name:        "single choice correct",
+			question:    single,
+			answer:      QuizAnswer{OptionID: uintPtr(2)},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  4,
+		},
+		{
+			name:       "single choice wrong costs the penalty",
+			question:   single,
+			answer:     QuizAnswer{OptionID: uintPtr(1)},
+			wantPoints: -1,
+		},
+		{
+			name:     "single choice option of another question",
+			question: single,
+			answer:   QuizAnswer{OptionID: uintPtr(9)},
+			wantErr:  ErrQuizInvalidAnswer,
+		},
+		{
+			name:     "single choice without an option",
+			question: single,
+			answer:   QuizAnswer{TextAnswer: stringPtr("2")},
+			wantErr:  ErrQuizInvalidAnswer,
+		},
+		{
+			name:        "true/false correct",
+			question:    trueFalse,
+			answer:      QuizAnswer{OptionID: uintPtr(1)},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  1,
+		},
+		{
+			name:       "true/false wrong without a penalty",
+			question:   trueFalse,
+			answer:     QuizAnswer{OptionID: uintPtr(2)},
+			wantPoints: 0,
+		},
+		{
+			name:        "multiple choice all correct options",
+			question:    allOrNothing,
+			answer:      QuizAnswer{OptionIDs: pq.Int64Array{3, 1, 2}},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  3,
+		},
+		{
+			name:        "multiple choice duplicate picks count once",
+			question:    allOrNothing,
+			answer:      QuizAnswer{OptionIDs: pq.Int64Array{1, 1, 2, 3}},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  3,
+		},
+		{
+			name:       "multiple choice all or nothing with a missing option",
+			question:   allOrNothing,
+			answer:     QuizAnswer{OptionIDs: pq.Int64Array{1, 2}},
+			wantPoints: -1,
+		},
+		{
+			name:       "multiple choice partial with a missing option",
+			question:   partial,
+			answer:     QuizAnswer{OptionIDs: pq.Int64Array{1, 2}},
+			wantCredit: 2.0 / 3,
+			wantPoints: 2,
+		},
+		{
+			name:       "multiple choice partial wrong pick cancels a right one",
+			question:   partial,
+			answer:     QuizAnswer{OptionIDs: pq.Int64Array{1, 2, 4}},
+			wantCredit: 1.0 / 3,
+			wantPoints: 1,
+		},
+		{
+			name:       "multiple choice partial every option of a half-correct question",
+			question:   halfCorrect,
+			answer:     QuizAnswer{OptionIDs: pq.Int64Array{1, 2, 3, 4}},
+			wantPoints: -1,
+		},
+		{
+			name:       "multiple choice partial every option of a mostly correct question",
+			question:   partial,
+			answer:     QuizAnswer{OptionIDs: pq.Int64Array{1, 2, 3, 4}},
+			wantCredit: 2.0 / 3,
+			wantPoints: 2,
+		},
+		{
+			name:       "multiple choice partial no credit costs the penalty",
+			question:   partial,
+			answer:     QuizAnswer{OptionIDs: pq.Int64Array{1, 4}},
+			wantPoints: -1,
+		},
+		{
+			name:     "multiple choice unknown option",
+			question: partial,
+			answer:   QuizAnswer{OptionIDs: pq.Int64Array{1, 9}},
+			wantErr:  ErrQuizInvalidAnswer,
+		},
+		{
+			name:     "multiple choice without options",
+			question: partial,
+			answer:   QuizAnswer{OptionID: uintPtr(1)},
+			wantErr:  ErrQuizInvalidAnswer,
+		},
+		{
+			name:        "short answer ignores case by default",
+			question:    textQuestion(nil, "Jakarta"),
+			answer:      QuizAnswer{TextAnswer: stringPtr("  jakarta ")},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  2,
+		},
+		{
+			name:        "short answer collapses inner whitespace",
+			question:    textQuestion(stringPtr(MatchModeExact), "New York"),
+			answer:      QuizAnswer{TextAnswer: stringPtr("New   York")},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  2,
+		},
+		{
+			name:       "short answer exact match is case sensitive",
+			question:   textQuestion(stringPtr(MatchModeExact), "Jakarta"),
+			answer:     QuizAnswer{TextAnswer: stringPtr("jakarta")},
+			wantPoints: -1,
+		},
+		{
+			name:        "short answer matches any accepted answer",
+			question:    textQuestion(stringPtr(MatchModeCaseInsensitive), "Batavia", "Jakarta"),
+			answer:      QuizAnswer{TextAnswer: stringPtr("JAKARTA")},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  2,
+		},
+		{
+			name:        "short answer regex",
+			question:    textQuestion(stringPtr(MatchModeRegex), "colou?r"),
+			answer:      QuizAnswer{TextAnswer: stringPtr("color")},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  2,
+		},
+		{
+			name:       "short answer regex must match the whole answer",
+			question:   textQuestion(stringPtr(MatchModeRegex), "colou?r"),
+			answer:     QuizAnswer{TextAnswer: stringPtr("colors")},
+			wantPoints: -1,
+		},
+		{
+			name:       "short answer invalid regex never matches",
+			question:   textQuestion(stringPtr(MatchModeRegex), "(colour"),
+			answer:     QuizAnswer{TextAnswer: stringPtr("(colour")},
+			wantPoints: -1,
+		},
+		{
+			name:     "short answer blank",
+			question: textQuestion(nil, "Jakarta"),
+			answer:   QuizAnswer{TextAnswer: stringPtr("   ")},
+			wantErr:  ErrQuizInvalidAnswer,
+		},
+		{
+			name:        "numeric within tolerance",
+			question:    numericQuestion(3.14, 0.01),
+			answer:      QuizAnswer{NumericAnswer: float64Ptr(3.145)},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  1,
+		},
+		{
+			name:        "numeric exact without tolerance",
+			question:    numericQuestion(16, 0),
+			answer:      QuizAnswer{NumericAnswer: float64Ptr(16)},
+			wantCorrect: true,
+			wantCredit:  1,
+			wantPoints:  1,
+		},
+		{
+			name:       "numeric outside tolerance",
+			question:   numericQuestion(3.14, 0.01),
+			answer:     QuizAnswer{NumericAnswer: float64Ptr(3.2)},
+			wantPoints: 0,
+		},
+		{
+			name:     "numeric without a number",
+			question: numericQuestion(3.14, 0.01),
+			answer:   QuizAnswer{TextAnswer: stringPtr("3.14")},
+			wantErr:  ErrQuizInvalidAnswer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answer := tt.answer
+			err := tt.question.Grade(&answer)
+			if tt.wantErr != nil {
+				if err != tt.wantErr {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if answer.IsCorrect != tt.wantCorrect {
+				t.Errorf("expected is_correct %v, got %v", tt.wantCorrect, answer.IsCorrect)
+			}
+			if math.Abs(answer.Credit-tt.wantCredit) > 1e-9 {
+				t.Errorf("expected credit %v, got %v", tt.wantCredit, answer.Credit)
+			}
+			if answer.PointsAwarded != tt.wantPoints {
+				t.Errorf("expected %v points, got %v", tt.wantPoints, answer.PointsAwarded)
+			}
+		})
+	}
+}
+
+func TestQuizQuestionGradeRoundsPartialPoints(t *testing.T) {
+	q := choiceQuestion(QuestionTypeMultipleChoice, ScoringModePartial, 1, 0, 4, 1, 2, 3)
+	answer := QuizAnswer{OptionIDs: pq.Int64Array{1}}
+	if err := q.Grade(&answer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer.PointsAwarded != 0.33 {
+		t.Fatalf("expected 0.33 points, got %v", answer.PointsAwarded)
+	}
+}
+
+func TestQuizQuestionGradeKeepsOnlyTheAnswerField(t *testing.T) {
+	q := numericQuestion(4, 0)
+	answer := QuizAnswer{
+		OptionID:      uintPtr(1),
+		OptionIDs:     pq.Int64Array{1, 2},
+		TextAnswer:    stringPtr("4"),
+		NumericAnswer: float64Ptr(4),
+	}
+	if err := q.Grade(&answer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer.OptionID != nil || answer.OptionIDs != nil || answer.TextAnswer != nil {
+		t.Fatalf("expected only the numeric answer to be kept, got %+v", answer)
+	}
+}
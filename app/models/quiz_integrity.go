@@ -0,0 +1,187 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
+)
+
+const (
+	// Reported by the client while the attempt is in progress.
+	QuizEventWindowBlur     = "window_blur"
+	QuizEventFullscreenExit = "fullscreen_exit"
+	QuizEventCopy           = "copy"
+	QuizEventPaste          = "paste"
+	QuizEventTabSwitch      = "tab_switch"
+
+	// Recorded by the server.
+	QuizEventAttemptStarted    = "attempt_started"
+	QuizEventQuestionNavigated = "question_navigated"
+	QuizEventAttemptSubmitted  = "attempt_submitted"
+)
+
+// QuizAttemptEvent is one proctoring signal of an attempt, with the IP
+// address and User-Agent of the request that reported it.
+type QuizAttemptEvent struct {
+	ID         uint            `json:"id"`
+	AttemptID  uint            `json:"attempt_id"`
+	EventType  string          `json:"event_type"`
+	Details    json.RawMessage `json:"details,omitempty"`
+	IPAddress  *string         `json:"ip_address"`
+	UserAgent  *string         `json:"user_agent"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// QuizIntegritySummary counts an attempt's proctoring signals. Flags names
+// the counts that reached their threshold; Flagged is set when any did.
+type QuizIntegritySummary struct {
+	FocusLosses     int      `json:"focus_losses"`
+	TabSwitches     int      `json:"tab_switches"`
+	FullscreenExits int      `json:"fullscreen_exits"`
+	CopyPastes      int      `json:"copy_pastes"`
+	IPAddresses     int      `json:"ip_addresses"`
+	UserAgents      int      `json:"user_agents"`
+	Flagged         bool     `json:"flagged"`
+	Flags           []string `json:"flags"`
+}
+
+// QuizIntegrityThresholds are the counts at which an attempt is flagged for
+// review. A threshold of 0 turns its check off.
+type QuizIntegrityThresholds struct {
+	FocusLosses     int `json:"focus_losses"`
+	TabSwitches     int `json:"tab_switches"`
+	FullscreenExits int `json:"fullscreen_exits"`
+	CopyPastes      int `json:"copy_pastes"`
+	IPAddresses     int `json:"ip_addresses"`
+	UserAgents      int `json:"user_agents"`
+}
+
+// LoadQuizIntegrityThresholds reads the auto-flag thresholds from the
+// QUIZ_FLAG_* environment variables.
+func LoadQuizIntegrityThresholds() QuizIntegrityThresholds {
+	return QuizIntegrityThresholds{
+		FocusLosses:     envThreshold("QUIZ_FLAG_FOCUS_LOSSES", 5),
+		TabSwitches:     envThreshold("QUIZ_FLAG_TAB_SWITCHES", 3),
+		FullscreenExits: envThreshold("QUIZ_FLAG_FULLSCREEN_EXITS", 3),
+		CopyPastes:      envThreshold("QUIZ_FLAG_COPY_PASTES", 3),
+		IPAddresses:     envThreshold("QUIZ_FLAG_IP_ADDRESSES", 2),
+		UserAgents:      envThreshold("QUIZ_FLAG_USER_AGENTS", 2),
+	}
+}
+
+func envThreshold(key string, fallback int) int {
+	value, err := strconv.Atoi(app.GetEnv(key, strconv.Itoa(fallback)))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// Evaluate sets Flags and Flagged from the thresholds.
+func (s *QuizIntegritySummary) Evaluate(t QuizIntegrityThresholds) {
+	checks := []struct {
+		name      string
+		count     int
+		threshold int
+	}{
+		{"focus_losses", s.FocusLosses, t.FocusLosses},
+		{"tab_switches", s.TabSwitches, t.TabSwitches},
+		{"fullscreen_exits", s.FullscreenExits, t.FullscreenExits},
+		{"copy_pastes", s.CopyPastes, t.CopyPastes},
+		{"ip_addresses", s.IPAddresses, t.IPAddresses},
+		{"user_agents", s.UserAgents, t.UserAgents},
+	}
+
+	s.Flags = make([]string, 0)
+	for _, check := range checks {
+		if check.threshold > 0 && check.count >= check.threshold {
+			s.Flags = append(s.Flags, check.name)
+		}
+	}
+	s.Flagged = len(s.Flags) > 0
+}
+
+// quizIntegrityJoin counts the events of each attempt for
+// quizIntegrityColumns. It must follow quiz_attempts a in the FROM clause.
+const quizIntegrityJoin = `
+	LEFT JOIN LATERAL (
+		SELECT
+			COUNT(*) FILTER (WHERE event_type = 'window_blur') AS focus_losses,
+			COUNT(*) FILTER (WHERE event_type = 'tab_switch') AS tab_switches,
+			COUNT(*) FILTER (WHERE event_type = 'fullscreen_exit') AS fullscreen_exits,
+			COUNT(*) FILTER (WHERE event_type IN ('copy', 'paste')) AS copy_pastes,
+			COUNT(DISTINCT ip_address) AS ip_addresses,
+			COUNT(DISTINCT user_agent) AS user_agents
+		FROM quiz_attempt_events
+		WHERE attempt_id = a.id
+	) integrity ON TRUE
+`
+
+// quizIntegrityColumns is the column list read into integritySummaryDest.
+const quizIntegrityColumns = `
+	integrity.focus_losses, integrity.tab_switches, integrity.fullscreen_exits,
+	integrity.copy_pastes, integrity.ip_addresses, integrity.user_agents
+`
+
+// integritySummaryDest returns the scan destinations for quizIntegrityColumns.
+func integritySummaryDest(s *QuizIntegritySummary) []any {
+	return []any{
+		&s.FocusLosses, &s.TabSwitches, &s.FullscreenExits, &s.CopyPastes, &s.IPAddresses, &s.UserAgents,
+	}
+}
+
+// RecordAttemptEvents stores proctoring events of an attempt.
+func (r *QuizRepository) RecordAttemptEvents(events []QuizAttemptEvent) error {
+	for i := range events {
+		e := &events[i]
+		var details any
+		if len(e.Details) > 0 {
+			details = string(e.Details)
+		}
+		if err := r.db.QueryRow(`
+			INSERT INTO quiz_attempt_events (attempt_id, event_type, details, ip_address, user_agent, occurred_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at
+		`, e.AttemptID, e.EventType, details, e.IPAddress, e.UserAgent, e.OccurredAt).Scan(&e.ID, &e.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListAttemptEvents returns the events of an attempt of the quiz in the
+// order they happened, or nil when the attempt does not belong to the quiz.
+func (r *QuizAdminRepository) ListAttemptEvents(quizID, attemptID uint) ([]QuizAttemptEvent, error) {
+	var exists bool
+	if err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM quiz_attempts WHERE id = $1 AND quiz_id = $2)`, attemptID, quizID,
+	).Scan(&exists); err != nil || !exists {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, attempt_id, event_type, details, ip_address, user_agent, occurred_at, created_at
+		FROM quiz_attempt_events
+		WHERE attempt_id = $1
+		ORDER BY occurred_at ASC, id ASC
+	`, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]QuizAttemptEvent, 0)
+	for rows.Next() {
+		var e QuizAttemptEvent
+		if err := rows.Scan(
+			&e.ID, &e.AttemptID, &e.EventType, &e.Details, &e.IPAddress, &e.UserAgent, &e.OccurredAt, &e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/studio-senkou/lentera-cendekia-be/utils/cache"
+)
+
+// QuizLeaderboardCacheTTL bounds how stale a cached leaderboard may get when
+// an invalidation is missed, e.g. for attempts finalized on read.
+const QuizLeaderboardCacheTTL = 10 * time.Minute
+
+// QuizLeaderboardEntry is one student's best completed attempt of a quiz.
+// Students are ranked by score, then by how long the attempt took; ties share
+// a rank.
+type QuizLeaderboardEntry struct {
+	Rank            int       `json:"rank"`
+	UserID          uint      `json:"user_id,omitempty"`
+	Name            string    `json:"name"`
+	AttemptID       uint      `json:"attempt_id,omitempty"`
+	Score           float64   `json:"score"`
+	DurationSeconds int       `json:"duration_seconds"`
+	SubmittedAt     time.Time `json:"submitted_at"`
+	IsCurrentUser   bool      `json:"is_current_user"`
+}
+
+// QuizLeaderboardFilter narrows a leaderboard to the students of one class
+// and to attempts submitted in [From, To).
+type QuizLeaderboardFilter struct {
+	ClassID *string
+	From    *time.Time
+	To      *time.Time
+}
+
+func (f QuizLeaderboardFilter) cacheKey(quizID uint) string {
+	classID, from, to := "all", "-", "-"
+	if f.ClassID != nil {
+		classID = *f.ClassID
+	}
+	if f.From != nil {
+		from = f.From.UTC().Format(time.RFC3339)
+	}
+	if f.To != nil {
+		to = f.To.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("quiz:leaderboard:%d:%s:%s:%s", quizID, classID, from, to)
+}
+
+// GetAssignedQuiz returns the active, published quiz when the user may take
+// it, or nil otherwise.
+func (r *QuizRepository) GetAssignedQuiz(userID, quizID uint) (*QuizQuiz, error) {
+	query := `
+		SELECT ` + quizQuizColumns + `
+		FROM quiz_quizzes
+		WHERE id = $2 AND is_active = TRUE AND deleted_at IS NULL AND published_version_id IS NOT NULL
+		  AND ` + quizAssignedCondition
+	quiz, err := scanQuizQuiz(r.db.QueryRow(query, userID, quizID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return quiz, nil
+}
+
+// GetQuizByCode returns the active, published quiz with the code, or nil.
+func (r *QuizRepository) GetQuizByCode(code string) (*QuizQuiz, error) {
+	query := `
+		SELECT ` + quizQuizColumns + `
+		FROM quiz_quizzes
+		WHERE code = $1 AND is_active = TRUE AND deleted_at IS NULL AND published_version_id IS NOT NULL
+	`
+	quiz, err := scanQuizQuiz(r.db.QueryRow(query, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return quiz, nil
+}
+
+// GetLeaderboard returns the full ranking of the quiz for the filter. Rankings
+// are cached in Redis until InvalidateQuizLeaderboard is called for the quiz;
+// when Redis is unavailable the ranking is read from the database.
+func (r *QuizRepository) GetLeaderboard(ctx context.Context, quizID uint, filter QuizLeaderboardFilter) ([]QuizLeaderboardEntry, error) {
+	if cache.RedisClient == nil {
+		return r.queryLeaderboard(quizID, filter)
+	}
+
+	key := filter.cacheKey(quizID)
+	var entries []QuizLeaderboardEntry
+	if err := cache.Get(ctx, key, &entries); err == nil {
+		return entries, nil
+	}
+
+	entries, err := r.queryLeaderboard(quizID, filter)
+	if err != nil {
+		return nil, err
+	}
+	_ = cache.Set(ctx, key, entries, QuizLeaderboardCacheTTL)
+	return entries, nil
+}
+
+func (r *QuizRepository) queryLeaderboard(quizID uint, filter QuizLeaderboardFilter) ([]QuizLeaderboardEntry, error) {
+	query := `
+		WITH best AS (
+			SELECT DISTINCT ON (a.user_id)
+				a.user_id, a.id AS attempt_id, a.score, a.submitted_at,
+				CEIL(EXTRACT(EPOCH FROM (a.submitted_at - a.started_at)))::INTEGER AS duration_seconds
+			FROM quiz_attempts a
+			WHERE a.quiz_id = $1 AND a.status = 'completed' AND a.score IS NOT NULL AND a.submitted_at IS NOT NULL
+			  AND ($2::UUID IS NULL OR EXISTS (
+				SELECT 1 FROM students s
+				WHERE s.user_id = a.user_id AND s.class_id = $2::UUID AND s.deleted_at IS NULL
+			  ))
+			  AND ($3::TIMESTAMPTZ IS NULL OR a.submitted_at >= $3::TIMESTAMPTZ)
+			  AND ($4::TIMESTAMPTZ IS NULL OR a.submitted_at < $4::TIMESTAMPTZ)
+			ORDER BY a.user_id, a.score DESC, (a.submitted_at - a.started_at) ASC, a.submitted_at ASC
+		)
+		SELECT
+			RANK() OVER (ORDER BY b.score DESC, b.duration_seconds ASC) AS rank,
+			b.user_id, u.name, b.attempt_id, b.score, b.duration_seconds, b.submitted_at
+		FROM best b
+			JOIN users u ON u.id = b.user_id AND u.deleted_at IS NULL
+		ORDER BY rank ASC, b.submitted_at ASC
+	`
+	rows, err := r.db.Query(query, quizID, filter.ClassID, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]QuizLeaderboardEntry, 0)
+	for rows.Next() {
+		var e QuizLeaderboardEntry
+		if err := rows.Scan(
+			&e.Rank, &e.UserID, &e.Name, &e.AttemptID, &e.Score, &e.DurationSeconds, &e.SubmittedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// InvalidateQuizLeaderboard drops every cached ranking of the quiz. Call it
+// whenever completed attempts of the quiz change.
+func InvalidateQuizLeaderboard(ctx context.Context, quizID uint) error {
+	if cache.RedisClient == nil {
+		return nil
+	}
+	return cache.DeletePattern(ctx, fmt.Sprintf("quiz:leaderboard:%d:*", quizID))
+}
+
+// AnonymizeLeaderboard hides who the other students are: only the viewer's
+// own entry keeps its name and IDs.
+func AnonymizeLeaderboard(entries []QuizLeaderboardEntry, viewerID uint) {
+	for i := range entries {
+		if viewerID != 0 && entries[i].UserID == viewerID {
+			continue
+		}
+		entries[i].Name = fmt.Sprintf("Peserta %d", i+1)
+		entries[i].UserID = 0
+		entries[i].AttemptID = 0
+	}
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// SyncDraftAnswerKeys copies the answer keys of the quiz's draft questions,
+// and of the bank questions its versions sampled, into the published copies
+// made from them: the is_correct flags of the options and the accepted and
+// numeric answers. Copies whose draft was deleted or changed its question
+// type are left alone. It returns the copies that changed, with their answer
+// keys loaded. Run it inside a transaction.
+func (r *QuizAdminRepository) SyncDraftAnswerKeys(quizID uint) ([]QuizQuestion, error) {
+	changedIDs := make(map[int64]bool)
+
+	rows, err := r.db.Query(`
+		UPDATE quiz_questions frozen
+		SET accepted_answers  = draft.accepted_answers,
+		    numeric_answer    = draft.numeric_answer,
+		    numeric_tolerance = draft.numeric_tolerance,
+		    updated_at        = NOW()
+		FROM quiz_questions draft
+		WHERE frozen.source_question_id = draft.id
+		  AND draft.version_id IS NULL
+		  AND frozen.version_id IN (SELECT id FROM quiz_versions WHERE quiz_id = $1)
+		  AND frozen.question_type = draft.question_type
+		  AND (frozen.accepted_answers IS DISTINCT FROM draft.accepted_answers
+		    OR frozen.numeric_answer IS DISTINCT FROM draft.numeric_answer
+		    OR frozen.numeric_tolerance IS DISTINCT FROM draft.numeric_tolerance)
+		RETURNING frozen.id
+	`, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if err := collectIDs(rows, changedIDs); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Query(`
+		UPDATE quiz_options frozen
+		SET is_correct = draft.is_correct,
+		    updated_at = NOW()
+		FROM quiz_options draft, quiz_questions frozen_question, quiz_questions draft_question
+		WHERE frozen.source_option_id = draft.id
+		  AND frozen_question.id = frozen.question_id
+		  AND draft_question.id = draft.question_id
+		  AND draft_question.id = frozen_question.source_question_id
+		  AND draft_question.version_id IS NULL
+		  AND frozen_question.version_id IN (SELECT id FROM quiz_versions WHERE quiz_id = $1)
+		  AND frozen_question.question_type = draft_question.question_type
+		  AND frozen.is_correct <> draft.is_correct
+		RETURNING frozen.question_id
+	`, quizID)
+	if err != nil {
+		return nil, err
+	}
+	if err := collectIDs(rows, changedIDs); err != nil {
+		return nil, err
+	}
+
+	if len(changedIDs) == 0 {
+		return make([]QuizQuestion, 0), nil
+	}
+	ids := make([]int64, 0, len(changedIDs))
+	for id := range changedIDs {
+		ids = append(ids, id)
+	}
+	return listQuestionsWithAnswers(r.db, `id = ANY($1)`, pq.Array(ids))
+}
+
+// RegradeQuiz grades every saved answer of the quiz's completed attempts
+// again against the answer key of the version the attempt was taken on and
+// rescores the attempts. Run it inside a transaction.
+func (r *QuizAdminRepository) RegradeQuiz(quizID uint) (*QuizRegradeResult, error) {
+	answers, err := r.completedAnswers(`attempt_id IN (SELECT id FROM quiz_attempts WHERE quiz_id = $1)`, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QuizRegradeResult{Changes: make([]QuizRegradeChange, 0)}
+	if len(answers) > 0 {
+		seen := make(map[uint]bool)
+		questionIDs := make([]int64, 0)
+		for _, ans := range answers {
+			if !seen[ans.QuestionID] {
+				seen[ans.QuestionID] = true
+				questionIDs = append(questionIDs, int64(ans.QuestionID))
+			}
+		}
+		questions, err := listQuestionsWithAnswers(r.db, `id = ANY($1)`, pq.Array(questionIDs))
+		if err != nil {
+			return nil, err
+		}
+		questionMap := make(map[uint]*QuizQuestion, len(questions))
+		for i := range questions {
+			questionMap[questions[i].ID] = &questions[i]
+		}
+
+		for _, ans := range answers {
+			q, ok := questionMap[ans.QuestionID]
+			if !ok {
+				continue
+			}
+			changed, err := r.regradeAnswer(q, ans)
+			if err != nil {
+				return nil, err
+			}
+			if changed {
+				result.AnswersChanged++
+			}
+		}
+	}
+
+	if err := r.rescoreAttempts(result, `quiz_id = $1`, quizID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// collectIDs adds the IDs in the rows to ids and closes the rows.
+func collectIDs(rows *sql.Rows, ids map[int64]bool) error {
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		ids[id] = true
+	}
+	return rows.Err()
+}
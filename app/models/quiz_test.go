@@ -0,0 +1,36 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	. "github.com/studio-senkou/lentera-cendekia-be/app/models"
+)
+
+func TestQuizAttemptHasQuestion(t *testing.T) {
+	attempt := &QuizAttempt{QuestionIDs: pq.Int64Array{4, 8, 15}}
+
+	for _, id := range []uint{4, 8, 15} {
+		if !attempt.HasQuestion(id) {
+			t.Fatalf("expected question %d to be part of the attempt", id)
+		}
+	}
+	if attempt.HasQuestion(16) {
+		t.Fatal("expected question 16 not to be part of the attempt")
+	}
+}
+
+func TestSubmitAnswersRejectsQuestionsOutsideAttempt(t *testing.T) {
+	// The check runs before the database is touched, so no connection is needed.
+	repo := NewQuizRepository(nil)
+	attempt := &QuizAttempt{ID: 1, QuestionIDs: pq.Int64Array{4, 8}}
+	optionID := uint(1)
+
+	answers := []QuizAnswer{
+		{QuestionID: 4, OptionID: &optionID},
+		{QuestionID: 23, OptionID: &optionID},
+	}
+	if _, err := repo.SubmitAnswers(attempt, answers); err != ErrQuizInvalidAnswer {
+		t.Fatalf("expected ErrQuizInvalidAnswer, got %v", err)
+	}
+}
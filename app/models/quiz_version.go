@@ -0,0 +1,464 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/studio-senkou/lentera-cendekia-be/database/facades"
+)
+
+// QuizVersion is a published snapshot of a quiz. Publishing copies the draft
+// questions, their options and attachments, the sampling rules and the
+// questions of the sampled banks into rows tied to the version, which are
+// never edited afterwards except for answer key corrections. Attempts are
+// pinned to the version that was published when they started.
+type QuizVersion struct {
+	ID            uint      `json:"id"`
+	QuizID        uint      `json:"quiz_id"`
+	VersionNumber int       `json:"version_number"`
+	Notes         *string   `json:"notes"`
+	QuestionCount int       `json:"question_count"`
+	PublishedBy   *uint     `json:"published_by"`
+	PublishedAt   time.Time `json:"published_at"`
+	IsCurrent     bool      `json:"is_current"`
+	AttemptCount  int       `json:"attempt_count"`
+}
+
+// quizVersionColumns is the column list read by scanQuizVersion. It must be
+// selected from quiz_versions v joined with quiz_quizzes q.
+const quizVersionColumns = `
+	v.id, v.quiz_id, v.version_number, v.notes, v.question_count, v.published_by, v.published_at,
+	COALESCE(q.published_version_id = v.id, FALSE),
+	(SELECT COUNT(*) FROM quiz_attempts a WHERE a.version_id = v.id)
+`
+
+func scanQuizVersion(row rowScanner) (*QuizVersion, error) {
+	v := new(QuizVersion)
+	if err := row.Scan(
+		&v.ID, &v.QuizID, &v.VersionNumber, &v.Notes, &v.QuestionCount, &v.PublishedBy, &v.PublishedAt,
+		&v.IsCurrent, &v.AttemptCount,
+	); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// QuizRegradeResult summarises a regrade: how many completed attempts were
+// scored again, how many answers and scores changed and how many attempts
+// went from passed to failed or back. Changes lists every attempt whose score
+// changed.
+type QuizRegradeResult struct {
+	AttemptCount      int                 `json:"attempt_count"`
+	AnswersChanged    int                 `json:"answers_changed"`
+	ScoresChanged     int                 `json:"scores_changed"`
+	PassStatusChanged int                 `json:"pass_status_changed"`
+	Changes           []QuizRegradeChange `json:"changes"`
+}
+
+// QuizRegradeChange is an attempt whose score changed in a regrade. Pass or
+// fail is judged against the quiz's current passing score.
+type QuizRegradeChange struct {
+	AttemptID        uint     `json:"attempt_id"`
+	UserID           uint     `json:"user_id"`
+	PreviousScore    *float64 `json:"previous_score"`
+	Score            *float64 `json:"score"`
+	PreviouslyPassed bool     `json:"previously_passed"`
+	Passed           bool     `json:"passed"`
+}
+
+// rescoreAttemptsQuery scores the completed attempts matching condition again
+// from their saved answers and returns each one's owner, score before and
+// after, and the quiz's passing score.
+func rescoreAttemptsQuery(condition string) string {
+	return `
+		UPDATE quiz_attempts
+		SET raw_score  = ` + attemptRawScoreExpr + `,
+		    max_score  = ` + attemptMaxScoreExpr + `,
+		    score      = ` + attemptScoreExpr + `,
+		    updated_at = NOW()
+		FROM (
+			SELECT id, score FROM quiz_attempts WHERE status = 'completed' AND ` + condition + `
+		) previous
+		WHERE quiz_attempts.id = previous.id
+		RETURNING quiz_attempts.id, quiz_attempts.user_id, previous.score, quiz_attempts.score,
+			(SELECT passing_score FROM quiz_quizzes WHERE id = quiz_attempts.quiz_id)
+	`
+}
+
+// freezeQuestions copies the draft questions matching condition, with their
+// options and attachments, into the version and returns how many were
+// copied. $1 is bound to the version ID; the condition's own arguments start
+// at $2. Attachment files are shared with the draft, not duplicated.
+func freezeQuestions(db facades.DBExecutor, versionID uint, condition string, args ...any) (int, error) {
+	rows, err := db.Query(`
+		INSERT INTO quiz_questions (
+			quiz_id, bank_id, version_id, source_question_id, question_text, text_format, question_type,
+			scoring_mode, match_mode, accepted_answers, numeric_answer, numeric_tolerance, tags,
+			difficulty, explanation, points, penalty_points
+		)
+		SELECT
+			quiz_id, bank_id, $1, id, question_text, text_format, question_type,
+			scoring_mode, match_mode, accepted_answers, numeric_answer, numeric_tolerance, tags,
+			difficulty, explanation, points, penalty_points
+		FROM quiz_questions
+		WHERE `+condition+`
+		ORDER BY id ASC
+		RETURNING source_question_id, id
+	`, append([]any{versionID}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	sourceIDs, copyIDs, err := scanIDPairs(rows)
+	if err != nil || len(copyIDs) == 0 {
+		return 0, err
+	}
+
+	rows, err = db.Query(`
+		INSERT INTO quiz_options (question_id, source_option_id, option_text, text_format, is_correct, explanation)
+		SELECT m.copy_id, o.id, o.option_text, o.text_format, o.is_correct, o.explanation
+		FROM quiz_options o
+			JOIN unnest($1::INTEGER[], $2::INTEGER[]) AS m(source_id, copy_id) ON m.source_id = o.question_id
+		ORDER BY o.id ASC
+		RETURNING source_option_id, id
+	`, pq.Array(sourceIDs), pq.Array(copyIDs))
+	if err != nil {
+		return 0, err
+	}
+	sourceOptionIDs, copyOptionIDs, err := scanIDPairs(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO quiz_attachments (
+			question_id, option_id, kind, file_path, file_name, content_type, file_size, sort_order
+		)
+		SELECT qm.copy_id, om.copy_id, a.kind, a.file_path, a.file_name, a.content_type, a.file_size, a.sort_order
+		FROM quiz_attachments a
+			JOIN unnest($1::INTEGER[], $2::INTEGER[]) AS qm(source_id, copy_id) ON qm.source_id = a.question_id
+			LEFT JOIN unnest($3::INTEGER[], $4::INTEGER[]) AS om(source_id, copy_id) ON om.source_id = a.option_id
+	`, pq.Array(sourceIDs), pq.Array(copyIDs), pq.Array(sourceOptionIDs), pq.Array(copyOptionIDs))
+	if err != nil {
+		return 0, err
+	}
+	return len(copyIDs), nil
+}
+
+// scanIDPairs reads (source ID, copy ID) rows and closes them.
+func scanIDPairs(rows *sql.Rows) ([]int64, []int64, error) {
+	defer rows.Close()
+	sources := make([]int64, 0)
+	copies := make([]int64, 0)
+	for rows.Next() {
+		var source, copied int64
+		if err := rows.Scan(&source, &copied); err != nil {
+			return nil, nil, err
+		}
+		sources = append(sources, source)
+		copies = append(copies, copied)
+	}
+	return sources, copies, rows.Err()
+}
+
+// PublishVersion freezes the quiz's current draft into a new version and
+// makes it the version new attempts use. It returns nil when the quiz does
+// not exist. Run it inside a transaction.
+func (r *QuizAdminRepository) PublishVersion(quizID, publishedBy uint, notes *string) (*QuizVersion, error) {
+	// Locking the quiz row keeps version numbers sequential under concurrent publishes.
+	var versionNumber int
+	err := r.db.QueryRow(`
+		SELECT COALESCE((SELECT MAX(version_number) FROM quiz_versions WHERE quiz_id = $1), 0) + 1
+		FROM quiz_quizzes
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, quizID).Scan(&versionNumber)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var versionID uint
+	if err := r.db.QueryRow(`
+		INSERT INTO quiz_versions (quiz_id, version_number, notes, published_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, quizID, versionNumber, notes, publishedBy).Scan(&versionID); err != nil {
+		return nil, err
+	}
+
+	questionCount, err := freezeQuestions(r.db, versionID, `quiz_id = $2 AND version_id IS NULL`, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.db.Exec(`
+		INSERT INTO quiz_sampling_rules (quiz_id, bank_id, tag, difficulty, question_count, version_id)
+		SELECT quiz_id, bank_id, tag, difficulty, question_count, $2
+		FROM quiz_sampling_rules
+		WHERE quiz_id = $1 AND version_id IS NULL
+		ORDER BY id ASC
+	`, quizID, versionID); err != nil {
+		return nil, err
+	}
+
+	// The version gets its own copy of every bank it samples from, so editing
+	// the bank later does not change what the version can draw.
+	if _, err := freezeQuestions(r.db, versionID,
+		`version_id IS NULL AND bank_id IN (SELECT bank_id FROM quiz_sampling_rules WHERE version_id = $1)`,
+	); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.db.Exec(
+		`UPDATE quiz_versions SET question_count = $1 WHERE id = $2`, questionCount, versionID,
+	); err != nil {
+		return nil, err
+	}
+	if _, err := r.db.Exec(
+		`UPDATE quiz_quizzes SET published_version_id = $1, updated_at = NOW() WHERE id = $2`, versionID, quizID,
+	); err != nil {
+		return nil, err
+	}
+
+	return r.GetVersion(quizID, versionID)
+}
+
+// ListVersions returns the published versions of the quiz, newest first, or
+// nil when the quiz does not exist.
+func (r *QuizAdminRepository) ListVersions(quizID uint) ([]*QuizVersion, error) {
+	var exists bool
+	if err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM quiz_quizzes WHERE id = $1 AND deleted_at IS NULL)`, quizID,
+	).Scan(&exists); err != nil || !exists {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+quizVersionColumns+`
+		FROM quiz_versions v
+			JOIN quiz_quizzes q ON q.id = v.quiz_id
+		WHERE v.quiz_id = $1
+		ORDER BY v.version_number DESC
+	`, quizID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]*QuizVersion, 0)
+	for rows.Next() {
+		v, err := scanQuizVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// GetVersion returns a version of the quiz, or nil when it does not exist.
+func (r *QuizAdminRepository) GetVersion(quizID, versionID uint) (*QuizVersion, error) {
+	v, err := scanQuizVersion(r.db.QueryRow(`
+		SELECT `+quizVersionColumns+`
+		FROM quiz_versions v
+			JOIN quiz_quizzes q ON q.id = v.quiz_id
+		WHERE v.id = $1 AND v.quiz_id = $2 AND q.deleted_at IS NULL
+	`, versionID, quizID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetVersionDetail returns the quiz's own questions and the sampling rules
+// frozen into the version. The copied bank questions are not listed.
+func (r *QuizAdminRepository) GetVersionDetail(versionID uint) ([]QuizQuestion, []QuizSamplingRule, error) {
+	questions, err := listQuestionsWithAnswers(r.db, `version_id = $1 AND quiz_id IS NOT NULL`, versionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules, err := listSamplingRules(r.db, `version_id = $1`, versionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return questions, rules, nil
+}
+
+// GetVersionQuestion returns a question frozen into the version, quiz or bank
+// owned, with its answer key. It returns nil when it does not exist.
+func (r *QuizAdminRepository) GetVersionQuestion(versionID, questionID uint) (*QuizQuestion, error) {
+	questions, err := listQuestionsWithAnswers(r.db, `id = $1 AND version_id = $2`, questionID, versionID)
+	if err != nil || len(questions) == 0 {
+		return nil, err
+	}
+	return &questions[0], nil
+}
+
+// UpdateAnswerKey stores the corrected answer key of a frozen question: the
+// is_correct flags of its options and its accepted or numeric answer. The
+// rest of a published question never changes.
+func (r *QuizAdminRepository) UpdateAnswerKey(q *QuizQuestion) error {
+	if _, err := r.db.Exec(`
+		UPDATE quiz_questions
+		SET accepted_answers  = $1,
+		    numeric_answer    = $2,
+		    numeric_tolerance = $3,
+		    updated_at        = NOW()
+		WHERE id = $4 AND version_id IS NOT NULL
+	`, q.AcceptedAnswers, q.NumericAnswer, q.NumericTolerance, q.ID); err != nil {
+		return err
+	}
+
+	correctIDs := make([]int64, 0)
+	for _, o := range q.Options {
+		if o.IsCorrect {
+			correctIDs = append(correctIDs, int64(o.ID))
+		}
+	}
+	_, err := r.db.Exec(`
+		UPDATE quiz_options
+		SET is_correct = (id = ANY($1)),
+		    updated_at = NOW()
+		WHERE question_id = $2 AND is_correct <> (id = ANY($1))
+	`, pq.Array(correctIDs), q.ID)
+	return err
+}
+
+// RegradeQuestion grades the question's saved answers in completed attempts
+// again against its answer key and rescores those attempts. The question's
+// options must be loaded with their IsCorrect flags. Run it inside a
+// transaction.
+func (r *QuizAdminRepository) RegradeQuestion(q *QuizQuestion) (*QuizRegradeResult, error) {
+	answers, err := r.completedAnswers(`question_id = $1`, q.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QuizRegradeResult{Changes: make([]QuizRegradeChange, 0)}
+	for _, ans := range answers {
+		changed, err := r.regradeAnswer(q, ans)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			result.AnswersChanged++
+		}
+	}
+
+	err = r.rescoreAttempts(result,
+		`($1 = ANY(question_ids) OR id IN (SELECT attempt_id FROM quiz_answers WHERE question_id = $1))`, q.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// completedAnswers returns the saved answers matching condition that belong
+// to completed attempts.
+func (r *QuizAdminRepository) completedAnswers(condition string, args ...any) ([]*QuizAnswer, error) {
+	rows, err := r.db.Query(`
+		SELECT `+quizAnswerColumns+`
+		FROM quiz_answers
+		WHERE `+condition+`
+		  AND attempt_id IN (SELECT id FROM quiz_attempts WHERE status = 'completed')
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := make([]*QuizAnswer, 0)
+	for rows.Next() {
+		ans, err := scanQuizAnswer(rows)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, ans)
+	}
+	return answers, rows.Err()
+}
+
+// regradeAnswer grades a saved answer again and stores the result when it
+// changed. It reports whether it did.
+func (r *QuizAdminRepository) regradeAnswer(q *QuizQuestion, ans *QuizAnswer) (bool, error) {
+	before := *ans
+	if err := q.Grade(ans); err != nil {
+		return false, err
+	}
+	if ans.IsCorrect == before.IsCorrect && ans.Credit == before.Credit && ans.PointsAwarded == before.PointsAwarded {
+		return false, nil
+	}
+	_, err := r.db.Exec(
+		`UPDATE quiz_answers SET is_correct = $1, credit = $2, points_awarded = $3 WHERE id = $4`,
+		ans.IsCorrect, ans.Credit, ans.PointsAwarded, ans.ID,
+	)
+	return err == nil, err
+}
+
+// rescoreAttempts rescores the completed attempts matching condition and adds
+// the outcome to result.
+func (r *QuizAdminRepository) rescoreAttempts(result *QuizRegradeResult, condition string, args ...any) error {
+	rows, err := r.db.Query(rescoreAttemptsQuery(condition), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change QuizRegradeChange
+		var passingScore int
+		if err := rows.Scan(
+			&change.AttemptID, &change.UserID, &change.PreviousScore, &change.Score, &passingScore,
+		); err != nil {
+			return err
+		}
+		result.AttemptCount++
+
+		if change.PreviousScore != nil && change.Score != nil && *change.PreviousScore == *change.Score {
+			continue
+		}
+		change.PreviouslyPassed = change.PreviousScore != nil && *change.PreviousScore >= float64(passingScore)
+		change.Passed = change.Score != nil && *change.Score >= float64(passingScore)
+		if change.Passed != change.PreviouslyPassed {
+			result.PassStatusChanged++
+		}
+		result.ScoresChanged++
+		result.Changes = append(result.Changes, change)
+	}
+	return rows.Err()
+}
+
+// UnreferencedAttachmentPaths filters the storage paths down to those no
+// attachment row uses anymore. Published versions share files with the
+// draft, so a file may only be removed once every copy is gone.
+func (r *QuizAdminRepository) UnreferencedAttachmentPaths(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return paths, nil
+	}
+	rows, err := r.db.Query(`
+		SELECT p.path
+		FROM unnest($1::TEXT[]) AS p(path)
+		WHERE NOT EXISTS (SELECT 1 FROM quiz_attachments a WHERE a.file_path = p.path)
+	`, pq.Array(paths))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unused := make([]string, 0, len(paths))
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		unused = append(unused, path)
+	}
+	return unused, rows.Err()
+}
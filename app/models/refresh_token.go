@@ -0,0 +1,73 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/studio-senkou/lentera-cendekia-be/database/facades"
+)
+
+// RefreshToken is one link in a chain of rotated refresh tokens. All tokens
+// issued from one login share its SessionID; only the hash of the token is kept.
+type RefreshToken struct {
+	ID        uint       `json:"id"`
+	SessionID string     `json:"session_id"`
+	UserID    uint       `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ParentID  *uint      `json:"parent_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type AuthenticationRepository struct {
+	db facades.DBExecutor
+}
+
+func NewAuthenticationRepository(db facades.DBExecutor) *AuthenticationRepository {
+	return &AuthenticationRepository{db: db}
+}
+
+func (r *AuthenticationRepository) WithExecutor(executor facades.DBExecutor) *AuthenticationRepository {
+	return &AuthenticationRepository{db: executor}
+}
+
+func (r *AuthenticationRepository) CreateRefreshToken(token *RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (session_id, user_id, token_hash, parent_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(query,
+		token.SessionID, token.UserID, token.TokenHash, token.ParentID, token.ExpiresAt,
+	).Scan(&token.ID, &token.CreatedAt)
+}
+
+// GetRefreshTokenForUpdate returns the token with the hash and locks it until
+// the transaction ends, or nil when no such token was ever issued.
+func (r *AuthenticationRepository) GetRefreshTokenForUpdate(tokenHash string) (*RefreshToken, error) {
+	query := `
+		SELECT id, session_id, user_id, token_hash, parent_id, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+	var token RefreshToken
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID, &token.SessionID, &token.UserID, &token.TokenHash, &token.ParentID,
+		&token.ExpiresAt, &token.UsedAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *AuthenticationRepository) MarkRefreshTokenUsed(tokenID uint) error {
+	_, err := r.db.Exec(`UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1`, tokenID)
+	return err
+}
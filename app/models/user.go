@@ -23,7 +23,7 @@ type User struct {
 	ID              uint       `json:"id"`
 	Name            string     `json:"name"`
 	Email           string     `json:"email"`
-	Role            string     `json:"role"` // 'user', 'mentor', 'admin'
+	Role            string     `json:"role"` // name of a row in roles, e.g. 'user', 'mentor', 'admin'
 	Password        string     `json:"-"`
 	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
 	IsActive        bool       `json:"is_active"`
@@ -333,8 +333,17 @@ func (r *UserRepository) Update(user *User) (string, error) {
 	return "", nil
 }
 
+// UpdatePassword sets a new password and keeps the hash of the old one in
+// the user's password history.
 func (r *UserRepository) UpdatePassword(id uint, newPassword string) error {
-	query := `UPDATE users SET password = $1, updated_at = NOW() WHERE id = $2`
+	// Kedua statement melihat snapshot yang sama, jadi INSERT membaca password lama.
+	query := `
+		WITH previous AS (
+			INSERT INTO user_password_histories (user_id, password_hash)
+			SELECT id, password FROM users WHERE id = $2
+		)
+		UPDATE users SET password = $1, updated_at = NOW() WHERE id = $2
+	`
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
@@ -355,7 +364,48 @@ func (r *UserRepository) UpdatePassword(id uint, newPassword string) error {
 		return sql.ErrNoRows
 	}
 
-	return nil
+	_, err = r.db.Exec(`
+		DELETE FROM user_password_histories
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM user_password_histories WHERE user_id = $1
+			ORDER BY created_at DESC, id DESC LIMIT $2
+		)
+	`, id, PasswordHistoryRetained)
+	return err
+}
+
+// PasswordHistoryRetained is how many old password hashes are kept per user,
+// the most a password policy can look back.
+const PasswordHistoryRetained = 24
+
+// IsPasswordReused reports whether password is one of the user's latest
+// history passwords, the current one included.
+func (r *UserRepository) IsPasswordReused(id uint, password string, history int) (bool, error) {
+	if history <= 0 {
+		return false, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT password FROM users WHERE id = $1
+		UNION ALL
+		(SELECT password_hash FROM user_password_histories WHERE user_id = $1
+			ORDER BY created_at DESC, id DESC LIMIT $2)
+	`, id, min(history-1, PasswordHistoryRetained))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hashedPassword string
+		if err := rows.Scan(&hashedPassword); err != nil {
+			return false, err
+		}
+		if bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
 }
 
 func (r *UserRepository) VerifyOldPassword(id int, oldPassword string) (bool, error) {
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UserIdentity links a user to their account at an external login provider,
+// identified by the provider's stable subject.
+type UserIdentity struct {
+	ID          uint       `json:"id"`
+	UserID      uint       `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"-"`
+	Email       *string    `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+const userIdentityColumns = `
+	id, user_id, provider, subject, email, last_login_at, created_at
+`
+
+func scanUserIdentity(row interface{ Scan(...any) error }) (*UserIdentity, error) {
+	var i UserIdentity
+	if err := row.Scan(&i.ID, &i.UserID, &i.Provider, &i.Subject, &i.Email, &i.LastLoginAt, &i.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// GetIdentity returns the identity of the provider's subject, or nil when it
+// is not linked to any user.
+func (r *AuthenticationRepository) GetIdentity(provider, subject string) (*UserIdentity, error) {
+	query := `
+		SELECT ` + userIdentityColumns + `
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+	identity, err := scanUserIdentity(r.db.QueryRow(query, provider, subject))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+// LinkIdentity links the provider's subject to the user. It returns
+// ErrIdentityAlreadyLinked when the user already has another account at the
+// provider, or the subject belongs to another user.
+func (r *AuthenticationRepository) LinkIdentity(identity *UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT DO NOTHING
+		RETURNING id, last_login_at, created_at
+	`
+	err := r.db.QueryRow(query, identity.UserID, identity.Provider, identity.Subject, identity.Email).
+		Scan(&identity.ID, &identity.LastLoginAt, &identity.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrIdentityAlreadyLinked
+	}
+	return err
+}
+
+// TouchIdentity records a login through the identity and the email the
+// provider reported with it.
+func (r *AuthenticationRepository) TouchIdentity(id uint, email *string) error {
+	_, err := r.db.Exec(`
+		UPDATE user_identities SET email = COALESCE($2, email), last_login_at = NOW() WHERE id = $1
+	`, id, email)
+	return err
+}
+
+func (r *AuthenticationRepository) ListIdentities(userID uint) ([]UserIdentity, error) {
+	rows, err := r.db.Query(`
+		SELECT `+userIdentityColumns+`
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := make([]UserIdentity, 0)
+	for rows.Next() {
+		identity, err := scanUserIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, *identity)
+	}
+	return identities, rows.Err()
+}
+
+// UnlinkIdentity removes the user's link to the provider. It reports false
+// when there was none.
+func (r *AuthenticationRepository) UnlinkIdentity(userID uint, provider string) (bool, error) {
+	res, err := r.db.Exec(`
+		DELETE FROM user_identities WHERE user_id = $1 AND provider = $2
+	`, userID, provider)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
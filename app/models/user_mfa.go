@@ -0,0 +1,138 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UserMFA is a user's TOTP enrollment. It only protects logins once
+// EnabledAt is set, i.e. after the user confirmed a first code.
+type UserMFA struct {
+	UserID       uint       `json:"user_id"`
+	Secret       string     `json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at"`
+	LastUsedStep *int64     `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (m *UserMFA) IsEnabled() bool {
+	return m != nil && m.EnabledAt != nil
+}
+
+// GetMFA returns the user's TOTP enrollment, or nil when there is none.
+func (r *AuthenticationRepository) GetMFA(userID uint) (*UserMFA, error) {
+	query := `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at, updated_at
+		FROM user_mfa
+		WHERE user_id = $1
+	`
+	var m UserMFA
+	err := r.db.QueryRow(query, userID).Scan(
+		&m.UserID, &m.Secret, &m.EnabledAt, &m.LastUsedStep, &m.CreatedAt, &m.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// SaveMFASecret starts or restarts an enrollment with a new secret. It
+// returns ErrMFAAlreadyEnabled instead of replacing an enabled secret.
+func (r *AuthenticationRepository) SaveMFASecret(userID uint, secret string) error {
+	query := `
+		INSERT INTO user_mfa (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = NULL, updated_at = NOW()
+		WHERE user_mfa.enabled_at IS NULL
+	`
+	res, err := r.db.Exec(query, userID, secret)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+// UseTOTPStep records the time step of an accepted code. It reports false
+// when that step or a later one was already used, i.e. the code is replayed.
+func (r *AuthenticationRepository) UseTOTPStep(userID uint, step int64) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE user_mfa SET last_used_step = $2, updated_at = NOW()
+		WHERE user_id = $1 AND (last_used_step IS NULL OR last_used_step < $2)
+	`, userID, step)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// EnableMFA confirms the enrollment and replaces the recovery codes. Call it
+// inside a transaction together with UseTOTPStep.
+func (r *AuthenticationRepository) EnableMFA(userID uint, recoveryCodeHashes []string) error {
+	if _, err := r.db.Exec(`
+		UPDATE user_mfa SET enabled_at = NOW(), updated_at = NOW() WHERE user_id = $1
+	`, userID); err != nil {
+		return err
+	}
+	return r.ReplaceRecoveryCodes(userID, recoveryCodeHashes)
+}
+
+// ReplaceRecoveryCodes invalidates the user's recovery codes and stores the
+// new hashes.
+func (r *AuthenticationRepository) ReplaceRecoveryCodes(userID uint, hashes []string) error {
+	if _, err := r.db.Exec(`DELETE FROM user_mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if _, err := r.db.Exec(`
+			INSERT INTO user_mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)
+		`, userID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode consumes an unused recovery code. It reports false when
+// the hash matches none.
+func (r *AuthenticationRepository) UseRecoveryCode(userID uint, hash string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE user_mfa_recovery_codes SET used_at = NOW()
+		WHERE id = (
+			SELECT id FROM user_mfa_recovery_codes
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+			LIMIT 1
+		)
+	`, userID, hash)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// CountRecoveryCodes returns how many recovery codes the user has left.
+func (r *AuthenticationRepository) CountRecoveryCodes(userID uint) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM user_mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL
+	`, userID).Scan(&count)
+	return count, err
+}
+
+// DisableMFA removes the user's enrollment and recovery codes.
+func (r *AuthenticationRepository) DisableMFA(userID uint) error {
+	if _, err := r.db.Exec(`DELETE FROM user_mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`DELETE FROM user_mfa WHERE user_id = $1`, userID)
+	return err
+}
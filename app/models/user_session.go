@@ -0,0 +1,157 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UserSession is one login of a user on one device. Access tokens name their
+// session, so revoking it logs that device out at its next request.
+type UserSession struct {
+	ID         string     `json:"id"`
+	UserID     uint       `json:"user_id"`
+	DeviceName *string    `json:"device_name"`
+	IPAddress  *string    `json:"ip_address"`
+	UserAgent  *string    `json:"user_agent"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// sessionLastSeenInterval throttles last_seen_at writes so that not every
+// authenticated request updates the session row.
+const sessionLastSeenInterval = time.Minute
+
+const userSessionColumns = `
+	id, user_id, device_name, ip_address, user_agent, expires_at, last_seen_at, revoked_at, created_at
+`
+
+func scanUserSession(row interface{ Scan(...any) error }) (*UserSession, error) {
+	var s UserSession
+	if err := row.Scan(
+		&s.ID, &s.UserID, &s.DeviceName, &s.IPAddress, &s.UserAgent, &s.ExpiresAt, &s.LastSeenAt, &s.RevokedAt, &s.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *AuthenticationRepository) CreateSession(session *UserSession) error {
+	query := `
+		INSERT INTO user_sessions (id, user_id, device_name, ip_address, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING last_seen_at, created_at
+	`
+	return r.db.QueryRow(query,
+		session.ID, session.UserID, session.DeviceName, session.IPAddress, session.UserAgent, session.ExpiresAt,
+	).Scan(&session.LastSeenAt, &session.CreatedAt)
+}
+
+// GetActiveSession returns the user's session when it is neither revoked nor
+// expired, or nil otherwise.
+func (r *AuthenticationRepository) GetActiveSession(userID uint, sessionID string) (*UserSession, error) {
+	query := `
+		SELECT ` + userSessionColumns + `
+		FROM user_sessions
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW()
+	`
+	session, err := scanUserSession(r.db.QueryRow(query, sessionID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+// TouchSession records that the session was just used, at most once per
+// sessionLastSeenInterval.
+func (r *AuthenticationRepository) TouchSession(session *UserSession) error {
+	if time.Since(session.LastSeenAt) < sessionLastSeenInterval {
+		return nil
+	}
+	_, err := r.db.Exec(`UPDATE user_sessions SET last_seen_at = NOW() WHERE id = $1`, session.ID)
+	return err
+}
+
+// ExtendSession moves the session's expiry along with a rotated refresh token
+// and records where the refresh came from.
+func (r *AuthenticationRepository) ExtendSession(sessionID string, expiresAt time.Time, ipAddress, userAgent *string) error {
+	query := `
+		UPDATE user_sessions
+		SET expires_at   = $2,
+		    ip_address   = COALESCE($3, ip_address),
+		    user_agent   = COALESCE($4, user_agent),
+		    last_seen_at = NOW()
+		WHERE id = $1
+	`
+	_, err := r.db.Exec(query, sessionID, expiresAt, ipAddress, userAgent)
+	return err
+}
+
+// ListActiveSessions returns the user's sessions that can still be used,
+// most recently seen first.
+func (r *AuthenticationRepository) ListActiveSessions(userID uint) ([]UserSession, error) {
+	query := `
+		SELECT ` + userSessionColumns + `
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]UserSession, 0)
+	for rows.Next() {
+		session, err := scanUserSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+	return sessions, rows.Err()
+}
+
+// RevokeSession logs one of the user's devices out by revoking the session
+// and its refresh tokens. It reports false when the user has no such active
+// session.
+func (r *AuthenticationRepository) RevokeSession(userID uint, sessionID string) (bool, error) {
+	res, err := r.db.Exec(`
+		UPDATE user_sessions SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`, sessionID, userID)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE refresh_tokens SET revoked_at = NOW() WHERE session_id = $1 AND revoked_at IS NULL
+	`, sessionID)
+	return true, err
+}
+
+// RevokeUserSessions logs the user out on every device and returns how many
+// sessions were still active.
+func (r *AuthenticationRepository) RevokeUserSessions(userID uint) (int, error) {
+	res, err := r.db.Exec(`
+		UPDATE user_sessions SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+	`, userID)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+
+	_, err = r.db.Exec(`
+		UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID)
+	return int(n), err
+}
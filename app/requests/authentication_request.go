@@ -1,8 +1,9 @@
 package requests
 
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6,max=30"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required,max=72"`
+	DeviceName string `json:"device_name" validate:"omitempty,max=100"`
 }
 
 type VerifyTokenRequest struct {
@@ -20,3 +21,32 @@ type ResetPasswordRequest struct {
 type RefreshTokenRequest struct {
 	Token string `json:"token" validate:"required"`
 }
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,min=6,max=20"`
+}
+
+type DisableMFARequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,min=6,max=20"`
+}
+
+type MFALoginSetupRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+type MFALoginRequest struct {
+	MFAToken   string `json:"mfa_token" validate:"required"`
+	Code       string `json:"code" validate:"required,min=6,max=20"`
+	DeviceName string `json:"device_name" validate:"omitempty,max=100"`
+}
+
+type MFAPolicyRequest struct {
+	RequiredForAdmins *bool `json:"required_for_admins" validate:"required"`
+}
+
+type OIDCCallbackRequest struct {
+	Code       string `json:"code" validate:"required"`
+	State      string `json:"state" validate:"required"`
+	DeviceName string `json:"device_name" validate:"omitempty,max=100"`
+}
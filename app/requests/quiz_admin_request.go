@@ -1,35 +1,143 @@
 package requests
 
+import "time"
+
 type CreateQuizRequest struct {
-	Title            string  `json:"title"             validate:"required,min=3,max=255"`
-	Description      *string `json:"description"       validate:"omitempty,min=3"`
-	PassingScore     int     `json:"passing_score"     validate:"required,min=0,max=100"`
-	TimeLimitMinutes *int    `json:"time_limit_minutes" validate:"omitempty,min=1"`
-	IsActive         bool    `json:"is_active"`
+	Title                string     `json:"title"             validate:"required,min=3,max=255"`
+	Description          *string    `json:"description"       validate:"omitempty,min=3"`
+	PassingScore         int        `json:"passing_score"     validate:"required,min=0,max=100"`
+	TimeLimitMinutes     *int       `json:"time_limit_minutes" validate:"omitempty,min=1"`
+	MaxAttempts          *int       `json:"max_attempts"      validate:"omitempty,min=0"` // 0 = tanpa batas, kosong = 1
+	CooldownMinutes      int        `json:"cooldown_minutes"  validate:"omitempty,min=0"`
+	ScorePolicy          string     `json:"score_policy"      validate:"omitempty,oneof=best latest average"`
+	ReviewPolicy         string     `json:"review_policy"     validate:"omitempty,oneof=never immediately after_close"`
+	OpensAt              *time.Time `json:"opens_at"`
+	ClosesAt             *time.Time `json:"closes_at"`
+	ClassIDs             []string   `json:"class_ids"           validate:"omitempty,dive,uuid"` // kosong = belum bisa dikerjakan siswa
+	CertificateEnabled   bool       `json:"certificate_enabled"`                                // terbitkan sertifikat PDF saat siswa lulus
+	LeaderboardOptOut    bool       `json:"leaderboard_opt_out"`                                // sembunyikan papan peringkat
+	LeaderboardAnonymous bool       `json:"leaderboard_anonymous"`                              // samarkan nama peserta
+	LeaderboardPublic    bool       `json:"leaderboard_public"`                                 // buka 10 besar untuk publik
+	IsActive             bool       `json:"is_active"`
 }
 
 type UpdateQuizRequest struct {
-	Title            string  `json:"title"             validate:"required,min=3,max=255"`
-	Description      *string `json:"description"       validate:"omitempty,min=3"`
-	PassingScore     int     `json:"passing_score"     validate:"required,min=0,max=100"`
-	TimeLimitMinutes *int    `json:"time_limit_minutes" validate:"omitempty,min=1"`
-	IsActive         bool    `json:"is_active"`
+	Title                string     `json:"title"             validate:"required,min=3,max=255"`
+	Description          *string    `json:"description"       validate:"omitempty,min=3"`
+	PassingScore         int        `json:"passing_score"     validate:"required,min=0,max=100"`
+	TimeLimitMinutes     *int       `json:"time_limit_minutes" validate:"omitempty,min=1"`
+	MaxAttempts          *int       `json:"max_attempts"      validate:"omitempty,min=0"` // 0 = tanpa batas, kosong = 1
+	CooldownMinutes      int        `json:"cooldown_minutes"  validate:"omitempty,min=0"`
+	ScorePolicy          string     `json:"score_policy"      validate:"omitempty,oneof=best latest average"`
+	ReviewPolicy         string     `json:"review_policy"     validate:"omitempty,oneof=never immediately after_close"`
+	OpensAt              *time.Time `json:"opens_at"`
+	ClosesAt             *time.Time `json:"closes_at"`
+	ClassIDs             []string   `json:"class_ids"           validate:"omitempty,dive,uuid"` // kosong = belum bisa dikerjakan siswa
+	CertificateEnabled   bool       `json:"certificate_enabled"`                                // terbitkan sertifikat PDF saat siswa lulus
+	LeaderboardOptOut    bool       `json:"leaderboard_opt_out"`                                // sembunyikan papan peringkat
+	LeaderboardAnonymous bool       `json:"leaderboard_anonymous"`                              // samarkan nama peserta
+	LeaderboardPublic    bool       `json:"leaderboard_public"`                                 // buka 10 besar untuk publik
+	IsActive             bool       `json:"is_active"`
 }
 
 type CreateQuestionRequest struct {
-	QuestionText string `json:"question_text" validate:"required,min=3"`
+	QuestionText     string   `json:"question_text"     validate:"required,min=3"`
+	TextFormat       string   `json:"text_format"       validate:"omitempty,oneof=plain markdown latex"`
+	QuestionType     string   `json:"question_type"     validate:"omitempty,oneof=single_choice multiple_choice true_false short_answer numeric"`
+	ScoringMode      string   `json:"scoring_mode"      validate:"omitempty,oneof=all_or_nothing partial"`
+	MatchMode        *string  `json:"match_mode"        validate:"omitempty,oneof=exact case_insensitive regex"`
+	AcceptedAnswers  []string `json:"accepted_answers"  validate:"required_if=QuestionType short_answer,omitempty,dive,required"`
+	NumericAnswer    *float64 `json:"numeric_answer"    validate:"required_if=QuestionType numeric"`
+	NumericTolerance float64  `json:"numeric_tolerance" validate:"omitempty,min=0"`
+	CorrectAnswer    *bool    `json:"correct_answer"    validate:"required_if=QuestionType true_false"`
+	Tags             []string `json:"tags"              validate:"omitempty,dive,required,max=100"`
+	Difficulty       *string  `json:"difficulty"        validate:"omitempty,oneof=easy medium hard"`
+	Points           float64  `json:"points"            validate:"omitempty,gt=0,max=1000"`
+	PenaltyPoints    float64  `json:"penalty_points"    validate:"omitempty,min=0,max=1000"`
+	Explanation      *string  `json:"explanation"`
 }
 
 type UpdateQuestionRequest struct {
-	QuestionText string `json:"question_text" validate:"required,min=3"`
+	QuestionText     string   `json:"question_text"     validate:"required,min=3"`
+	TextFormat       string   `json:"text_format"       validate:"omitempty,oneof=plain markdown latex"`
+	QuestionType     string   `json:"question_type"     validate:"omitempty,oneof=single_choice multiple_choice true_false short_answer numeric"`
+	ScoringMode      string   `json:"scoring_mode"      validate:"omitempty,oneof=all_or_nothing partial"`
+	MatchMode        *string  `json:"match_mode"        validate:"omitempty,oneof=exact case_insensitive regex"`
+	AcceptedAnswers  []string `json:"accepted_answers"  validate:"required_if=QuestionType short_answer,omitempty,dive,required"`
+	NumericAnswer    *float64 `json:"numeric_answer"    validate:"required_if=QuestionType numeric"`
+	NumericTolerance float64  `json:"numeric_tolerance" validate:"omitempty,min=0"`
+	CorrectAnswer    *bool    `json:"correct_answer"    validate:"required_if=QuestionType true_false"`
+	Tags             []string `json:"tags"              validate:"omitempty,dive,required,max=100"`
+	Difficulty       *string  `json:"difficulty"        validate:"omitempty,oneof=easy medium hard"`
+	Points           float64  `json:"points"            validate:"omitempty,gt=0,max=1000"`
+	PenaltyPoints    float64  `json:"penalty_points"    validate:"omitempty,min=0,max=1000"`
+	Explanation      *string  `json:"explanation"`
 }
 
 type CreateOptionRequest struct {
-	OptionText string `json:"option_text" validate:"required,min=1"`
-	IsCorrect  bool   `json:"is_correct"`
+	OptionText  string  `json:"option_text" validate:"required,min=1"`
+	TextFormat  string  `json:"text_format" validate:"omitempty,oneof=plain markdown latex"`
+	IsCorrect   bool    `json:"is_correct"`
+	Explanation *string `json:"explanation"`
 }
 
 type UpdateOptionRequest struct {
-	OptionText string `json:"option_text" validate:"required,min=1"`
-	IsCorrect  bool   `json:"is_correct"`
+	OptionText  string  `json:"option_text" validate:"required,min=1"`
+	TextFormat  string  `json:"text_format" validate:"omitempty,oneof=plain markdown latex"`
+	IsCorrect   bool    `json:"is_correct"`
+	Explanation *string `json:"explanation"`
+}
+
+type CreateQuestionBankRequest struct {
+	Name        string  `json:"name"        validate:"required,min=3,max=255"`
+	Description *string `json:"description" validate:"omitempty,min=3"`
+}
+
+type UpdateQuestionBankRequest struct {
+	Name        string  `json:"name"        validate:"required,min=3,max=255"`
+	Description *string `json:"description" validate:"omitempty,min=3"`
+}
+
+type SamplingRuleItem struct {
+	BankID        uint    `json:"bank_id"        validate:"required"`
+	Tag           *string `json:"tag"            validate:"omitempty,min=1,max=100"`
+	Difficulty    *string `json:"difficulty"     validate:"omitempty,oneof=easy medium hard"`
+	QuestionCount int     `json:"question_count" validate:"required,min=1"`
+}
+
+type UpdateSamplingRulesRequest struct {
+	Rules []SamplingRuleItem `json:"rules" validate:"omitempty,dive"`
+}
+
+type ImportQuizRequest struct {
+	Format      string  `json:"format"      validate:"omitempty,oneof=json csv gift"` // kosong = dari ekstensi file
+	Title       *string `json:"title"       validate:"omitempty,min=3,max=255"`       // kosong = judul di file atau nama file
+	Description *string `json:"description" validate:"omitempty,min=3"`
+}
+
+type UploadQuizAttachmentRequest struct {
+	OptionID uint `json:"option_id"` // kosong = lampiran soal
+}
+
+type ReorderQuizAttachmentsRequest struct {
+	AttachmentIDs []int64 `json:"attachment_ids" validate:"required,min=1,dive,min=1"`
+}
+
+type PublishQuizRequest struct {
+	Notes *string `json:"notes" validate:"omitempty,max=1000"`
+}
+
+type RegradeQuizRequest struct {
+	SyncAnswerKeys bool `json:"sync_answer_keys"` // salin kunci jawaban draft ke versi yang sudah dipublikasikan
+	NotifyStudents bool `json:"notify_students"`  // kirim email ke siswa yang nilainya berubah
+}
+
+// Hanya kunci jawaban yang bisa dikoreksi pada versi yang sudah dipublikasikan.
+// Field yang wajib diisi mengikuti jenis soal.
+type CorrectAnswerKeyRequest struct {
+	CorrectOptionIDs []uint   `json:"correct_option_ids" validate:"omitempty,dive,min=1"`    // single_choice, multiple_choice
+	CorrectAnswer    *bool    `json:"correct_answer"`                                        // true_false
+	AcceptedAnswers  []string `json:"accepted_answers"   validate:"omitempty,dive,required"` // short_answer
+	NumericAnswer    *float64 `json:"numeric_answer"`                                        // numeric
+	NumericTolerance *float64 `json:"numeric_tolerance"  validate:"omitempty,min=0"`         // numeric, kosong = tidak diubah
 }
@@ -1,14 +1,44 @@
 package requests
 
+import "time"
+
 type SubmitQuizRequest struct {
-	Answers []SubmitAnswerItem `json:"answers" validate:"required,min=1,dive"`
+	Answers []SubmitAnswerItem `json:"answers" validate:"omitempty,dive"`
 }
 
+// SubmitAnswerItem carries the answer for one question. Which field is used
+// depends on the question type: option_id for single_choice and true_false,
+// option_ids for multiple_choice, text_answer for short_answer and
+// numeric_answer for numeric.
 type SubmitAnswerItem struct {
-	QuestionID uint `json:"question_id" validate:"required"`
-	OptionID   uint `json:"option_id"   validate:"required"`
+	QuestionID    uint     `json:"question_id"    validate:"required"`
+	OptionID      *uint    `json:"option_id"      validate:"required_without_all=OptionIDs TextAnswer NumericAnswer"`
+	OptionIDs     []uint   `json:"option_ids"     validate:"omitempty,dive,required"`
+	TextAnswer    *string  `json:"text_answer"    validate:"omitempty,max=1000"`
+	NumericAnswer *float64 `json:"numeric_answer"`
 }
 
 type ResetQuizAttemptRequest struct {
 	UserID uint `json:"user_id" validate:"required"`
 }
+
+// RecordQuizEventsRequest carries proctoring events the client buffered
+// during an attempt. occurred_at is the client's clock; the server keeps it
+// only when it falls inside the attempt.
+type RecordQuizEventsRequest struct {
+	Events []QuizEventItem `json:"events" validate:"required,min=1,max=50,dive"`
+}
+
+type QuizEventItem struct {
+	EventType  string         `json:"event_type"  validate:"required,oneof=window_blur fullscreen_exit copy paste tab_switch"`
+	OccurredAt *time.Time     `json:"occurred_at"`
+	Details    map[string]any `json:"details"`
+}
+
+type QuizLeaderboardRequest struct {
+	ClassID string `query:"class_id" json:"class_id" validate:"omitempty,uuid"`
+	Window  string `query:"window"   json:"window"   validate:"omitempty,oneof=day week month"` // mengabaikan from/to
+	From    string `query:"from"     json:"from"     validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To      string `query:"to"       json:"to"       validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit   int    `query:"limit"    json:"limit"    validate:"omitempty,min=1,max=100"`
+}
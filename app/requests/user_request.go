@@ -21,7 +21,7 @@ type CreateNewMentorRequest struct {
 
 type UserActivationRequest struct {
 	ActivationToken string `json:"activation_token" validate:"required"`
-	Password        string `json:"password" validate:"required,min=6,max=30"`
+	Password        string `json:"password" validate:"required,password"`
 }
 
 type PasswordResetRequest struct {
@@ -30,20 +30,20 @@ type PasswordResetRequest struct {
 
 type PasswordResetConfirmRequest struct {
 	ResetToken      string `json:"reset_token" validate:"required"`
-	NewPassword     string `json:"new_password" validate:"required,min=6,max=30"`
-	ConfirmPassword string `json:"confirm_password" validate:"required,min=6,max=30,eqfield=NewPassword"`
+	NewPassword     string `json:"new_password" validate:"required,password"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
 }
 
 type UpdatePasswordRequest struct {
 	Token           string `json:"token" validate:"required"`
-	NewPassword     string `json:"new_password" validate:"required,min=6,max=30"`
-	ConfirmPassword string `json:"confirm_password" validate:"required,min=6,max=30,eqfield=NewPassword"`
+	NewPassword     string `json:"new_password" validate:"required,password"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
 }
 
 type UpdateUserPasswordRequest struct {
-	OldPassword     string `json:"old_password" validate:"required,min=6,max=30"`
-	NewPassword     string `json:"new_password" validate:"required,min=6,max=30"`
-	ConfirmPassword string `json:"confirm_password" validate:"required,min=6,max=30,eqfield=NewPassword"`
+	OldPassword     string `json:"old_password" validate:"required,max=72"`
+	NewPassword     string `json:"new_password" validate:"required,password"`
+	ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
 }
 
 type UpdateUserRequest struct {
@@ -4,15 +4,50 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/studio-senkou/lentera-cendekia-be/app/controllers"
 	"github.com/studio-senkou/lentera-cendekia-be/app/middlewares"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/auth"
 )
 
 func SetupAuthRoutes(router fiber.Router) {
 	authController := controllers.NewAuthController()
+	loginLimiter := auth.NewLimiter("login")
+	verifyEmailLimiter := auth.NewLimiter("verify-email")
 
-	router.Post("/auth/login", authController.LoginUser)
-	router.Post("/auth/login/admin", authController.LoginAdmin)
-	router.Post("/auth/verify-email", authController.VerifyAccount)
+	router.Post("/auth/login", middlewares.ThrottleMiddleware(loginLimiter, false), authController.LoginUser)
+	router.Post("/auth/login/admin", middlewares.ThrottleMiddleware(loginLimiter, false), authController.LoginAdmin)
+	router.Post("/auth/verify-email", middlewares.ThrottleMiddleware(verifyEmailLimiter, false), authController.VerifyAccount)
 	router.Post("/auth/verify-token", authController.VerifyOneTimeToken)
 	router.Put("/auth/refresh", authController.RefreshToken)
 	router.Delete("/auth/logout", middlewares.AuthMiddleware(), authController.Logout)
+	router.Get("/auth/jwks.json", authController.JWKS)
+
+	mfaLimiter := auth.NewLimiter("mfa")
+	router.Post("/auth/login/mfa", middlewares.ThrottleMiddleware(mfaLimiter, false), authController.LoginMFA)
+	router.Post("/auth/login/mfa/setup", middlewares.ThrottleMiddleware(mfaLimiter, false), authController.SetupMFAForLogin)
+
+	oidcLimiter := auth.NewLimiter("oidc")
+	router.Get("/auth/oidc/:provider/authorize", authController.AuthorizeOIDC)
+	router.Post("/auth/oidc/:provider/callback", middlewares.ThrottleMiddleware(oidcLimiter, false), authController.OIDCCallback)
+	router.Get("/auth/identities", middlewares.AuthMiddleware(), authController.ListIdentities)
+	router.Delete("/auth/identities/:provider", middlewares.AuthMiddleware(), authController.UnlinkIdentity)
+
+	mfaEnroll := middlewares.RequirePermission(models.PermMFAEnroll)
+	router.Get("/auth/mfa", middlewares.AuthMiddleware(), mfaEnroll, authController.GetMFAStatus)
+	router.Post("/auth/mfa/setup", middlewares.AuthMiddleware(), mfaEnroll, authController.SetupMFA)
+	router.Post("/auth/mfa/enable", middlewares.AuthMiddleware(), mfaEnroll, authController.EnableMFA)
+	router.Post("/auth/mfa/recovery-codes", middlewares.AuthMiddleware(), mfaEnroll, authController.RegenerateRecoveryCodes)
+	router.Delete("/auth/mfa", middlewares.AuthMiddleware(), mfaEnroll, authController.DisableMFA)
+
+	router.Get(
+		"/admin/security/mfa-policy",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSecurityManage),
+		authController.GetMFAPolicy,
+	)
+	router.Put(
+		"/admin/security/mfa-policy",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermSecurityManage),
+		authController.UpdateMFAPolicy,
+	)
 }
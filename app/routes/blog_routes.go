@@ -4,14 +4,15 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/studio-senkou/lentera-cendekia-be/app/controllers"
 	"github.com/studio-senkou/lentera-cendekia-be/app/middlewares"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
 )
 
 func SetupBlogRoutes(router fiber.Router) {
 	blogController := controllers.NewBlogController()
 
-	router.Post("/blogs", middlewares.AuthMiddleware(), middlewares.RoleMiddleware("mentor", "admin"), blogController.CreateBlog)
+	router.Post("/blogs", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermBlogPublish), blogController.CreateBlog)
 	router.Get("/blogs", blogController.GetAllBlogs)
 	router.Get("/blogs/:id", blogController.GetBlogByID)
-	router.Put("/blogs/:id", middlewares.AuthMiddleware(), middlewares.RoleMiddleware("mentor", "admin"), blogController.UpdateBlog)
-	router.Delete("/blogs/:id", middlewares.AuthMiddleware(), middlewares.RoleMiddleware("mentor", "admin"), blogController.DeleteBlog)
+	router.Put("/blogs/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermBlogPublish), blogController.UpdateBlog)
+	router.Delete("/blogs/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermBlogPublish), blogController.DeleteBlog)
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/studio-senkou/lentera-cendekia-be/app/controllers"
 	"github.com/studio-senkou/lentera-cendekia-be/app/middlewares"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
 )
 
 func SetupMeetingSessionRoutes(router fiber.Router) {
@@ -12,13 +13,13 @@ func SetupMeetingSessionRoutes(router fiber.Router) {
 	router.Post(
 		"/meeting-sessions",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin", "mentor"),
+		middlewares.RequirePermission(models.PermSessionCreate),
 		meetingSessionController.CreateMeetingSession,
 	)
 	router.Post(
 		"/meeting-sessions/bulk",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin", "mentor"),
+		middlewares.RequirePermission(models.PermSessionCreate),
 		meetingSessionController.BulkCreateMeetingSessions,
 	)
 	// router.Post("/meeting-sessions/:id/student-attend", middlewares.AuthMiddleware(), middlewares.RoleMiddleware("user"), meetingSessionController.UserAttend)
@@ -26,7 +27,7 @@ func SetupMeetingSessionRoutes(router fiber.Router) {
 	router.Get(
 		"/meeting-sessions",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin", "mentor"),
+		middlewares.RequirePermission(models.PermSessionView),
 		meetingSessionController.GetMeetingSessions,
 	)
 	// router.Get("/meeting-sessions/me", middlewares.AuthMiddleware(), meetingSessionController.GetUserMeetingSession)
@@ -39,13 +40,13 @@ func SetupMeetingSessionRoutes(router fiber.Router) {
 		// "/meeting-sessions/:id",
 		"/meeting-sessions/bulk",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin", "mentor"),
+		middlewares.RequirePermission(models.PermSessionManage),
 		meetingSessionController.UpdateMeetingSession,
 	)
 	router.Delete(
 		"/meeting-sessions/:id",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin", "mentor"),
+		middlewares.RequirePermission(models.PermSessionManage),
 		meetingSessionController.DeleteMeetingSession,
 	)
 	// router.Patch("/meeting-sessions/:id/:status", middlewares.AuthMiddleware(), meetingSessionController.UpdateMeetingSessionStatus)
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/studio-senkou/lentera-cendekia-be/app/controllers"
 	"github.com/studio-senkou/lentera-cendekia-be/app/middlewares"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
 )
 
 func SetupQuizAdminRoutes(router fiber.Router) {
@@ -11,14 +12,23 @@ func SetupQuizAdminRoutes(router fiber.Router) {
 
 	admin := router.Group("/admin/quizzes",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermQuizManage),
 	)
 
 	admin.Get("", ac.ListQuizzes)
 	admin.Post("", ac.CreateQuiz)
+	admin.Post("/import", ac.ImportQuiz)
 	admin.Get("/:id", ac.GetQuiz)
 	admin.Put("/:id", ac.UpdateQuiz)
 	admin.Delete("/:id", ac.DeleteQuiz)
+	admin.Get("/:id/export", ac.ExportQuiz)
+	admin.Get("/:id/leaderboard", ac.GetLeaderboard)
+
+	admin.Post("/:id/publish", ac.PublishQuiz)
+	admin.Get("/:id/versions", ac.ListVersions)
+	admin.Get("/:id/versions/:vid", ac.GetVersion)
+	admin.Put("/:id/versions/:vid/questions/:qid/answer-key", ac.CorrectAnswerKey)
+	admin.Post("/:id/regrade", ac.RegradeQuiz)
 
 	admin.Post("/:id/questions", ac.CreateQuestion)
 	admin.Put("/:id/questions/:qid", ac.UpdateQuestion)
@@ -28,5 +38,37 @@ func SetupQuizAdminRoutes(router fiber.Router) {
 	admin.Put("/:id/questions/:qid/options/:oid", ac.UpdateOption)
 	admin.Delete("/:id/questions/:qid/options/:oid", ac.DeleteOption)
 
+	admin.Post("/:id/questions/:qid/attachments", ac.UploadAttachment)
+	admin.Put("/:id/questions/:qid/attachments/order", ac.ReorderAttachments)
+	admin.Delete("/:id/questions/:qid/attachments/:aid", ac.DeleteAttachment)
+
 	admin.Get("/:id/attempts", ac.ListAttempts)
+	admin.Get("/:id/attempts/:aid/events", ac.ListAttemptEvents)
+	admin.Get("/:id/analysis", ac.GetQuizAnalysis)
+
+	admin.Get("/:id/sampling-rules", ac.GetSamplingRules)
+	admin.Put("/:id/sampling-rules", ac.UpdateSamplingRules)
+
+	banks := router.Group("/admin/question-banks",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermQuizManage),
+	)
+
+	banks.Get("", ac.ListBanks)
+	banks.Post("", ac.CreateBank)
+	banks.Get("/:bid", ac.GetBank)
+	banks.Put("/:bid", ac.UpdateBank)
+	banks.Delete("/:bid", ac.DeleteBank)
+
+	banks.Post("/:bid/questions", ac.CreateQuestion)
+	banks.Put("/:bid/questions/:qid", ac.UpdateQuestion)
+	banks.Delete("/:bid/questions/:qid", ac.DeleteQuestion)
+
+	banks.Post("/:bid/questions/:qid/options", ac.CreateOption)
+	banks.Put("/:bid/questions/:qid/options/:oid", ac.UpdateOption)
+	banks.Delete("/:bid/questions/:qid/options/:oid", ac.DeleteOption)
+
+	banks.Post("/:bid/questions/:qid/attachments", ac.UploadAttachment)
+	banks.Put("/:bid/questions/:qid/attachments/order", ac.ReorderAttachments)
+	banks.Delete("/:bid/questions/:qid/attachments/:aid", ac.DeleteAttachment)
 }
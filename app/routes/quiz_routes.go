@@ -4,17 +4,42 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/studio-senkou/lentera-cendekia-be/app/controllers"
 	"github.com/studio-senkou/lentera-cendekia-be/app/middlewares"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
 )
 
 func SetupQuizRoutes(router fiber.Router) {
 	quizController := controllers.NewQuizController()
 
+	router.Get(
+		"/quiz",
+		middlewares.AuthMiddleware(),
+		quizController.ListQuizzes,
+	)
+
 	router.Get(
 		"/quiz/histories",
 		middlewares.AuthMiddleware(),
 		quizController.GetStudentQuizHistories,
 	)
 
+	router.Get(
+		"/quiz/attempts/:aid/review",
+		middlewares.AuthMiddleware(),
+		quizController.ReviewAttempt,
+	)
+
+	// Publik: pihak ketiga memverifikasi sertifikat tanpa login.
+	router.Get(
+		"/quiz/certificates/:code",
+		quizController.VerifyCertificate,
+	)
+
+	// Publik: 10 besar kuis yang membuka papan peringkatnya, misalnya event tryout.
+	router.Get(
+		"/quiz/leaderboards/:code",
+		quizController.GetPublicLeaderboard,
+	)
+
 	router.Get(
 		"/quiz/code/:code",
 		middlewares.AuthMiddleware(),
@@ -27,12 +52,42 @@ func SetupQuizRoutes(router fiber.Router) {
 		quizController.GetQuiz,
 	)
 
+	router.Get(
+		"/quiz/:id/leaderboard",
+		middlewares.AuthMiddleware(),
+		quizController.GetLeaderboard,
+	)
+
+	router.Post(
+		"/quiz/:id/attempts",
+		middlewares.AuthMiddleware(),
+		quizController.StartAttempt,
+	)
+
+	router.Put(
+		"/quiz/:id/answers",
+		middlewares.AuthMiddleware(),
+		quizController.SaveAnswer,
+	)
+
+	router.Delete(
+		"/quiz/:id/answers/:qid",
+		middlewares.AuthMiddleware(),
+		quizController.ClearAnswer,
+	)
+
 	router.Post(
 		"/quiz/:id/submit",
 		middlewares.AuthMiddleware(),
 		quizController.SubmitQuiz,
 	)
 
+	router.Post(
+		"/quiz/:id/events",
+		middlewares.AuthMiddleware(),
+		quizController.RecordEvents,
+	)
+
 	router.Get(
 		"/quiz/:id/status",
 		middlewares.AuthMiddleware(),
@@ -60,7 +115,7 @@ func SetupQuizRoutes(router fiber.Router) {
 	router.Post(
 		"/quiz/:id/reset",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermQuizManage),
 		quizController.ResetAttempt,
 	)
 }
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/controllers"
+	"github.com/studio-senkou/lentera-cendekia-be/app/middlewares"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+)
+
+func SetupRoleRoutes(router fiber.Router) {
+	rc := controllers.NewRoleController()
+
+	router.Get(
+		"/admin/permissions",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermRoleManage),
+		rc.ListPermissions,
+	)
+
+	roles := router.Group("/admin/roles",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermRoleManage),
+	)
+
+	roles.Get("", rc.ListRoles)
+	roles.Post("", rc.CreateRole)
+	roles.Get("/:name", rc.GetRole)
+	roles.Put("/:name", rc.UpdateRole)
+	roles.Delete("/:name", rc.DeleteRole)
+
+	router.Put(
+		"/users/:id/role",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermRoleManage),
+		rc.AssignUserRole,
+	)
+}
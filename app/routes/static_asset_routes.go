@@ -4,13 +4,14 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/studio-senkou/lentera-cendekia-be/app/controllers"
 	"github.com/studio-senkou/lentera-cendekia-be/app/middlewares"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
 )
 
 func SetupStaticAssetRoutes(router fiber.Router) {
 	staticAssetController := controllers.NewStaticAssetController()
 
-	router.Post("/static-assets", middlewares.AuthMiddleware(), middlewares.RoleMiddleware("admin"), staticAssetController.CreateStaticAsset)
+	router.Post("/static-assets", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermStaticAssetManage), staticAssetController.CreateStaticAsset)
 	router.Get("/static-assets", staticAssetController.GetAllStaticAssets)
 	router.Get("/static-assets/:id", staticAssetController.GetStaticAssetByID)
-	router.Delete("/static-assets/:id", middlewares.AuthMiddleware(), middlewares.RoleMiddleware("admin"), staticAssetController.DeleteStaticAsset)
+	router.Delete("/static-assets/:id", middlewares.AuthMiddleware(), middlewares.RequirePermission(models.PermStaticAssetManage), staticAssetController.DeleteStaticAsset)
 }
\ No newline at end of file
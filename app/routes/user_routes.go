@@ -4,6 +4,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/studio-senkou/lentera-cendekia-be/app/controllers"
 	"github.com/studio-senkou/lentera-cendekia-be/app/middlewares"
+	"github.com/studio-senkou/lentera-cendekia-be/app/models"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/auth"
 )
 
 func SetupUserRoutes(router fiber.Router) {
@@ -12,13 +14,13 @@ func SetupUserRoutes(router fiber.Router) {
 	router.Post(
 		"/users",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserManage),
 		userController.CreateNewStudent,
 	)
 	router.Post(
 		"/users/mentors",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserManage),
 		userController.CreateNewMentor,
 	)
 
@@ -26,11 +28,35 @@ func SetupUserRoutes(router fiber.Router) {
 	router.Post(
 		"/users/:id/force-activate",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserManage),
 		userController.ForceActivateUser,
 	)
 
-	router.Post("/users/reset-password", userController.ResetPassword)
+	router.Post(
+		"/users/:id/force-logout",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermUserManage),
+		userController.ForceLogoutUser,
+	)
+
+	router.Post(
+		"/users/:id/unlock",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermUserManage),
+		userController.UnlockUser,
+	)
+	router.Delete(
+		"/users/:id/mfa",
+		middlewares.AuthMiddleware(),
+		middlewares.RequirePermission(models.PermUserManage),
+		userController.ResetUserMFA,
+	)
+
+	router.Post(
+		"/users/reset-password",
+		middlewares.ThrottleMiddleware(auth.NewLimiter("reset-password"), true),
+		userController.ResetPassword,
+	)
 	router.Put("/users/update-password", userController.UpdatePasswordByToken)
 	router.Put(
 		"/users/me/update-password",
@@ -41,32 +67,34 @@ func SetupUserRoutes(router fiber.Router) {
 	router.Get(
 		"/users",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserView),
 		userController.GetAllUsers,
 	)
 	router.Get(
 		"/users/students/dropdown",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserView),
 		userController.GetUserAsDropdown,
 	)
 	router.Get(
 		"/users/mentors/dropdown",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserView),
 		userController.GetMentorDropdown,
 	)
 	router.Get("/users/me", middlewares.AuthMiddleware(), userController.GetUserMe)
+	router.Get("/users/me/sessions", middlewares.AuthMiddleware(), userController.ListMySessions)
+	router.Delete("/users/me/sessions/:id", middlewares.AuthMiddleware(), userController.RevokeMySession)
 	router.Get(
 		"/users/:id",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserView),
 		userController.GetUser,
 	)
 	router.Get(
 		"/active-user",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserView),
 		userController.GetActiveUser,
 	)
 
@@ -74,14 +102,14 @@ func SetupUserRoutes(router fiber.Router) {
 	router.Put(
 		"/users/:id",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserManage),
 		userController.UpdateUser,
 	)
 
 	router.Delete(
 		"/users/:id",
 		middlewares.AuthMiddleware(),
-		middlewares.RoleMiddleware("admin"),
+		middlewares.RequirePermission(models.PermUserManage),
 		userController.DeleteUser,
 	)
 }
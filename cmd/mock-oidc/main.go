@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/studio-senkou/lentera-cendekia-be/utils/oidc/oidctest"
+)
+
+// A local OpenID Connect provider for trying the social login without a
+// Google client. Run it and point the Google settings in .env at it:
+//
+//	go run ./cmd/mock-oidc -email student@example.com
+//
+//	GOOGLE_OIDC_ISSUER=http://localhost:9100
+//	GOOGLE_OIDC_CLIENT_ID=lentera-cendekia
+//	GOOGLE_OIDC_CLIENT_SECRET=secret
+//
+// Every login returns the given account; add login_hint=<email> to the
+// authorization URL to log in as someone else.
+func main() {
+	addr := flag.String("addr", "localhost:9100", "address to listen on")
+	issuer := flag.String("issuer", "", "issuer URL, http://<addr> by default")
+	clientID := flag.String("client-id", "lentera-cendekia", "expected client ID")
+	clientSecret := flag.String("client-secret", "secret", "expected client secret")
+	email := flag.String("email", "student@example.com", "email of the account that logs in")
+	name := flag.String("name", "Mock Student", "name of the account that logs in")
+	flag.Parse()
+
+	if *issuer == "" {
+		*issuer = "http://" + *addr
+	}
+
+	provider, err := oidctest.NewProvider(*issuer, *clientID, *clientSecret, oidctest.User{
+		Subject:       "mock|" + *email,
+		Email:         *email,
+		EmailVerified: true,
+		Name:          *name,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create mock OIDC provider: %v", err))
+	}
+
+	fmt.Printf("Mock OIDC provider for client %q listening on %s\n", *clientID, *issuer)
+	if err := http.ListenAndServe(*addr, provider); err != nil {
+		panic(err)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/app/jobs"
 	"github.com/studio-senkou/lentera-cendekia-be/app/routes"
 	"github.com/studio-senkou/lentera-cendekia-be/config"
 	"github.com/studio-senkou/lentera-cendekia-be/database"
 	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
 	"github.com/studio-senkou/lentera-cendekia-be/utils/cache"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/queue"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
 )
 
 type Application interface {
@@ -34,6 +37,26 @@ func (a *application) Run() error {
 	}
 	defer cache.CloseRedis()
 
+	if err := validator.InitPasswordPolicy(); err != nil {
+		return fmt.Errorf("failed to load password policy: %w", err)
+	}
+
+	if queue.LoadWorkerConfigFromEnv().Enabled {
+		queueService, err := queue.NewQueueService(queue.LoadConfigFromEnv())
+		if err != nil {
+			return fmt.Errorf("failed to initialize queue: %w", err)
+		}
+
+		if err := jobs.Register(queueService); err != nil {
+			return fmt.Errorf("failed to register background jobs: %w", err)
+		}
+
+		if err := queueService.StartWorker(); err != nil {
+			return fmt.Errorf("failed to start queue worker: %w", err)
+		}
+		defer queueService.Stop()
+	}
+
 	fiberApp := fiber.New(*config.NewFiberConfig())
 
 	fiberApp.Use(config.NewLoggerConfig())
@@ -49,6 +72,7 @@ func (a *application) Run() error {
 	routes.SetupBlogRoutes(router)
 	routes.SetupQuizRoutes(router)
 	routes.SetupQuizAdminRoutes(router)
+	routes.SetupRoleRoutes(router)
 
 	fiberApp.Get("/", func(c *fiber.Ctx) error {
 		return c.SendString("Welcome to Lentera Cendekia API")
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
 )
 
 func NewFiberConfig() *fiber.Config {
@@ -11,6 +14,10 @@ func NewFiberConfig() *fiber.Config {
 		CaseSensitive:           true,
 		StrictRouting:           true,
 		EnableTrustedProxyCheck: true,
+		// Nginx passes the client address in X-Real-IP; it is only read when
+		// the request comes from one of the trusted proxies.
+		ProxyHeader:    "X-Real-IP",
+		TrustedProxies: strings.Split(app.GetEnv("TRUSTED_PROXIES", "127.0.0.1,::1"), ","),
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			message := "Internal Server Error"
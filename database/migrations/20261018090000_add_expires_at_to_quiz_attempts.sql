@@ -0,0 +1,23 @@
+-- migrate:up
+-- Batas waktu pengerjaan dihitung di server saat attempt dibuat
+-- (started_at + time_limit_minutes). NULL = kuis tanpa batas waktu.
+ALTER TABLE quiz_attempts ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+-- TRUE jika attempt difinalisasi otomatis oleh sistem karena waktu habis
+ALTER TABLE quiz_attempts ADD COLUMN IF NOT EXISTS auto_submitted BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Isi deadline untuk attempt yang masih berjalan
+UPDATE quiz_attempts a
+SET expires_at = a.started_at + make_interval(mins => q.time_limit_minutes)
+FROM quiz_quizzes q
+WHERE a.quiz_id = q.id
+  AND a.status = 'in_progress'
+  AND q.time_limit_minutes IS NOT NULL;
+
+-- Index untuk job finalisasi attempt yang kedaluwarsa
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_status_expires_at ON quiz_attempts(status, expires_at);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_quiz_attempts_status_expires_at;
+ALTER TABLE quiz_attempts DROP COLUMN IF EXISTS auto_submitted;
+ALTER TABLE quiz_attempts DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,44 @@
+-- migrate:up
+-- Jenis soal:
+--   'single_choice'   : satu jawaban benar (default, perilaku lama)
+--   'multiple_choice' : beberapa jawaban benar, dinilai all_or_nothing atau partial
+--   'true_false'      : dua pilihan True/False
+--   'short_answer'    : jawaban teks, dicocokkan dengan accepted_answers
+--   'numeric'         : jawaban angka dengan toleransi
+ALTER TABLE quiz_questions
+    ADD COLUMN IF NOT EXISTS question_type VARCHAR(20) NOT NULL DEFAULT 'single_choice',
+    ADD COLUMN IF NOT EXISTS scoring_mode VARCHAR(20) NOT NULL DEFAULT 'all_or_nothing',
+    ADD COLUMN IF NOT EXISTS match_mode VARCHAR(20),              -- 'exact', 'case_insensitive', 'regex'
+    ADD COLUMN IF NOT EXISTS accepted_answers TEXT[],
+    ADD COLUMN IF NOT EXISTS numeric_answer NUMERIC,
+    ADD COLUMN IF NOT EXISTS numeric_tolerance NUMERIC NOT NULL DEFAULT 0;
+
+-- Jawaban tidak selalu berupa satu option
+ALTER TABLE quiz_answers ALTER COLUMN option_id DROP NOT NULL;
+
+ALTER TABLE quiz_answers
+    ADD COLUMN IF NOT EXISTS option_ids INTEGER[],                -- pilihan untuk multiple_choice
+    ADD COLUMN IF NOT EXISTS text_answer TEXT,                    -- jawaban short_answer
+    ADD COLUMN IF NOT EXISTS numeric_answer NUMERIC,              -- jawaban numeric
+    ADD COLUMN IF NOT EXISTS credit NUMERIC(5, 4) NOT NULL DEFAULT 0; -- nilai 0..1 per soal
+
+UPDATE quiz_answers SET credit = CASE WHEN is_correct THEN 1 ELSE 0 END;
+
+-- migrate:down
+DELETE FROM quiz_answers WHERE option_id IS NULL;
+
+ALTER TABLE quiz_answers
+    DROP COLUMN IF EXISTS credit,
+    DROP COLUMN IF EXISTS numeric_answer,
+    DROP COLUMN IF EXISTS text_answer,
+    DROP COLUMN IF EXISTS option_ids;
+
+ALTER TABLE quiz_answers ALTER COLUMN option_id SET NOT NULL;
+
+ALTER TABLE quiz_questions
+    DROP COLUMN IF EXISTS numeric_tolerance,
+    DROP COLUMN IF EXISTS numeric_answer,
+    DROP COLUMN IF EXISTS accepted_answers,
+    DROP COLUMN IF EXISTS match_mode,
+    DROP COLUMN IF EXISTS scoring_mode,
+    DROP COLUMN IF EXISTS question_type;
@@ -0,0 +1,31 @@
+-- migrate:up
+-- Aturan pengerjaan ulang per kuis:
+--   max_attempts     : jumlah attempt yang boleh dipakai, NULL = tanpa batas
+--   cooldown_minutes : jeda minimal setelah submit sebelum attempt berikutnya
+--   score_policy     : nilai yang dihitung, 'best', 'latest' atau 'average'
+-- Default mempertahankan perilaku lama: satu attempt, tanpa jeda.
+ALTER TABLE quiz_quizzes
+    ADD COLUMN IF NOT EXISTS max_attempts INTEGER DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS cooldown_minutes INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS score_policy VARCHAR(10) NOT NULL DEFAULT 'latest';
+
+ALTER TABLE quiz_quizzes
+    ADD CONSTRAINT chk_quiz_quizzes_max_attempts CHECK (max_attempts IS NULL OR max_attempts >= 1),
+    ADD CONSTRAINT chk_quiz_quizzes_cooldown_minutes CHECK (cooldown_minutes >= 0),
+    ADD CONSTRAINT chk_quiz_quizzes_score_policy CHECK (score_policy IN ('best', 'latest', 'average'));
+
+-- Index untuk menghitung attempt per user per kuis
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_user_quiz_status ON quiz_attempts(user_id, quiz_id, status);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_quiz_attempts_user_quiz_status;
+
+ALTER TABLE quiz_quizzes
+    DROP CONSTRAINT IF EXISTS chk_quiz_quizzes_score_policy,
+    DROP CONSTRAINT IF EXISTS chk_quiz_quizzes_cooldown_minutes,
+    DROP CONSTRAINT IF EXISTS chk_quiz_quizzes_max_attempts;
+
+ALTER TABLE quiz_quizzes
+    DROP COLUMN IF EXISTS score_policy,
+    DROP COLUMN IF EXISTS cooldown_minutes,
+    DROP COLUMN IF EXISTS max_attempts;
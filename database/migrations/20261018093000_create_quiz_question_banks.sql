@@ -0,0 +1,101 @@
+-- migrate:up
+-- Bank soal yang bisa dipakai ulang oleh banyak kuis
+CREATE TABLE IF NOT EXISTS quiz_question_banks (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Soal dimiliki oleh tepat satu kuis atau satu bank soal
+ALTER TABLE quiz_questions ALTER COLUMN quiz_id DROP NOT NULL;
+
+ALTER TABLE quiz_questions
+    ADD COLUMN IF NOT EXISTS bank_id INTEGER,
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}',   -- contoh: {aljabar, geometri}
+    ADD COLUMN IF NOT EXISTS difficulty VARCHAR(10);             -- 'easy', 'medium', 'hard'
+
+-- Aturan pengambilan soal acak per kuis. Satu baris = ambil question_count soal
+-- dari bank_id, opsional difilter tag dan/atau difficulty (stratified sampling).
+CREATE TABLE IF NOT EXISTS quiz_sampling_rules (
+    id SERIAL PRIMARY KEY,
+    quiz_id INTEGER NOT NULL,
+    bank_id INTEGER NOT NULL,
+    tag VARCHAR(100),
+    difficulty VARCHAR(10),
+    question_count INTEGER NOT NULL CHECK (question_count > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_questions_bank_id'
+        ) THEN
+            ALTER TABLE quiz_questions
+            ADD CONSTRAINT fk_quiz_questions_bank_id
+            FOREIGN KEY (bank_id) REFERENCES quiz_question_banks(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'chk_quiz_questions_owner'
+        ) THEN
+            ALTER TABLE quiz_questions
+            ADD CONSTRAINT chk_quiz_questions_owner
+            CHECK ((quiz_id IS NULL) <> (bank_id IS NULL));
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_sampling_rules_quiz_id'
+        ) THEN
+            ALTER TABLE quiz_sampling_rules
+            ADD CONSTRAINT fk_quiz_sampling_rules_quiz_id
+            FOREIGN KEY (quiz_id) REFERENCES quiz_quizzes(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_sampling_rules_bank_id'
+        ) THEN
+            ALTER TABLE quiz_sampling_rules
+            ADD CONSTRAINT fk_quiz_sampling_rules_bank_id
+            FOREIGN KEY (bank_id) REFERENCES quiz_question_banks(id)
+            ON DELETE CASCADE;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_bank_id ON quiz_questions(bank_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_tags ON quiz_questions USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_quiz_sampling_rules_quiz_id ON quiz_sampling_rules(quiz_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_quiz_sampling_rules_quiz_id;
+DROP INDEX IF EXISTS idx_quiz_questions_tags;
+DROP INDEX IF EXISTS idx_quiz_questions_bank_id;
+
+DROP TABLE IF EXISTS quiz_sampling_rules;
+
+DELETE FROM quiz_questions WHERE quiz_id IS NULL;
+
+ALTER TABLE quiz_questions
+    DROP CONSTRAINT IF EXISTS chk_quiz_questions_owner,
+    DROP CONSTRAINT IF EXISTS fk_quiz_questions_bank_id;
+
+ALTER TABLE quiz_questions
+    DROP COLUMN IF EXISTS difficulty,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS bank_id;
+
+ALTER TABLE quiz_questions ALTER COLUMN quiz_id SET NOT NULL;
+
+DROP TABLE IF EXISTS quiz_question_banks;
@@ -0,0 +1,41 @@
+-- migrate:up
+-- Bobot nilai per soal dan pengurangan nilai untuk jawaban salah.
+-- Soal yang tidak dijawab bernilai 0 (tidak kena penalti).
+ALTER TABLE quiz_questions
+    ADD COLUMN IF NOT EXISTS points NUMERIC(6, 2) NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS penalty_points NUMERIC(6, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE quiz_questions
+    ADD CONSTRAINT chk_quiz_questions_points CHECK (points > 0),
+    ADD CONSTRAINT chk_quiz_questions_penalty_points CHECK (penalty_points >= 0);
+
+-- Poin yang didapat per jawaban, bisa negatif jika kena penalti
+ALTER TABLE quiz_answers ADD COLUMN IF NOT EXISTS points_awarded NUMERIC(8, 2) NOT NULL DEFAULT 0;
+
+UPDATE quiz_answers SET points_awarded = credit;
+
+-- raw_score = total poin, max_score = total bobot soal pada attempt.
+-- score tetap nilai ternormalisasi 0..100 yang dipakai untuk passing_score.
+ALTER TABLE quiz_attempts
+    ADD COLUMN IF NOT EXISTS raw_score NUMERIC(10, 2),
+    ADD COLUMN IF NOT EXISTS max_score NUMERIC(10, 2);
+
+UPDATE quiz_attempts a
+SET raw_score = COALESCE((SELECT SUM(ans.credit) FROM quiz_answers ans WHERE ans.attempt_id = a.id), 0),
+    max_score = COALESCE(array_length(a.question_ids, 1), 0)
+WHERE a.status = 'completed';
+
+-- migrate:down
+ALTER TABLE quiz_attempts
+    DROP COLUMN IF EXISTS max_score,
+    DROP COLUMN IF EXISTS raw_score;
+
+ALTER TABLE quiz_answers DROP COLUMN IF EXISTS points_awarded;
+
+ALTER TABLE quiz_questions
+    DROP CONSTRAINT IF EXISTS chk_quiz_questions_penalty_points,
+    DROP CONSTRAINT IF EXISTS chk_quiz_questions_points;
+
+ALTER TABLE quiz_questions
+    DROP COLUMN IF EXISTS penalty_points,
+    DROP COLUMN IF EXISTS points;
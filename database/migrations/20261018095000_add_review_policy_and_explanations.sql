@@ -0,0 +1,18 @@
+-- migrate:up
+-- Pembahasan soal dan opsi yang ditampilkan saat siswa meninjau jawabannya
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS explanation TEXT;
+ALTER TABLE quiz_options ADD COLUMN IF NOT EXISTS explanation TEXT;
+
+-- Kapan siswa boleh meninjau jawaban: never, immediately, after_close.
+-- Default never agar kuis yang sudah ada tidak tiba-tiba membuka kunci jawaban.
+ALTER TABLE quiz_quizzes ADD COLUMN IF NOT EXISTS review_policy VARCHAR(20) NOT NULL DEFAULT 'never';
+
+ALTER TABLE quiz_quizzes
+    ADD CONSTRAINT chk_quiz_quizzes_review_policy CHECK (review_policy IN ('never', 'immediately', 'after_close'));
+
+-- migrate:down
+ALTER TABLE quiz_quizzes DROP CONSTRAINT IF EXISTS chk_quiz_quizzes_review_policy;
+ALTER TABLE quiz_quizzes DROP COLUMN IF EXISTS review_policy;
+
+ALTER TABLE quiz_options DROP COLUMN IF EXISTS explanation;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS explanation;
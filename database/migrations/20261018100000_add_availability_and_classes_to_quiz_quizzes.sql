@@ -0,0 +1,31 @@
+-- migrate:up
+-- Jendela waktu pengerjaan kuis. NULL berarti tidak dibatasi.
+ALTER TABLE quiz_quizzes
+    ADD COLUMN IF NOT EXISTS opens_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS closes_at TIMESTAMP;
+
+ALTER TABLE quiz_quizzes
+    ADD CONSTRAINT chk_quiz_quizzes_window CHECK (opens_at IS NULL OR closes_at IS NULL OR closes_at > opens_at);
+
+-- Kelas yang boleh mengerjakan kuis. Kuis tanpa kelas tidak bisa dikerjakan siswa.
+CREATE TABLE IF NOT EXISTS quiz_class_assignments (
+    quiz_id INTEGER NOT NULL,
+    class_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (quiz_id, class_id)
+);
+
+ALTER TABLE quiz_class_assignments
+    ADD CONSTRAINT fk_quiz_class_assignments_quiz FOREIGN KEY (quiz_id) REFERENCES quiz_quizzes(id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_quiz_class_assignments_class FOREIGN KEY (class_id) REFERENCES classes(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_class_assignments_class_id ON quiz_class_assignments(class_id);
+
+-- migrate:down
+DROP TABLE IF EXISTS quiz_class_assignments;
+
+ALTER TABLE quiz_quizzes DROP CONSTRAINT IF EXISTS chk_quiz_quizzes_window;
+
+ALTER TABLE quiz_quizzes
+    DROP COLUMN IF EXISTS closes_at,
+    DROP COLUMN IF EXISTS opens_at;
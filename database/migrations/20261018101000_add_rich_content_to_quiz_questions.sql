@@ -0,0 +1,61 @@
+-- migrate:up
+-- Format teks soal dan opsi: 'plain', 'markdown' atau 'latex'.
+-- Frontend yang merender teksnya, backend hanya menyimpan penandanya.
+ALTER TABLE quiz_questions ADD COLUMN IF NOT EXISTS text_format VARCHAR(10) NOT NULL DEFAULT 'plain';
+ALTER TABLE quiz_options ADD COLUMN IF NOT EXISTS text_format VARCHAR(10) NOT NULL DEFAULT 'plain';
+
+ALTER TABLE quiz_questions
+    ADD CONSTRAINT chk_quiz_questions_text_format CHECK (text_format IN ('plain', 'markdown', 'latex'));
+ALTER TABLE quiz_options
+    ADD CONSTRAINT chk_quiz_options_text_format CHECK (text_format IN ('plain', 'markdown', 'latex'));
+
+-- Lampiran gambar/audio milik soal, atau milik salah satu opsinya jika option_id terisi
+CREATE TABLE IF NOT EXISTS quiz_attachments (
+    id SERIAL PRIMARY KEY,
+    question_id INTEGER NOT NULL,
+    option_id INTEGER,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('image', 'audio')),
+    file_path TEXT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_attachments_question_id'
+        ) THEN
+            ALTER TABLE quiz_attachments
+            ADD CONSTRAINT fk_quiz_attachments_question_id
+            FOREIGN KEY (question_id) REFERENCES quiz_questions(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_attachments_option_id'
+        ) THEN
+            ALTER TABLE quiz_attachments
+            ADD CONSTRAINT fk_quiz_attachments_option_id
+            FOREIGN KEY (option_id) REFERENCES quiz_options(id)
+            ON DELETE CASCADE;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attachments_question_id ON quiz_attachments(question_id, sort_order);
+
+-- migrate:down
+DROP TABLE IF EXISTS quiz_attachments;
+
+ALTER TABLE quiz_options DROP CONSTRAINT IF EXISTS chk_quiz_options_text_format;
+ALTER TABLE quiz_questions DROP CONSTRAINT IF EXISTS chk_quiz_questions_text_format;
+
+ALTER TABLE quiz_options DROP COLUMN IF EXISTS text_format;
+ALTER TABLE quiz_questions DROP COLUMN IF EXISTS text_format;
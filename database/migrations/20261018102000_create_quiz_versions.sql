@@ -0,0 +1,263 @@
+-- migrate:up
+-- Versi kuis yang sudah dipublikasikan. Setiap publish menyalin soal draft,
+-- opsi, lampiran, aturan sampling dan isi bank soal yang dipakai menjadi baris
+-- beku (version_id terisi) yang tidak pernah diubah lagi. Attempt menunjuk ke
+-- satu versi sehingga edit soal berikutnya tidak mengubah riwayat pengerjaan.
+CREATE TABLE IF NOT EXISTS quiz_versions (
+    id SERIAL PRIMARY KEY,
+    quiz_id INTEGER NOT NULL,
+    version_number INTEGER NOT NULL,
+    notes TEXT,
+    question_count INTEGER NOT NULL DEFAULT 0,
+    published_by INTEGER,
+    published_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (quiz_id, version_number)
+);
+
+ALTER TABLE quiz_quizzes ADD COLUMN IF NOT EXISTS published_version_id INTEGER;
+
+-- version_id NULL = soal draft yang bisa diedit admin.
+-- source_question_id/source_option_id pada baris beku menunjuk ke baris draft asalnya.
+ALTER TABLE quiz_questions
+    ADD COLUMN IF NOT EXISTS version_id INTEGER,
+    ADD COLUMN IF NOT EXISTS source_question_id INTEGER;
+
+ALTER TABLE quiz_options ADD COLUMN IF NOT EXISTS source_option_id INTEGER;
+
+ALTER TABLE quiz_sampling_rules ADD COLUMN IF NOT EXISTS version_id INTEGER;
+
+ALTER TABLE quiz_attempts ADD COLUMN IF NOT EXISTS version_id INTEGER;
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_versions_quiz_id'
+        ) THEN
+            ALTER TABLE quiz_versions
+            ADD CONSTRAINT fk_quiz_versions_quiz_id
+            FOREIGN KEY (quiz_id) REFERENCES quiz_quizzes(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_versions_published_by'
+        ) THEN
+            ALTER TABLE quiz_versions
+            ADD CONSTRAINT fk_quiz_versions_published_by
+            FOREIGN KEY (published_by) REFERENCES users(id)
+            ON DELETE SET NULL;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_quizzes_published_version_id'
+        ) THEN
+            ALTER TABLE quiz_quizzes
+            ADD CONSTRAINT fk_quiz_quizzes_published_version_id
+            FOREIGN KEY (published_version_id) REFERENCES quiz_versions(id)
+            ON DELETE SET NULL;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_questions_version_id'
+        ) THEN
+            ALTER TABLE quiz_questions
+            ADD CONSTRAINT fk_quiz_questions_version_id
+            FOREIGN KEY (version_id) REFERENCES quiz_versions(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_sampling_rules_version_id'
+        ) THEN
+            ALTER TABLE quiz_sampling_rules
+            ADD CONSTRAINT fk_quiz_sampling_rules_version_id
+            FOREIGN KEY (version_id) REFERENCES quiz_versions(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_attempts_version_id'
+        ) THEN
+            ALTER TABLE quiz_attempts
+            ADD CONSTRAINT fk_quiz_attempts_version_id
+            FOREIGN KEY (version_id) REFERENCES quiz_versions(id)
+            ON DELETE SET NULL;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_questions_version_id ON quiz_questions(version_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_sampling_rules_version_id ON quiz_sampling_rules(version_id);
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_version_id ON quiz_attempts(version_id);
+
+-- Kuis yang sudah punya soal atau aturan sampling langsung dipublikasikan
+-- sebagai versi 1 agar siswa tetap bisa mengerjakannya.
+INSERT INTO quiz_versions (quiz_id, version_number, notes)
+SELECT q.id, 1, 'Versi awal dari soal yang sudah ada'
+FROM quiz_quizzes q
+WHERE EXISTS (SELECT 1 FROM quiz_questions qq WHERE qq.quiz_id = q.id)
+   OR EXISTS (SELECT 1 FROM quiz_sampling_rules r WHERE r.quiz_id = q.id)
+ON CONFLICT (quiz_id, version_number) DO NOTHING;
+
+UPDATE quiz_quizzes q SET published_version_id = v.id FROM quiz_versions v WHERE v.quiz_id = q.id;
+UPDATE quiz_attempts a SET version_id = v.id FROM quiz_versions v WHERE v.quiz_id = a.quiz_id;
+
+-- Baris yang sudah dirujuk attempt dibekukan di tempat sehingga ID-nya tetap,
+-- lalu dibuatkan salinan draft. Soal bank dibekukan ke versi attempt pertama
+-- yang mengambilnya.
+UPDATE quiz_questions qq SET version_id = v.id FROM quiz_versions v WHERE v.quiz_id = qq.quiz_id;
+
+UPDATE quiz_questions qq
+SET version_id = used.version_id
+FROM (
+    SELECT DISTINCT ON (u.question_id) u.question_id, a.version_id
+    FROM quiz_attempts a, unnest(a.question_ids) AS u(question_id)
+    WHERE a.version_id IS NOT NULL
+    ORDER BY u.question_id, a.id
+) used
+WHERE qq.id = used.question_id AND qq.bank_id IS NOT NULL AND qq.version_id IS NULL;
+
+-- Pemetaan baris sumber -> salinan. version_id NULL = salinan draft.
+CREATE TEMP TABLE tmp_question_copies (source_id INTEGER, new_id INTEGER, version_id INTEGER);
+CREATE TEMP TABLE tmp_option_copies (source_id INTEGER, new_id INTEGER, new_question_id INTEGER);
+
+INSERT INTO tmp_question_copies (source_id, new_id, version_id)
+SELECT id, nextval(pg_get_serial_sequence('quiz_questions', 'id')), NULL
+FROM quiz_questions
+WHERE version_id IS NOT NULL;
+
+INSERT INTO quiz_questions (
+    id, quiz_id, bank_id, question_text, text_format, question_type, scoring_mode, match_mode,
+    accepted_answers, numeric_answer, numeric_tolerance, tags, difficulty, explanation,
+    points, penalty_points, version_id, source_question_id, created_at, updated_at
+)
+SELECT
+    c.new_id, q.quiz_id, q.bank_id, q.question_text, q.text_format, q.question_type, q.scoring_mode, q.match_mode,
+    q.accepted_answers, q.numeric_answer, q.numeric_tolerance, q.tags, q.difficulty, q.explanation,
+    q.points, q.penalty_points, c.version_id, CASE WHEN c.version_id IS NOT NULL THEN c.source_id END,
+    q.created_at, q.updated_at
+FROM tmp_question_copies c
+    JOIN quiz_questions q ON q.id = c.source_id;
+
+INSERT INTO tmp_option_copies (source_id, new_id, new_question_id)
+SELECT o.id, nextval(pg_get_serial_sequence('quiz_options', 'id')), c.new_id
+FROM quiz_options o
+    JOIN tmp_question_copies c ON c.source_id = o.question_id;
+
+INSERT INTO quiz_options (
+    id, question_id, option_text, text_format, is_correct, explanation, source_option_id, created_at, updated_at
+)
+SELECT
+    c.new_id, c.new_question_id, o.option_text, o.text_format, o.is_correct, o.explanation,
+    CASE WHEN qc.version_id IS NOT NULL THEN c.source_id END, o.created_at, o.updated_at
+FROM tmp_option_copies c
+    JOIN quiz_options o ON o.id = c.source_id
+    JOIN tmp_question_copies qc ON qc.new_id = c.new_question_id;
+
+-- File lampiran dipakai bersama oleh baris draft dan baris beku
+INSERT INTO quiz_attachments (
+    question_id, option_id, kind, file_path, file_name, content_type, file_size, sort_order, created_at
+)
+SELECT qc.new_id, oc.new_id, a.kind, a.file_path, a.file_name, a.content_type, a.file_size, a.sort_order, a.created_at
+FROM quiz_attachments a
+    JOIN tmp_question_copies qc ON qc.source_id = a.question_id
+    LEFT JOIN tmp_option_copies oc ON oc.source_id = a.option_id AND oc.new_question_id = qc.new_id;
+
+UPDATE quiz_questions q SET source_question_id = c.new_id
+FROM tmp_question_copies c
+WHERE q.id = c.source_id AND c.version_id IS NULL;
+
+UPDATE quiz_options o SET source_option_id = c.new_id
+FROM tmp_option_copies c
+    JOIN tmp_question_copies qc ON qc.new_id = c.new_question_id
+WHERE o.id = c.source_id AND qc.version_id IS NULL;
+
+-- Aturan sampling versi 1 dan isi bank soal yang bisa diambil versi tersebut
+INSERT INTO quiz_sampling_rules (quiz_id, bank_id, tag, difficulty, question_count, version_id, created_at)
+SELECT r.quiz_id, r.bank_id, r.tag, r.difficulty, r.question_count, v.id, r.created_at
+FROM quiz_sampling_rules r
+    JOIN quiz_versions v ON v.quiz_id = r.quiz_id
+WHERE r.version_id IS NULL;
+
+TRUNCATE tmp_question_copies, tmp_option_copies;
+
+INSERT INTO tmp_question_copies (source_id, new_id, version_id)
+SELECT q.id, nextval(pg_get_serial_sequence('quiz_questions', 'id')), pools.version_id
+FROM (
+    SELECT DISTINCT version_id, bank_id
+    FROM quiz_sampling_rules
+    WHERE version_id IS NOT NULL
+) pools
+    JOIN quiz_questions q ON q.bank_id = pools.bank_id AND q.version_id IS NULL
+WHERE NOT EXISTS (
+    SELECT 1 FROM quiz_questions frozen
+    WHERE frozen.version_id = pools.version_id AND frozen.source_question_id = q.id
+);
+
+INSERT INTO quiz_questions (
+    id, quiz_id, bank_id, question_text, text_format, question_type, scoring_mode, match_mode,
+    accepted_answers, numeric_answer, numeric_tolerance, tags, difficulty, explanation,
+    points, penalty_points, version_id, source_question_id, created_at, updated_at
+)
+SELECT
+    c.new_id, q.quiz_id, q.bank_id, q.question_text, q.text_format, q.question_type, q.scoring_mode, q.match_mode,
+    q.accepted_answers, q.numeric_answer, q.numeric_tolerance, q.tags, q.difficulty, q.explanation,
+    q.points, q.penalty_points, c.version_id, c.source_id, q.created_at, q.updated_at
+FROM tmp_question_copies c
+    JOIN quiz_questions q ON q.id = c.source_id;
+
+INSERT INTO tmp_option_copies (source_id, new_id, new_question_id)
+SELECT o.id, nextval(pg_get_serial_sequence('quiz_options', 'id')), c.new_id
+FROM quiz_options o
+    JOIN tmp_question_copies c ON c.source_id = o.question_id;
+
+INSERT INTO quiz_options (
+    id, question_id, option_text, text_format, is_correct, explanation, source_option_id, created_at, updated_at
+)
+SELECT
+    c.new_id, c.new_question_id, o.option_text, o.text_format, o.is_correct, o.explanation,
+    c.source_id, o.created_at, o.updated_at
+FROM tmp_option_copies c
+    JOIN quiz_options o ON o.id = c.source_id;
+
+INSERT INTO quiz_attachments (
+    question_id, option_id, kind, file_path, file_name, content_type, file_size, sort_order, created_at
+)
+SELECT qc.new_id, oc.new_id, a.kind, a.file_path, a.file_name, a.content_type, a.file_size, a.sort_order, a.created_at
+FROM quiz_attachments a
+    JOIN tmp_question_copies qc ON qc.source_id = a.question_id
+    LEFT JOIN tmp_option_copies oc ON oc.source_id = a.option_id AND oc.new_question_id = qc.new_id;
+
+DROP TABLE tmp_question_copies, tmp_option_copies;
+
+UPDATE quiz_versions v
+SET question_count = (
+    SELECT COUNT(*) FROM quiz_questions q WHERE q.version_id = v.id AND q.quiz_id IS NOT NULL
+);
+
+-- migrate:down
+-- Hanya soal draft yang dipertahankan. Baris beku dihapus, termasuk yang
+-- dirujuk attempt lama, jadi riwayat attempt tersebut tidak bisa dipulihkan.
+ALTER TABLE quiz_attempts DROP CONSTRAINT IF EXISTS fk_quiz_attempts_version_id;
+ALTER TABLE quiz_quizzes DROP CONSTRAINT IF EXISTS fk_quiz_quizzes_published_version_id;
+
+DROP TABLE IF EXISTS quiz_versions CASCADE;
+
+DELETE FROM quiz_sampling_rules WHERE version_id IS NOT NULL;
+DELETE FROM quiz_questions WHERE version_id IS NOT NULL;
+
+ALTER TABLE quiz_attempts DROP COLUMN IF EXISTS version_id;
+ALTER TABLE quiz_sampling_rules DROP COLUMN IF EXISTS version_id;
+ALTER TABLE quiz_options DROP COLUMN IF EXISTS source_option_id;
+ALTER TABLE quiz_questions
+    DROP COLUMN IF EXISTS source_question_id,
+    DROP COLUMN IF EXISTS version_id;
+ALTER TABLE quiz_quizzes DROP COLUMN IF EXISTS published_version_id;
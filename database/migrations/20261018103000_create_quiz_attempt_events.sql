@@ -0,0 +1,39 @@
+-- migrate:up
+-- Catatan integritas pengerjaan kuis. Event dari klien (window_blur,
+-- fullscreen_exit, copy, paste, tab_switch) dikirim selama attempt berjalan,
+-- sedangkan attempt_started, question_navigated dan attempt_submitted dicatat
+-- server. Setiap event menyimpan IP dan User-Agent saat itu untuk ditinjau mentor.
+CREATE TABLE IF NOT EXISTS quiz_attempt_events (
+    id SERIAL PRIMARY KEY,
+    attempt_id INTEGER NOT NULL,
+    event_type VARCHAR(30) NOT NULL CHECK (event_type IN (
+        'window_blur', 'fullscreen_exit', 'copy', 'paste', 'tab_switch',
+        'attempt_started', 'question_navigated', 'attempt_submitted'
+    )),
+    details JSONB,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_attempt_events_attempt_id'
+        ) THEN
+            ALTER TABLE quiz_attempt_events
+            ADD CONSTRAINT fk_quiz_attempt_events_attempt_id
+            FOREIGN KEY (attempt_id) REFERENCES quiz_attempts(id)
+            ON DELETE CASCADE;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempt_events_attempt_id ON quiz_attempt_events(attempt_id, occurred_at);
+
+-- migrate:down
+DROP TABLE IF EXISTS quiz_attempt_events;
@@ -0,0 +1,64 @@
+-- migrate:up
+-- Sertifikat diterbitkan sekali per siswa per kuis, saat siswa pertama kali lulus
+-- pada kuis yang mengaktifkan certificate_enabled. Nama siswa, judul kuis dan
+-- nilai disalin saat terbit agar verifikasi tetap sama walau datanya berubah.
+-- file_path dan emailed_at diisi oleh job latar belakang setelah PDF dibuat dan dikirim.
+ALTER TABLE quiz_quizzes ADD COLUMN IF NOT EXISTS certificate_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS quiz_certificates (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    quiz_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    attempt_id INTEGER,
+    student_name VARCHAR(255) NOT NULL,
+    quiz_title VARCHAR(255) NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    file_path TEXT,
+    issued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    emailed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (quiz_id, user_id)
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_certificates_quiz_id'
+        ) THEN
+            ALTER TABLE quiz_certificates
+            ADD CONSTRAINT fk_quiz_certificates_quiz_id
+            FOREIGN KEY (quiz_id) REFERENCES quiz_quizzes(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_certificates_user_id'
+        ) THEN
+            ALTER TABLE quiz_certificates
+            ADD CONSTRAINT fk_quiz_certificates_user_id
+            FOREIGN KEY (user_id) REFERENCES users(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_quiz_certificates_attempt_id'
+        ) THEN
+            ALTER TABLE quiz_certificates
+            ADD CONSTRAINT fk_quiz_certificates_attempt_id
+            FOREIGN KEY (attempt_id) REFERENCES quiz_attempts(id)
+            ON DELETE SET NULL;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+-- migrate:down
+DROP TABLE IF EXISTS quiz_certificates;
+
+ALTER TABLE quiz_quizzes DROP COLUMN IF EXISTS certificate_enabled;
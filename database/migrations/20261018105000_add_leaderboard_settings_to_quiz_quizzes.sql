@@ -0,0 +1,21 @@
+-- migrate:up
+-- Pengaturan papan peringkat per kuis. Papan peringkat aktif kecuali kuis memilih
+-- keluar; nama peserta bisa disamarkan, dan 10 besar bisa dibuka untuk publik
+-- (misalnya untuk event tryout).
+ALTER TABLE quiz_quizzes
+    ADD COLUMN IF NOT EXISTS leaderboard_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS leaderboard_anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS leaderboard_public BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Peringkat dihitung dari attempt selesai per kuis, diurutkan berdasarkan nilai.
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_leaderboard
+    ON quiz_attempts(quiz_id, score DESC)
+    WHERE status = 'completed';
+
+-- migrate:down
+DROP INDEX IF EXISTS idx_quiz_attempts_leaderboard;
+
+ALTER TABLE quiz_quizzes
+    DROP COLUMN IF EXISTS leaderboard_public,
+    DROP COLUMN IF EXISTS leaderboard_anonymous,
+    DROP COLUMN IF EXISTS leaderboard_opt_out;
@@ -0,0 +1,64 @@
+-- migrate:up
+-- Refresh token opaque yang dirotasi setiap dipakai. Satu family mewakili satu login
+-- (satu perangkat); token lama yang dipakai ulang mencabut seluruh family-nya.
+-- Token hanya disimpan sebagai hash SHA-256.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    family_id UUID NOT NULL,
+    user_id INTEGER NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    parent_id BIGINT,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_refresh_tokens_user_id'
+        ) THEN
+            ALTER TABLE refresh_tokens
+            ADD CONSTRAINT fk_refresh_tokens_user_id
+            FOREIGN KEY (user_id) REFERENCES users(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_refresh_tokens_parent_id'
+        ) THEN
+            ALTER TABLE refresh_tokens
+            ADD CONSTRAINT fk_refresh_tokens_parent_id
+            FOREIGN KEY (parent_id) REFERENCES refresh_tokens(id)
+            ON DELETE SET NULL;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+
+-- Satu token per pengguna digantikan oleh refresh_tokens; semua pengguna perlu login ulang.
+DROP TABLE IF EXISTS user_has_tokens;
+
+-- migrate:down
+CREATE TABLE IF NOT EXISTS user_has_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id SERIAL NOT NULL UNIQUE,
+    token TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+ALTER TABLE user_has_tokens
+    ADD CONSTRAINT fk_user_tokens FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens ON user_has_tokens(user_id);
+
+DROP TABLE IF EXISTS refresh_tokens;
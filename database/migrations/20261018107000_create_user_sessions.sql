@@ -0,0 +1,60 @@
+-- migrate:up
+-- Satu baris per login/perangkat. Refresh token yang dirotasi dikelompokkan per sesi,
+-- dan access token menyimpan id sesi sehingga sesi bisa dicabut satu per satu.
+-- expires_at ikut diperpanjang setiap refresh token dirotasi.
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id UUID PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    device_name VARCHAR(100),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_user_sessions_user_id'
+        ) THEN
+            ALTER TABLE user_sessions
+            ADD CONSTRAINT fk_user_sessions_user_id
+            FOREIGN KEY (user_id) REFERENCES users(id)
+            ON DELETE CASCADE;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions(user_id);
+
+-- Family refresh token yang sudah ada dijadikan sesi tanpa info perangkat.
+INSERT INTO user_sessions (id, user_id, expires_at, last_seen_at, revoked_at, created_at)
+SELECT
+    family_id,
+    MIN(user_id),
+    MAX(expires_at),
+    MAX(created_at),
+    CASE WHEN BOOL_AND(revoked_at IS NOT NULL) THEN MAX(revoked_at) END,
+    MIN(created_at)
+FROM refresh_tokens
+GROUP BY family_id
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE refresh_tokens RENAME COLUMN family_id TO session_id;
+ALTER INDEX IF EXISTS idx_refresh_tokens_family_id RENAME TO idx_refresh_tokens_session_id;
+
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT fk_refresh_tokens_session_id FOREIGN KEY (session_id) REFERENCES user_sessions(id) ON DELETE CASCADE;
+
+-- migrate:down
+ALTER TABLE refresh_tokens DROP CONSTRAINT IF EXISTS fk_refresh_tokens_session_id;
+
+ALTER INDEX IF EXISTS idx_refresh_tokens_session_id RENAME TO idx_refresh_tokens_family_id;
+ALTER TABLE refresh_tokens RENAME COLUMN session_id TO family_id;
+
+DROP TABLE IF EXISTS user_sessions;
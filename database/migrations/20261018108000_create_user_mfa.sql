@@ -0,0 +1,71 @@
+-- migrate:up
+-- Secret TOTP per pengguna. Baris dengan enabled_at NULL berarti pendaftaran belum
+-- dikonfirmasi dengan kode pertama. last_used_step mencegah kode yang sama dipakai ulang.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id INTEGER PRIMARY KEY,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at TIMESTAMP,
+    last_used_step BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Kode pemulihan hanya disimpan hash SHA-256-nya dan masing-masing sekali pakai.
+CREATE TABLE IF NOT EXISTS user_mfa_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Pengaturan aplikasi yang bisa diubah admin tanpa deploy ulang.
+CREATE TABLE IF NOT EXISTS app_settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by INTEGER,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_user_mfa_user_id'
+        ) THEN
+            ALTER TABLE user_mfa
+            ADD CONSTRAINT fk_user_mfa_user_id
+            FOREIGN KEY (user_id) REFERENCES users(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_user_mfa_recovery_codes_user_id'
+        ) THEN
+            ALTER TABLE user_mfa_recovery_codes
+            ADD CONSTRAINT fk_user_mfa_recovery_codes_user_id
+            FOREIGN KEY (user_id) REFERENCES users(id)
+            ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_app_settings_updated_by'
+        ) THEN
+            ALTER TABLE app_settings
+            ADD CONSTRAINT fk_app_settings_updated_by
+            FOREIGN KEY (updated_by) REFERENCES users(id)
+            ON DELETE SET NULL;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX IF NOT EXISTS idx_user_mfa_recovery_codes_user_id ON user_mfa_recovery_codes(user_id);
+
+-- migrate:down
+DROP TABLE IF EXISTS app_settings;
+DROP TABLE IF EXISTS user_mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
@@ -0,0 +1,132 @@
+-- migrate:up
+-- Peran kini disimpan di database beserta izin yang dimilikinya, sehingga admin
+-- bisa membuat peran baru (mis. koordinator akademik) tanpa mengubah kode.
+-- users.role tetap menyimpan nama peran dan kini mengacu ke roles.name.
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT,
+    is_system BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_name VARCHAR(50) NOT NULL,
+    permission_name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role_name, permission_name)
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_role_permissions_role_name'
+        ) THEN
+            ALTER TABLE role_permissions
+            ADD CONSTRAINT fk_role_permissions_role_name
+            FOREIGN KEY (role_name) REFERENCES roles(name)
+            ON UPDATE CASCADE ON DELETE CASCADE;
+        END IF;
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_role_permissions_permission_name'
+        ) THEN
+            ALTER TABLE role_permissions
+            ADD CONSTRAINT fk_role_permissions_permission_name
+            FOREIGN KEY (permission_name) REFERENCES permissions(name)
+            ON UPDATE CASCADE ON DELETE CASCADE;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX IF NOT EXISTS idx_role_permissions_permission_name ON role_permissions(permission_name);
+
+INSERT INTO permissions (name, description) VALUES
+    ('admin.panel', 'Login ke panel admin'),
+    ('user.view', 'Melihat daftar pengguna, siswa, dan mentor'),
+    ('user.manage', 'Membuat, mengubah, mengaktifkan, dan menghapus pengguna'),
+    ('role.manage', 'Mengelola peran, izin, dan peran pengguna'),
+    ('security.manage', 'Mengatur kebijakan keamanan seperti kewajiban 2FA'),
+    ('mfa.enroll', 'Mengaktifkan autentikasi dua faktor untuk akun sendiri'),
+    ('class.manage', 'Membuat, mengubah, dan menghapus kelas'),
+    ('session.view', 'Melihat semua sesi pertemuan'),
+    ('session.create', 'Membuat sesi pertemuan'),
+    ('session.manage', 'Mengubah dan menghapus sesi pertemuan'),
+    ('quiz.manage', 'Mengelola kuis, bank soal, dan attempt siswa'),
+    ('blog.publish', 'Menulis, mengubah, dan menghapus blog'),
+    ('testimony.manage', 'Mengelola testimoni'),
+    ('static_asset.manage', 'Mengelola aset statis')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO roles (name, description, is_system) VALUES
+    ('admin', 'Akses penuh ke seluruh aplikasi', TRUE),
+    ('mentor', 'Pengajar yang mengelola sesi pertemuan dan menulis blog', TRUE),
+    ('user', 'Siswa', TRUE),
+    ('academic_coordinator', 'Koordinator akademik: kelas, sesi pertemuan, dan kuis', FALSE),
+    ('content_editor', 'Editor konten: blog, testimoni, dan aset statis', FALSE)
+ON CONFLICT (name) DO NOTHING;
+
+-- Nilai users.role lain yang mungkin sudah ada ikut didaftarkan agar foreign key valid.
+INSERT INTO roles (name, is_system)
+SELECT DISTINCT role, FALSE FROM users
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_name, permission_name)
+SELECT 'admin', name FROM permissions
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_name, permission_name) VALUES
+    ('mentor', 'mfa.enroll'),
+    ('mentor', 'session.view'),
+    ('mentor', 'session.create'),
+    ('mentor', 'session.manage'),
+    ('mentor', 'blog.publish'),
+    ('academic_coordinator', 'admin.panel'),
+    ('academic_coordinator', 'mfa.enroll'),
+    ('academic_coordinator', 'user.view'),
+    ('academic_coordinator', 'class.manage'),
+    ('academic_coordinator', 'session.view'),
+    ('academic_coordinator', 'session.create'),
+    ('academic_coordinator', 'session.manage'),
+    ('academic_coordinator', 'quiz.manage'),
+    ('content_editor', 'admin.panel'),
+    ('content_editor', 'mfa.enroll'),
+    ('content_editor', 'blog.publish'),
+    ('content_editor', 'testimony.manage'),
+    ('content_editor', 'static_asset.manage')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(50);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_users_role'
+        ) THEN
+            ALTER TABLE users
+            ADD CONSTRAINT fk_users_role
+            FOREIGN KEY (role) REFERENCES roles(name)
+            ON UPDATE CASCADE;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+-- migrate:down
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(20);
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,18 @@
+-- migrate:up
+-- Mentor hanya boleh mengubah sesi pertemuan dan blog miliknya sendiri.
+-- Izin *.manage_all melewati pemeriksaan kepemilikan tersebut, untuk admin
+-- dan peran yang memang mengelola data semua mentor.
+INSERT INTO permissions (name, description) VALUES
+    ('session.manage_all', 'Melihat, mengubah, dan menghapus sesi pertemuan milik mentor mana pun'),
+    ('blog.manage_all', 'Mengubah dan menghapus blog milik penulis mana pun')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_name, permission_name) VALUES
+    ('admin', 'session.manage_all'),
+    ('admin', 'blog.manage_all'),
+    ('academic_coordinator', 'session.manage_all'),
+    ('content_editor', 'blog.manage_all')
+ON CONFLICT DO NOTHING;
+
+-- migrate:down
+DELETE FROM permissions WHERE name IN ('session.manage_all', 'blog.manage_all');
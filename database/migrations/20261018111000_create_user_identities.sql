@@ -0,0 +1,34 @@
+-- migrate:up
+-- Akun penyedia login eksternal (mis. Google) yang tertaut ke pengguna. Tautan
+-- dibuat saat login pertama lewat email terverifikasi yang cocok dengan users.email,
+-- lalu login berikutnya dicocokkan lewat (provider, subject) walau email berubah.
+CREATE TABLE IF NOT EXISTS user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_user_identities_provider_subject UNIQUE (provider, subject),
+    CONSTRAINT uq_user_identities_user_id_provider UNIQUE (user_id, provider)
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_user_identities_user_id'
+        ) THEN
+            ALTER TABLE user_identities
+            ADD CONSTRAINT fk_user_identities_user_id
+            FOREIGN KEY (user_id) REFERENCES users(id)
+            ON DELETE CASCADE;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+-- migrate:down
+DROP TABLE IF EXISTS user_identities;
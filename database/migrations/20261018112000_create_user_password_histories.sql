@@ -0,0 +1,30 @@
+-- migrate:up
+-- Hash bcrypt dari password lama pengguna, agar password baru tidak boleh sama
+-- dengan beberapa password terakhir. Baris lama dipangkas saat password diganti.
+CREATE TABLE IF NOT EXISTS user_password_histories (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+DO $$
+    BEGIN
+
+        IF NOT EXISTS (
+            SELECT 1 FROM pg_constraint
+            WHERE conname = 'fk_user_password_histories_user_id'
+        ) THEN
+            ALTER TABLE user_password_histories
+            ADD CONSTRAINT fk_user_password_histories_user_id
+            FOREIGN KEY (user_id) REFERENCES users(id)
+            ON DELETE CASCADE;
+        END IF;
+
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE INDEX IF NOT EXISTS idx_user_password_histories_user_id ON user_password_histories(user_id, created_at DESC);
+
+-- migrate:down
+DROP TABLE IF EXISTS user_password_histories;
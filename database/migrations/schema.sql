@@ -61,6 +61,18 @@ SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: app_settings; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.app_settings (
+    key character varying(100) NOT NULL,
+    value text NOT NULL,
+    updated_by integer,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
 --
 -- Name: blogs; Type: TABLE; Schema: public; Owner: -
 --
@@ -219,6 +231,16 @@ CREATE SEQUENCE public.mentors_id_seq
 ALTER SEQUENCE public.mentors_id_seq OWNED BY public.mentors.id;
 
 
+--
+-- Name: permissions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.permissions (
+    name character varying(100) NOT NULL,
+    description text NOT NULL
+);
+
+
 --
 -- Name: quiz_answers; Type: TABLE; Schema: public; Owner: -
 --
@@ -227,9 +249,14 @@ CREATE TABLE public.quiz_answers (
     id integer NOT NULL,
     attempt_id integer NOT NULL,
     question_id integer NOT NULL,
-    option_id integer NOT NULL,
+    option_id integer,
     is_correct boolean DEFAULT false NOT NULL,
-    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    option_ids integer[],
+    text_answer text,
+    numeric_answer numeric,
+    credit numeric(5,4) DEFAULT 0 NOT NULL,
+    points_awarded numeric(8,2) DEFAULT 0 NOT NULL
 );
 
 
@@ -253,6 +280,82 @@ CREATE SEQUENCE public.quiz_answers_id_seq
 ALTER SEQUENCE public.quiz_answers_id_seq OWNED BY public.quiz_answers.id;
 
 
+--
+-- Name: quiz_attachments; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.quiz_attachments (
+    id integer NOT NULL,
+    question_id integer NOT NULL,
+    option_id integer,
+    kind character varying(10) NOT NULL,
+    file_path text NOT NULL,
+    file_name character varying(255) NOT NULL,
+    content_type character varying(100) NOT NULL,
+    file_size bigint DEFAULT 0 NOT NULL,
+    sort_order integer DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT quiz_attachments_kind_check CHECK (((kind)::text = ANY ((ARRAY['image'::character varying, 'audio'::character varying])::text[])))
+);
+
+
+--
+-- Name: quiz_attachments_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.quiz_attachments_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: quiz_attachments_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.quiz_attachments_id_seq OWNED BY public.quiz_attachments.id;
+
+
+--
+-- Name: quiz_attempt_events; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.quiz_attempt_events (
+    id integer NOT NULL,
+    attempt_id integer NOT NULL,
+    event_type character varying(30) NOT NULL,
+    details jsonb,
+    ip_address character varying(45),
+    user_agent text,
+    occurred_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT quiz_attempt_events_event_type_check CHECK (((event_type)::text = ANY ((ARRAY['window_blur'::character varying, 'fullscreen_exit'::character varying, 'copy'::character varying, 'paste'::character varying, 'tab_switch'::character varying, 'attempt_started'::character varying, 'question_navigated'::character varying, 'attempt_submitted'::character varying])::text[])))
+);
+
+
+--
+-- Name: quiz_attempt_events_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.quiz_attempt_events_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: quiz_attempt_events_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.quiz_attempt_events_id_seq OWNED BY public.quiz_attempt_events.id;
+
+
 --
 -- Name: quiz_attempts; Type: TABLE; Schema: public; Owner: -
 --
@@ -271,7 +374,12 @@ CREATE TABLE public.quiz_attempts (
     updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
     question_ids integer[],
     option_order jsonb,
-    current_question_index integer DEFAULT 0
+    current_question_index integer DEFAULT 0,
+    expires_at timestamp without time zone,
+    auto_submitted boolean DEFAULT false NOT NULL,
+    raw_score numeric(10,2),
+    max_score numeric(10,2),
+    version_id integer
 );
 
 
@@ -295,6 +403,58 @@ CREATE SEQUENCE public.quiz_attempts_id_seq
 ALTER SEQUENCE public.quiz_attempts_id_seq OWNED BY public.quiz_attempts.id;
 
 
+--
+-- Name: quiz_certificates; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.quiz_certificates (
+    id integer NOT NULL,
+    code character varying(20) NOT NULL,
+    quiz_id integer NOT NULL,
+    user_id integer NOT NULL,
+    attempt_id integer,
+    student_name character varying(255) NOT NULL,
+    quiz_title character varying(255) NOT NULL,
+    score double precision NOT NULL,
+    file_path text,
+    issued_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    emailed_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
+--
+-- Name: quiz_certificates_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.quiz_certificates_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: quiz_certificates_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.quiz_certificates_id_seq OWNED BY public.quiz_certificates.id;
+
+
+--
+-- Name: quiz_class_assignments; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.quiz_class_assignments (
+    quiz_id integer NOT NULL,
+    class_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
 --
 -- Name: quiz_options; Type: TABLE; Schema: public; Owner: -
 --
@@ -305,7 +465,11 @@ CREATE TABLE public.quiz_options (
     option_text text NOT NULL,
     is_correct boolean DEFAULT false NOT NULL,
     created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
-    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    explanation text,
+    text_format character varying(10) DEFAULT 'plain'::character varying NOT NULL,
+    source_option_id integer,
+    CONSTRAINT chk_quiz_options_text_format CHECK (((text_format)::text = ANY ((ARRAY['plain'::character varying, 'markdown'::character varying, 'latex'::character varying])::text[])))
 );
 
 
@@ -329,16 +493,69 @@ CREATE SEQUENCE public.quiz_options_id_seq
 ALTER SEQUENCE public.quiz_options_id_seq OWNED BY public.quiz_options.id;
 
 
+--
+-- Name: quiz_question_banks; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.quiz_question_banks (
+    id integer NOT NULL,
+    name character varying(255) NOT NULL,
+    description text,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    deleted_at timestamp without time zone
+);
+
+
+--
+-- Name: quiz_question_banks_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.quiz_question_banks_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: quiz_question_banks_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.quiz_question_banks_id_seq OWNED BY public.quiz_question_banks.id;
+
+
 --
 -- Name: quiz_questions; Type: TABLE; Schema: public; Owner: -
 --
 
 CREATE TABLE public.quiz_questions (
     id integer NOT NULL,
-    quiz_id integer NOT NULL,
+    quiz_id integer,
     question_text text NOT NULL,
     created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
-    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    question_type character varying(20) DEFAULT 'single_choice'::character varying NOT NULL,
+    scoring_mode character varying(20) DEFAULT 'all_or_nothing'::character varying NOT NULL,
+    match_mode character varying(20),
+    accepted_answers text[],
+    numeric_answer numeric,
+    numeric_tolerance numeric DEFAULT 0 NOT NULL,
+    bank_id integer,
+    tags text[] DEFAULT '{}'::text[] NOT NULL,
+    difficulty character varying(10),
+    points numeric(6,2) DEFAULT 1 NOT NULL,
+    penalty_points numeric(6,2) DEFAULT 0 NOT NULL,
+    explanation text,
+    text_format character varying(10) DEFAULT 'plain'::character varying NOT NULL,
+    version_id integer,
+    source_question_id integer,
+    CONSTRAINT chk_quiz_questions_owner CHECK (((quiz_id IS NULL) <> (bank_id IS NULL))),
+    CONSTRAINT chk_quiz_questions_penalty_points CHECK ((penalty_points >= (0)::numeric)),
+    CONSTRAINT chk_quiz_questions_points CHECK ((points > (0)::numeric)),
+    CONSTRAINT chk_quiz_questions_text_format CHECK (((text_format)::text = ANY ((ARRAY['plain'::character varying, 'markdown'::character varying, 'latex'::character varying])::text[])))
 );
 
 
@@ -376,7 +593,23 @@ CREATE TABLE public.quiz_quizzes (
     created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
     updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
     deleted_at timestamp without time zone,
-    code character varying(8) NOT NULL
+    code character varying(8) NOT NULL,
+    max_attempts integer DEFAULT 1,
+    cooldown_minutes integer DEFAULT 0 NOT NULL,
+    score_policy character varying(10) DEFAULT 'latest'::character varying NOT NULL,
+    review_policy character varying(20) DEFAULT 'never'::character varying NOT NULL,
+    opens_at timestamp without time zone,
+    closes_at timestamp without time zone,
+    published_version_id integer,
+    certificate_enabled boolean DEFAULT false NOT NULL,
+    leaderboard_opt_out boolean DEFAULT false NOT NULL,
+    leaderboard_anonymous boolean DEFAULT false NOT NULL,
+    leaderboard_public boolean DEFAULT false NOT NULL,
+    CONSTRAINT chk_quiz_quizzes_cooldown_minutes CHECK ((cooldown_minutes >= 0)),
+    CONSTRAINT chk_quiz_quizzes_max_attempts CHECK (((max_attempts IS NULL) OR (max_attempts >= 1))),
+    CONSTRAINT chk_quiz_quizzes_review_policy CHECK (((review_policy)::text = ANY ((ARRAY['never'::character varying, 'immediately'::character varying, 'after_close'::character varying])::text[]))),
+    CONSTRAINT chk_quiz_quizzes_score_policy CHECK (((score_policy)::text = ANY ((ARRAY['best'::character varying, 'latest'::character varying, 'average'::character varying])::text[]))),
+    CONSTRAINT chk_quiz_quizzes_window CHECK (((opens_at IS NULL) OR (closes_at IS NULL) OR (closes_at > opens_at)))
 );
 
 
@@ -400,6 +633,138 @@ CREATE SEQUENCE public.quiz_quizzes_id_seq
 ALTER SEQUENCE public.quiz_quizzes_id_seq OWNED BY public.quiz_quizzes.id;
 
 
+--
+-- Name: quiz_sampling_rules; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.quiz_sampling_rules (
+    id integer NOT NULL,
+    quiz_id integer NOT NULL,
+    bank_id integer NOT NULL,
+    tag character varying(100),
+    difficulty character varying(10),
+    question_count integer NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    version_id integer,
+    CONSTRAINT quiz_sampling_rules_question_count_check CHECK ((question_count > 0))
+);
+
+
+--
+-- Name: quiz_sampling_rules_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.quiz_sampling_rules_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: quiz_sampling_rules_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.quiz_sampling_rules_id_seq OWNED BY public.quiz_sampling_rules.id;
+
+
+--
+-- Name: quiz_versions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.quiz_versions (
+    id integer NOT NULL,
+    quiz_id integer NOT NULL,
+    version_number integer NOT NULL,
+    notes text,
+    question_count integer DEFAULT 0 NOT NULL,
+    published_by integer,
+    published_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
+--
+-- Name: quiz_versions_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.quiz_versions_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: quiz_versions_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.quiz_versions_id_seq OWNED BY public.quiz_versions.id;
+
+
+--
+-- Name: refresh_tokens; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.refresh_tokens (
+    id bigint NOT NULL,
+    session_id uuid NOT NULL,
+    user_id integer NOT NULL,
+    token_hash character(64) NOT NULL,
+    parent_id bigint,
+    expires_at timestamp without time zone NOT NULL,
+    used_at timestamp without time zone,
+    revoked_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
+--
+-- Name: refresh_tokens_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.refresh_tokens_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: refresh_tokens_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.refresh_tokens_id_seq OWNED BY public.refresh_tokens.id;
+
+
+--
+-- Name: role_permissions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.role_permissions (
+    role_name character varying(50) NOT NULL,
+    permission_name character varying(100) NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
+--
+-- Name: roles; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.roles (
+    name character varying(50) NOT NULL,
+    description text,
+    is_system boolean DEFAULT false NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
 --
 -- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
 --
@@ -551,24 +916,25 @@ ALTER SEQUENCE public.testimonials_id_seq OWNED BY public.testimonials.id;
 
 
 --
--- Name: user_has_tokens; Type: TABLE; Schema: public; Owner: -
+-- Name: user_identities; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.user_has_tokens (
+CREATE TABLE public.user_identities (
     id integer NOT NULL,
     user_id integer NOT NULL,
-    token text NOT NULL,
-    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
-    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
-    deleted_at timestamp without time zone
+    provider character varying(50) NOT NULL,
+    subject character varying(255) NOT NULL,
+    email character varying(255),
+    last_login_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
 );
 
 
 --
--- Name: user_has_tokens_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+-- Name: user_identities_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-CREATE SEQUENCE public.user_has_tokens_id_seq
+CREATE SEQUENCE public.user_identities_id_seq
     AS integer
     START WITH 1
     INCREMENT BY 1
@@ -578,55 +944,44 @@ CREATE SEQUENCE public.user_has_tokens_id_seq
 
 
 --
--- Name: user_has_tokens_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
---
-
-ALTER SEQUENCE public.user_has_tokens_id_seq OWNED BY public.user_has_tokens.id;
-
-
---
--- Name: user_has_tokens_user_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+-- Name: user_identities_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
-CREATE SEQUENCE public.user_has_tokens_user_id_seq
-    AS integer
-    START WITH 1
-    INCREMENT BY 1
-    NO MINVALUE
-    NO MAXVALUE
-    CACHE 1;
+ALTER SEQUENCE public.user_identities_id_seq OWNED BY public.user_identities.id;
 
 
 --
--- Name: user_has_tokens_user_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+-- Name: user_mfa; Type: TABLE; Schema: public; Owner: -
 --
 
-ALTER SEQUENCE public.user_has_tokens_user_id_seq OWNED BY public.user_has_tokens.user_id;
+CREATE TABLE public.user_mfa (
+    user_id integer NOT NULL,
+    secret character varying(64) NOT NULL,
+    enabled_at timestamp without time zone,
+    last_used_step bigint,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
 
 
 --
--- Name: users; Type: TABLE; Schema: public; Owner: -
+-- Name: user_mfa_recovery_codes; Type: TABLE; Schema: public; Owner: -
 --
 
-CREATE TABLE public.users (
+CREATE TABLE public.user_mfa_recovery_codes (
     id integer NOT NULL,
-    name character varying(100) NOT NULL,
-    email character varying(150) NOT NULL,
-    password text NOT NULL,
-    role character varying(20) DEFAULT 'user'::character varying NOT NULL,
-    email_verified_at timestamp without time zone,
-    is_active boolean DEFAULT true,
-    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
-    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
-    deleted_at timestamp without time zone
+    user_id integer NOT NULL,
+    code_hash character(64) NOT NULL,
+    used_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
 );
 
 
 --
--- Name: users_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+-- Name: user_mfa_recovery_codes_id_seq; Type: SEQUENCE; Schema: public; Owner: -
 --
 
-CREATE SEQUENCE public.users_id_seq
+CREATE SEQUENCE public.user_mfa_recovery_codes_id_seq
     AS integer
     START WITH 1
     INCREMENT BY 1
@@ -636,7 +991,94 @@ CREATE SEQUENCE public.users_id_seq
 
 
 --
--- Name: users_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+-- Name: user_mfa_recovery_codes_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.user_mfa_recovery_codes_id_seq OWNED BY public.user_mfa_recovery_codes.id;
+
+
+--
+-- Name: user_password_histories; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.user_password_histories (
+    id integer NOT NULL,
+    user_id integer NOT NULL,
+    password_hash text NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
+--
+-- Name: user_password_histories_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.user_password_histories_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: user_password_histories_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.user_password_histories_id_seq OWNED BY public.user_password_histories.id;
+
+
+--
+-- Name: user_sessions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.user_sessions (
+    id uuid NOT NULL,
+    user_id integer NOT NULL,
+    device_name character varying(100),
+    ip_address character varying(45),
+    user_agent text,
+    expires_at timestamp without time zone NOT NULL,
+    last_seen_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    revoked_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP
+);
+
+
+--
+-- Name: users; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.users (
+    id integer NOT NULL,
+    name character varying(100) NOT NULL,
+    email character varying(150) NOT NULL,
+    password text NOT NULL,
+    role character varying(50) DEFAULT 'user'::character varying NOT NULL,
+    email_verified_at timestamp without time zone,
+    is_active boolean DEFAULT true,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP,
+    deleted_at timestamp without time zone
+);
+
+
+--
+-- Name: users_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.users_id_seq
+    AS integer
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: users_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
 --
 
 ALTER SEQUENCE public.users_id_seq OWNED BY public.users.id;
@@ -677,6 +1119,20 @@ ALTER TABLE ONLY public.mentors ALTER COLUMN id SET DEFAULT nextval('public.ment
 ALTER TABLE ONLY public.quiz_answers ALTER COLUMN id SET DEFAULT nextval('public.quiz_answers_id_seq'::regclass);
 
 
+--
+-- Name: quiz_attachments id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_attachments ALTER COLUMN id SET DEFAULT nextval('public.quiz_attachments_id_seq'::regclass);
+
+
+--
+-- Name: quiz_attempt_events id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_attempt_events ALTER COLUMN id SET DEFAULT nextval('public.quiz_attempt_events_id_seq'::regclass);
+
+
 --
 -- Name: quiz_attempts id; Type: DEFAULT; Schema: public; Owner: -
 --
@@ -684,6 +1140,13 @@ ALTER TABLE ONLY public.quiz_answers ALTER COLUMN id SET DEFAULT nextval('public
 ALTER TABLE ONLY public.quiz_attempts ALTER COLUMN id SET DEFAULT nextval('public.quiz_attempts_id_seq'::regclass);
 
 
+--
+-- Name: quiz_certificates id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_certificates ALTER COLUMN id SET DEFAULT nextval('public.quiz_certificates_id_seq'::regclass);
+
+
 --
 -- Name: quiz_options id; Type: DEFAULT; Schema: public; Owner: -
 --
@@ -691,6 +1154,13 @@ ALTER TABLE ONLY public.quiz_attempts ALTER COLUMN id SET DEFAULT nextval('publi
 ALTER TABLE ONLY public.quiz_options ALTER COLUMN id SET DEFAULT nextval('public.quiz_options_id_seq'::regclass);
 
 
+--
+-- Name: quiz_question_banks id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_question_banks ALTER COLUMN id SET DEFAULT nextval('public.quiz_question_banks_id_seq'::regclass);
+
+
 --
 -- Name: quiz_questions id; Type: DEFAULT; Schema: public; Owner: -
 --
@@ -705,6 +1175,27 @@ ALTER TABLE ONLY public.quiz_questions ALTER COLUMN id SET DEFAULT nextval('publ
 ALTER TABLE ONLY public.quiz_quizzes ALTER COLUMN id SET DEFAULT nextval('public.quiz_quizzes_id_seq'::regclass);
 
 
+--
+-- Name: quiz_sampling_rules id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_sampling_rules ALTER COLUMN id SET DEFAULT nextval('public.quiz_sampling_rules_id_seq'::regclass);
+
+
+--
+-- Name: quiz_versions id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_versions ALTER COLUMN id SET DEFAULT nextval('public.quiz_versions_id_seq'::regclass);
+
+
+--
+-- Name: refresh_tokens id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.refresh_tokens ALTER COLUMN id SET DEFAULT nextval('public.refresh_tokens_id_seq'::regclass);
+
+
 --
 -- Name: static_assets id; Type: DEFAULT; Schema: public; Owner: -
 --
@@ -734,17 +1225,24 @@ ALTER TABLE ONLY public.testimonials ALTER COLUMN id SET DEFAULT nextval('public
 
 
 --
--- Name: user_has_tokens id; Type: DEFAULT; Schema: public; Owner: -
+-- Name: user_identities id; Type: DEFAULT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.user_has_tokens ALTER COLUMN id SET DEFAULT nextval('public.user_has_tokens_id_seq'::regclass);
+ALTER TABLE ONLY public.user_identities ALTER COLUMN id SET DEFAULT nextval('public.user_identities_id_seq'::regclass);
 
 
 --
--- Name: user_has_tokens user_id; Type: DEFAULT; Schema: public; Owner: -
+-- Name: user_mfa_recovery_codes id; Type: DEFAULT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.user_has_tokens ALTER COLUMN user_id SET DEFAULT nextval('public.user_has_tokens_user_id_seq'::regclass);
+ALTER TABLE ONLY public.user_mfa_recovery_codes ALTER COLUMN id SET DEFAULT nextval('public.user_mfa_recovery_codes_id_seq'::regclass);
+
+
+--
+-- Name: user_password_histories id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_password_histories ALTER COLUMN id SET DEFAULT nextval('public.user_password_histories_id_seq'::regclass);
 
 
 --
@@ -754,6 +1252,14 @@ ALTER TABLE ONLY public.user_has_tokens ALTER COLUMN user_id SET DEFAULT nextval
 ALTER TABLE ONLY public.users ALTER COLUMN id SET DEFAULT nextval('public.users_id_seq'::regclass);
 
 
+--
+-- Name: app_settings app_settings_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.app_settings
+    ADD CONSTRAINT app_settings_pkey PRIMARY KEY (key);
+
+
 --
 -- Name: blogs blogs_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -794,6 +1300,14 @@ ALTER TABLE ONLY public.mentors
     ADD CONSTRAINT mentors_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: permissions permissions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.permissions
+    ADD CONSTRAINT permissions_pkey PRIMARY KEY (name);
+
+
 --
 -- Name: quiz_answers quiz_answers_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -802,6 +1316,22 @@ ALTER TABLE ONLY public.quiz_answers
     ADD CONSTRAINT quiz_answers_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: quiz_attachments quiz_attachments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_attachments
+    ADD CONSTRAINT quiz_attachments_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: quiz_attempt_events quiz_attempt_events_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_attempt_events
+    ADD CONSTRAINT quiz_attempt_events_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: quiz_attempts quiz_attempts_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -810,6 +1340,38 @@ ALTER TABLE ONLY public.quiz_attempts
     ADD CONSTRAINT quiz_attempts_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: quiz_certificates quiz_certificates_code_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_certificates
+    ADD CONSTRAINT quiz_certificates_code_key UNIQUE (code);
+
+
+--
+-- Name: quiz_certificates quiz_certificates_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_certificates
+    ADD CONSTRAINT quiz_certificates_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: quiz_certificates quiz_certificates_quiz_id_user_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_certificates
+    ADD CONSTRAINT quiz_certificates_quiz_id_user_id_key UNIQUE (quiz_id, user_id);
+
+
+--
+-- Name: quiz_class_assignments quiz_class_assignments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_class_assignments
+    ADD CONSTRAINT quiz_class_assignments_pkey PRIMARY KEY (quiz_id, class_id);
+
+
 --
 -- Name: quiz_options quiz_options_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -818,6 +1380,14 @@ ALTER TABLE ONLY public.quiz_options
     ADD CONSTRAINT quiz_options_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: quiz_question_banks quiz_question_banks_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_question_banks
+    ADD CONSTRAINT quiz_question_banks_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: quiz_questions quiz_questions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -842,6 +1412,62 @@ ALTER TABLE ONLY public.quiz_quizzes
     ADD CONSTRAINT quiz_quizzes_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: quiz_sampling_rules quiz_sampling_rules_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_sampling_rules
+    ADD CONSTRAINT quiz_sampling_rules_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: quiz_versions quiz_versions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_versions
+    ADD CONSTRAINT quiz_versions_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: quiz_versions quiz_versions_quiz_id_version_number_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_versions
+    ADD CONSTRAINT quiz_versions_quiz_id_version_number_key UNIQUE (quiz_id, version_number);
+
+
+--
+-- Name: refresh_tokens refresh_tokens_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.refresh_tokens
+    ADD CONSTRAINT refresh_tokens_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: refresh_tokens refresh_tokens_token_hash_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.refresh_tokens
+    ADD CONSTRAINT refresh_tokens_token_hash_key UNIQUE (token_hash);
+
+
+--
+-- Name: role_permissions role_permissions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.role_permissions
+    ADD CONSTRAINT role_permissions_pkey PRIMARY KEY (role_name, permission_name);
+
+
+--
+-- Name: roles roles_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.roles
+    ADD CONSTRAINT roles_pkey PRIMARY KEY (name);
+
+
 --
 -- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -891,19 +1517,59 @@ ALTER TABLE ONLY public.quiz_answers
 
 
 --
--- Name: user_has_tokens user_has_tokens_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: user_identities uq_user_identities_provider_subject; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.user_has_tokens
-    ADD CONSTRAINT user_has_tokens_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.user_identities
+    ADD CONSTRAINT uq_user_identities_provider_subject UNIQUE (provider, subject);
 
 
 --
--- Name: user_has_tokens user_has_tokens_user_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: user_identities uq_user_identities_user_id_provider; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.user_has_tokens
-    ADD CONSTRAINT user_has_tokens_user_id_key UNIQUE (user_id);
+ALTER TABLE ONLY public.user_identities
+    ADD CONSTRAINT uq_user_identities_user_id_provider UNIQUE (user_id, provider);
+
+
+--
+-- Name: user_identities user_identities_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_identities
+    ADD CONSTRAINT user_identities_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: user_mfa user_mfa_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_mfa
+    ADD CONSTRAINT user_mfa_pkey PRIMARY KEY (user_id);
+
+
+--
+-- Name: user_mfa_recovery_codes user_mfa_recovery_codes_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_mfa_recovery_codes
+    ADD CONSTRAINT user_mfa_recovery_codes_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: user_password_histories user_password_histories_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_password_histories
+    ADD CONSTRAINT user_password_histories_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: user_sessions user_sessions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_sessions
+    ADD CONSTRAINT user_sessions_pkey PRIMARY KEY (id);
 
 
 --
@@ -971,6 +1637,27 @@ CREATE INDEX idx_mentors_user_id ON public.mentors USING btree (user_id);
 CREATE INDEX idx_quiz_answers_attempt_id ON public.quiz_answers USING btree (attempt_id);
 
 
+--
+-- Name: idx_quiz_attachments_question_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_attachments_question_id ON public.quiz_attachments USING btree (question_id, sort_order);
+
+
+--
+-- Name: idx_quiz_attempt_events_attempt_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_attempt_events_attempt_id ON public.quiz_attempt_events USING btree (attempt_id, occurred_at);
+
+
+--
+-- Name: idx_quiz_attempts_leaderboard; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_attempts_leaderboard ON public.quiz_attempts USING btree (quiz_id, score DESC) WHERE ((status)::text = 'completed'::text);
+
+
 --
 -- Name: idx_quiz_attempts_status; Type: INDEX; Schema: public; Owner: -
 --
@@ -978,6 +1665,13 @@ CREATE INDEX idx_quiz_answers_attempt_id ON public.quiz_answers USING btree (att
 CREATE INDEX idx_quiz_attempts_status ON public.quiz_attempts USING btree (status);
 
 
+--
+-- Name: idx_quiz_attempts_status_expires_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_attempts_status_expires_at ON public.quiz_attempts USING btree (status, expires_at);
+
+
 --
 -- Name: idx_quiz_attempts_user_quiz; Type: INDEX; Schema: public; Owner: -
 --
@@ -985,6 +1679,27 @@ CREATE INDEX idx_quiz_attempts_status ON public.quiz_attempts USING btree (statu
 CREATE INDEX idx_quiz_attempts_user_quiz ON public.quiz_attempts USING btree (user_id, quiz_id);
 
 
+--
+-- Name: idx_quiz_attempts_user_quiz_status; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_attempts_user_quiz_status ON public.quiz_attempts USING btree (user_id, quiz_id, status);
+
+
+--
+-- Name: idx_quiz_attempts_version_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_attempts_version_id ON public.quiz_attempts USING btree (version_id);
+
+
+--
+-- Name: idx_quiz_class_assignments_class_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_class_assignments_class_id ON public.quiz_class_assignments USING btree (class_id);
+
+
 --
 -- Name: idx_quiz_options_question_id; Type: INDEX; Schema: public; Owner: -
 --
@@ -992,6 +1707,13 @@ CREATE INDEX idx_quiz_attempts_user_quiz ON public.quiz_attempts USING btree (us
 CREATE INDEX idx_quiz_options_question_id ON public.quiz_options USING btree (question_id);
 
 
+--
+-- Name: idx_quiz_questions_bank_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_questions_bank_id ON public.quiz_questions USING btree (bank_id);
+
+
 --
 -- Name: idx_quiz_questions_quiz_id; Type: INDEX; Schema: public; Owner: -
 --
@@ -999,6 +1721,20 @@ CREATE INDEX idx_quiz_options_question_id ON public.quiz_options USING btree (qu
 CREATE INDEX idx_quiz_questions_quiz_id ON public.quiz_questions USING btree (quiz_id);
 
 
+--
+-- Name: idx_quiz_questions_tags; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_questions_tags ON public.quiz_questions USING gin (tags);
+
+
+--
+-- Name: idx_quiz_questions_version_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_questions_version_id ON public.quiz_questions USING btree (version_id);
+
+
 --
 -- Name: idx_quiz_quizzes_code; Type: INDEX; Schema: public; Owner: -
 --
@@ -1013,6 +1749,41 @@ CREATE UNIQUE INDEX idx_quiz_quizzes_code ON public.quiz_quizzes USING btree (co
 CREATE INDEX idx_quiz_quizzes_is_active ON public.quiz_quizzes USING btree (is_active);
 
 
+--
+-- Name: idx_quiz_sampling_rules_quiz_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_sampling_rules_quiz_id ON public.quiz_sampling_rules USING btree (quiz_id);
+
+
+--
+-- Name: idx_quiz_sampling_rules_version_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_quiz_sampling_rules_version_id ON public.quiz_sampling_rules USING btree (version_id);
+
+
+--
+-- Name: idx_refresh_tokens_session_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_refresh_tokens_session_id ON public.refresh_tokens USING btree (session_id);
+
+
+--
+-- Name: idx_refresh_tokens_user_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_refresh_tokens_user_id ON public.refresh_tokens USING btree (user_id);
+
+
+--
+-- Name: idx_role_permissions_permission_name; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_role_permissions_permission_name ON public.role_permissions USING btree (permission_name);
+
+
 --
 -- Name: idx_static_assets_url; Type: INDEX; Schema: public; Owner: -
 --
@@ -1042,10 +1813,32 @@ CREATE INDEX idx_students_user_id ON public.students USING btree (user_id);
 
 
 --
--- Name: idx_user_tokens; Type: INDEX; Schema: public; Owner: -
+-- Name: idx_user_mfa_recovery_codes_user_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_user_mfa_recovery_codes_user_id ON public.user_mfa_recovery_codes USING btree (user_id);
+
+
+--
+-- Name: idx_user_password_histories_user_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_user_password_histories_user_id ON public.user_password_histories USING btree (user_id, created_at DESC);
+
+
+--
+-- Name: idx_user_sessions_user_id; Type: INDEX; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_user_tokens ON public.user_has_tokens USING btree (user_id);
+CREATE INDEX idx_user_sessions_user_id ON public.user_sessions USING btree (user_id);
+
+
+--
+-- Name: app_settings fk_app_settings_updated_by; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.app_settings
+    ADD CONSTRAINT fk_app_settings_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id) ON DELETE SET NULL;
 
 
 --
@@ -1112,6 +1905,30 @@ ALTER TABLE ONLY public.quiz_answers
     ADD CONSTRAINT fk_quiz_answers_question_id FOREIGN KEY (question_id) REFERENCES public.quiz_questions(id) ON DELETE CASCADE;
 
 
+--
+-- Name: quiz_attachments fk_quiz_attachments_option_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_attachments
+    ADD CONSTRAINT fk_quiz_attachments_option_id FOREIGN KEY (option_id) REFERENCES public.quiz_options(id) ON DELETE CASCADE;
+
+
+--
+-- Name: quiz_attachments fk_quiz_attachments_question_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_attachments
+    ADD CONSTRAINT fk_quiz_attachments_question_id FOREIGN KEY (question_id) REFERENCES public.quiz_questions(id) ON DELETE CASCADE;
+
+
+--
+-- Name: quiz_attempt_events fk_quiz_attempt_events_attempt_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_attempt_events
+    ADD CONSTRAINT fk_quiz_attempt_events_attempt_id FOREIGN KEY (attempt_id) REFERENCES public.quiz_attempts(id) ON DELETE CASCADE;
+
+
 --
 -- Name: quiz_attempts fk_quiz_attempts_quiz_id; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -1136,6 +1953,54 @@ ALTER TABLE ONLY public.quiz_attempts
     ADD CONSTRAINT fk_quiz_attempts_user_id FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
 
 
+--
+-- Name: quiz_attempts fk_quiz_attempts_version_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_attempts
+    ADD CONSTRAINT fk_quiz_attempts_version_id FOREIGN KEY (version_id) REFERENCES public.quiz_versions(id) ON DELETE SET NULL;
+
+
+--
+-- Name: quiz_certificates fk_quiz_certificates_attempt_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_certificates
+    ADD CONSTRAINT fk_quiz_certificates_attempt_id FOREIGN KEY (attempt_id) REFERENCES public.quiz_attempts(id) ON DELETE SET NULL;
+
+
+--
+-- Name: quiz_certificates fk_quiz_certificates_quiz_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_certificates
+    ADD CONSTRAINT fk_quiz_certificates_quiz_id FOREIGN KEY (quiz_id) REFERENCES public.quiz_quizzes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: quiz_certificates fk_quiz_certificates_user_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_certificates
+    ADD CONSTRAINT fk_quiz_certificates_user_id FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: quiz_class_assignments fk_quiz_class_assignments_class; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_class_assignments
+    ADD CONSTRAINT fk_quiz_class_assignments_class FOREIGN KEY (class_id) REFERENCES public.classes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: quiz_class_assignments fk_quiz_class_assignments_quiz; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_class_assignments
+    ADD CONSTRAINT fk_quiz_class_assignments_quiz FOREIGN KEY (quiz_id) REFERENCES public.quiz_quizzes(id) ON DELETE CASCADE;
+
+
 --
 -- Name: quiz_options fk_quiz_options_question_id; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -1144,6 +2009,14 @@ ALTER TABLE ONLY public.quiz_options
     ADD CONSTRAINT fk_quiz_options_question_id FOREIGN KEY (question_id) REFERENCES public.quiz_questions(id) ON DELETE CASCADE;
 
 
+--
+-- Name: quiz_questions fk_quiz_questions_bank_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_questions
+    ADD CONSTRAINT fk_quiz_questions_bank_id FOREIGN KEY (bank_id) REFERENCES public.quiz_question_banks(id) ON DELETE CASCADE;
+
+
 --
 -- Name: quiz_questions fk_quiz_questions_quiz_id; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -1152,6 +2025,102 @@ ALTER TABLE ONLY public.quiz_questions
     ADD CONSTRAINT fk_quiz_questions_quiz_id FOREIGN KEY (quiz_id) REFERENCES public.quiz_quizzes(id) ON DELETE CASCADE;
 
 
+--
+-- Name: quiz_questions fk_quiz_questions_version_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_questions
+    ADD CONSTRAINT fk_quiz_questions_version_id FOREIGN KEY (version_id) REFERENCES public.quiz_versions(id) ON DELETE CASCADE;
+
+
+--
+-- Name: quiz_quizzes fk_quiz_quizzes_published_version_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_quizzes
+    ADD CONSTRAINT fk_quiz_quizzes_published_version_id FOREIGN KEY (published_version_id) REFERENCES public.quiz_versions(id) ON DELETE SET NULL;
+
+
+--
+-- Name: quiz_sampling_rules fk_quiz_sampling_rules_bank_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_sampling_rules
+    ADD CONSTRAINT fk_quiz_sampling_rules_bank_id FOREIGN KEY (bank_id) REFERENCES public.quiz_question_banks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: quiz_sampling_rules fk_quiz_sampling_rules_quiz_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_sampling_rules
+    ADD CONSTRAINT fk_quiz_sampling_rules_quiz_id FOREIGN KEY (quiz_id) REFERENCES public.quiz_quizzes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: quiz_sampling_rules fk_quiz_sampling_rules_version_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_sampling_rules
+    ADD CONSTRAINT fk_quiz_sampling_rules_version_id FOREIGN KEY (version_id) REFERENCES public.quiz_versions(id) ON DELETE CASCADE;
+
+
+--
+-- Name: quiz_versions fk_quiz_versions_published_by; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_versions
+    ADD CONSTRAINT fk_quiz_versions_published_by FOREIGN KEY (published_by) REFERENCES public.users(id) ON DELETE SET NULL;
+
+
+--
+-- Name: quiz_versions fk_quiz_versions_quiz_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.quiz_versions
+    ADD CONSTRAINT fk_quiz_versions_quiz_id FOREIGN KEY (quiz_id) REFERENCES public.quiz_quizzes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: refresh_tokens fk_refresh_tokens_parent_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.refresh_tokens
+    ADD CONSTRAINT fk_refresh_tokens_parent_id FOREIGN KEY (parent_id) REFERENCES public.refresh_tokens(id) ON DELETE SET NULL;
+
+
+--
+-- Name: refresh_tokens fk_refresh_tokens_session_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.refresh_tokens
+    ADD CONSTRAINT fk_refresh_tokens_session_id FOREIGN KEY (session_id) REFERENCES public.user_sessions(id) ON DELETE CASCADE;
+
+
+--
+-- Name: refresh_tokens fk_refresh_tokens_user_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.refresh_tokens
+    ADD CONSTRAINT fk_refresh_tokens_user_id FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: role_permissions fk_role_permissions_permission_name; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.role_permissions
+    ADD CONSTRAINT fk_role_permissions_permission_name FOREIGN KEY (permission_name) REFERENCES public.permissions(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
+--
+-- Name: role_permissions fk_role_permissions_role_name; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.role_permissions
+    ADD CONSTRAINT fk_role_permissions_role_name FOREIGN KEY (role_name) REFERENCES public.roles(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
 --
 -- Name: meeting_sessions fk_student_mt_sessions; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -1185,11 +2154,51 @@ ALTER TABLE ONLY public.students
 
 
 --
--- Name: user_has_tokens fk_user_tokens; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: user_identities fk_user_identities_user_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_identities
+    ADD CONSTRAINT fk_user_identities_user_id FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: user_mfa_recovery_codes fk_user_mfa_recovery_codes_user_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_mfa_recovery_codes
+    ADD CONSTRAINT fk_user_mfa_recovery_codes_user_id FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: user_mfa fk_user_mfa_user_id; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.user_has_tokens
-    ADD CONSTRAINT fk_user_tokens FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+ALTER TABLE ONLY public.user_mfa
+    ADD CONSTRAINT fk_user_mfa_user_id FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: user_password_histories fk_user_password_histories_user_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_password_histories
+    ADD CONSTRAINT fk_user_password_histories_user_id FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: user_sessions fk_user_sessions_user_id; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_sessions
+    ADD CONSTRAINT fk_user_sessions_user_id FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+--
+-- Name: users fk_users_role; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.users
+    ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES public.roles(name) ON UPDATE CASCADE;
 
 
 --
@@ -1224,4 +2233,23 @@ INSERT INTO public.schema_migrations (version) VALUES
     ('20260403144400'),
     ('20260403145100'),
     ('20260403160200'),
-    ('20260404000001');
+    ('20260404000001'),
+    ('20261018090000'),
+    ('20261018091000'),
+    ('20261018092000'),
+    ('20261018093000'),
+    ('20261018094000'),
+    ('20261018095000'),
+    ('20261018100000'),
+    ('20261018101000'),
+    ('20261018102000'),
+    ('20261018103000'),
+    ('20261018104000'),
+    ('20261018105000'),
+    ('20261018106000'),
+    ('20261018107000'),
+    ('20261018108000'),
+    ('20261018109000'),
+    ('20261018110000'),
+    ('20261018111000'),
+    ('20261018112000');
@@ -8,13 +8,18 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// JwtManager signs tokens with one active key and verifies them with any of
+// its keys, picked by the token's "kid" header. Keeping retired keys around
+// for verification lets the signing key rotate without logging everyone out.
 type JwtManager struct {
-	secret []byte
+	active *SigningKey
+	keys   map[string]*SigningKey
 }
 
 type Payload struct {
-	UserID uint   `json:"user_id"`
-	Role   string `json:"role"`
+	UserID    uint   `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID string `json:"session_id,omitempty"` // login session the token belongs to
 }
 
 type AuthToken struct {
@@ -22,12 +27,32 @@ type AuthToken struct {
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
+// NewJwtManager returns a manager with a single HS256 key.
 func NewJwtManager(secret string) *JwtManager {
+	key := NewHMACKey(DefaultKeyID, []byte(secret))
 	return &JwtManager{
-		secret: []byte(secret),
+		active: key,
+		keys:   map[string]*SigningKey{key.ID: key},
 	}
 }
 
+// NewJwtManagerWithKeys returns a manager that signs with active and also
+// accepts tokens signed by the previous keys.
+func NewJwtManagerWithKeys(active *SigningKey, previous ...*SigningKey) (*JwtManager, error) {
+	if active == nil || !active.CanSign() {
+		return nil, errors.New("the active key must be able to sign")
+	}
+
+	keys := map[string]*SigningKey{active.ID: active}
+	for _, key := range previous {
+		if _, exists := keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		keys[key.ID] = key
+	}
+	return &JwtManager{active: active, keys: keys}, nil
+}
+
 func (j *JwtManager) GenerateToken(payload Payload, expiry time.Time) (*AuthToken, error) {
 	jwtClaims := jwt.MapClaims{
 		"payload": payload,
@@ -35,8 +60,9 @@ func (j *JwtManager) GenerateToken(payload Payload, expiry time.Time) (*AuthToke
 		"iat":     jwt.NewNumericDate(time.Now()),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
-	signedToken, err := token.SignedString(j.secret)
+	token := jwt.NewWithClaims(j.active.method(), jwtClaims)
+	token.Header["kid"] = j.active.ID
+	signedToken, err := token.SignedString(j.active.signKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -51,10 +77,20 @@ func (j *JwtManager) GenerateToken(payload Payload, expiry time.Time) (*AuthToke
 
 func (j *JwtManager) ValidateToken(token string) (jwt.MapClaims, error) {
 	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		// Token lama belum membawa kid dan ditandatangani dengan kunci default.
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			kid = DefaultKeyID
+		}
+
+		key, ok := j.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if token.Method.Alg() != key.Algorithm {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return j.secret, nil
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
@@ -72,3 +108,23 @@ func (j *JwtManager) ValidateToken(token string) (jwt.MapClaims, error) {
 
 	return claims, nil
 }
+
+// JWKS returns the public keys other services need to verify tokens. HMAC
+// keys are shared secrets and are never published.
+func (j *JwtManager) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(j.keys))}
+
+	// Kunci aktif lebih dulu, sisanya sesuai urutan kid agar respons stabil.
+	if jwk, ok := j.active.JWK(); ok {
+		set.Keys = append(set.Keys, jwk)
+	}
+	for _, id := range sortedKeyIDs(j.keys) {
+		if id == j.active.ID {
+			continue
+		}
+		if jwk, ok := j.keys[id].JWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
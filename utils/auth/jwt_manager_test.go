@@ -1 +1,130 @@
 package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/studio-senkou/lentera-cendekia-be/utils/auth"
+)
+
+func TestRotatedKeyStillVerifies(t *testing.T) {
+	old := NewJwtManager("old-secret")
+	token, err := old.GenerateToken(Payload{UserID: 1, Role: "user"}, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	manager, err := NewJwtManagerWithKeys(
+		NewHMACKey("2026-10", []byte("new-secret")),
+		NewHMACKey(DefaultKeyID, []byte("old-secret")),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.ValidateToken(token.Token); err != nil {
+		t.Fatalf("expected the token of the previous key to verify: %v", err)
+	}
+
+	fresh, err := manager.GenerateToken(Payload{UserID: 1, Role: "user"}, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := old.ValidateToken(fresh.Token); err == nil {
+		t.Fatal("expected a token of an unknown kid to be rejected")
+	}
+}
+
+func TestTokenWithoutKidUsesDefaultKey(t *testing.T) {
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	})
+	signed, err := legacy.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := NewJwtManager("secret").ValidateToken(signed); err != nil {
+		t.Fatalf("expected a token without kid to verify: %v", err)
+	}
+}
+
+func TestAsymmetricKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		alg     string
+		private any
+		kty     string
+	}{
+		{AlgRS256, rsaKey, "RSA"},
+		{AlgEdDSA, edKey, "OKP"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.alg, func(t *testing.T) {
+			der, err := x509.MarshalPKCS8PrivateKey(tc.private)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			key, err := ParseSigningKey("k1", tc.alg, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			manager, err := NewJwtManagerWithKeys(key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			token, err := manager.GenerateToken(Payload{UserID: 7, Role: "admin"}, time.Now().Add(time.Minute))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := manager.ValidateToken(token.Token); err != nil {
+				t.Fatalf("expected the token to verify: %v", err)
+			}
+
+			set := manager.JWKS()
+			if len(set.Keys) != 1 || set.Keys[0].Kid != "k1" || set.Keys[0].Kty != tc.kty {
+				t.Fatalf("unexpected JWKS: %+v", set)
+			}
+		})
+	}
+}
+
+func TestJWKSOmitsHMACKeys(t *testing.T) {
+	if keys := NewJwtManager("secret").JWKS().Keys; len(keys) != 0 {
+		t.Fatalf("expected no published keys, got %d", len(keys))
+	}
+}
+
+func TestVerificationOnlyKeyCannotBeActive(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(edKey.Public())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	key, err := ParseSigningKey("k1", AlgEdDSA, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := NewJwtManagerWithKeys(key); err == nil {
+		t.Fatal("expected a public key to be refused as the signing key")
+	}
+}
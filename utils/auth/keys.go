@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
+)
+
+var (
+	jwtManager     *JwtManager
+	jwtManagerOnce sync.Once
+)
+
+// GetJwtManager returns the JwtManager configured from the environment. It
+// panics when the keys are misconfigured, like a missing .env does.
+func GetJwtManager() *JwtManager {
+	jwtManagerOnce.Do(func() {
+		manager, err := LoadJwtManager()
+		if err != nil {
+			panic("Error loading JWT keys: " + err.Error())
+		}
+		jwtManager = manager
+	})
+	return jwtManager
+}
+
+// LoadJwtManager reads the signing keys from the environment:
+//
+//   - JWT_SIGNING_KEY_ID and JWT_SIGNING_ALG name the active key, "default"
+//     and HS256 unless set.
+//   - JWT_SIGNING_KEY is the active key. For HS256 it is the secret and falls
+//     back to AUTH_SECRET; for RS256 and EdDSA it is a PEM file path or the
+//     PEM itself.
+//   - JWT_VERIFICATION_KEYS lists retired keys that are still accepted, as
+//     comma separated "kid:alg:value" entries with the value read the same way.
+func LoadJwtManager() (*JwtManager, error) {
+	id := app.GetEnv("JWT_SIGNING_KEY_ID", DefaultKeyID)
+	algorithm := app.GetEnv("JWT_SIGNING_ALG", AlgHS256)
+
+	value := app.GetEnv("JWT_SIGNING_KEY", "")
+	if value == "" && algorithm == AlgHS256 {
+		value = app.GetEnv("AUTH_SECRET", "")
+	}
+
+	active, err := loadSigningKey(id, algorithm, value)
+	if err != nil {
+		return nil, fmt.Errorf("JWT_SIGNING_KEY: %w", err)
+	}
+
+	var previous []*SigningKey
+	for _, entry := range strings.Split(app.GetEnv("JWT_VERIFICATION_KEYS", ""), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("JWT_VERIFICATION_KEYS: entry %q is not kid:alg:value", entry)
+		}
+
+		key, err := loadSigningKey(parts[0], parts[1], parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("JWT_VERIFICATION_KEYS: %w", err)
+		}
+		previous = append(previous, key)
+	}
+
+	return NewJwtManagerWithKeys(active, previous...)
+}
+
+// loadSigningKey membaca nilai kunci; untuk RS256/EdDSA nilai yang bukan PEM
+// dianggap path file berisi PEM.
+func loadSigningKey(id, algorithm, value string) (*SigningKey, error) {
+	if algorithm != AlgHS256 && value != "" && !strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		pem, err := os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %q: %w", id, err)
+		}
+		return ParseSigningKey(id, algorithm, pem)
+	}
+	return ParseSigningKey(id, algorithm, []byte(value))
+}
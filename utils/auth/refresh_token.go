@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
+)
+
+// AccessTokenTTL is how long an access token is valid, from ACCESS_TOKEN_TTL.
+// Revoking a login session ends its access tokens at once, since every request
+// checks the session; they are still kept short-lived so a leaked token stops
+// working soon even when nobody notices and revokes its session.
+func AccessTokenTTL() time.Duration {
+	return envDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+}
+
+// RefreshTokenTTL is how long a refresh token may go unused before the login
+// expires, from REFRESH_TOKEN_TTL.
+func RefreshTokenTTL() time.Duration {
+	return envDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(app.GetEnv(key, fallback.String()))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// GenerateRefreshToken returns a new opaque refresh token and the hash to
+// store in its place. The token itself is only ever handed to the client.
+func GenerateRefreshToken() (token, hash string, err error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(bytes)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the hex SHA-256 of a refresh token. Refresh tokens
+// carry 256 bits of randomness, so a fast unsalted hash is enough.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,28 @@
+package auth_test
+
+import (
+	"testing"
+
+	. "github.com/studio-senkou/lentera-cendekia-be/utils/auth"
+)
+
+func TestGenerateRefreshToken(t *testing.T) {
+	token, hash, err := GenerateRefreshToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hash) != 64 {
+		t.Fatalf("expected a 64 character hash, got %d", len(hash))
+	}
+	if HashRefreshToken(token) != hash {
+		t.Fatal("expected the hash to match the token")
+	}
+
+	other, _, err := GenerateRefreshToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other == token {
+		t.Fatal("expected distinct tokens")
+	}
+}
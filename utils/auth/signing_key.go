@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	// DefaultKeyID is the kid of the key made from AUTH_SECRET. Tokens issued
+	// before keys had ids are verified with it.
+	DefaultKeyID = "default"
+)
+
+// SigningKey is one key of a JwtManager. Keys parsed from a public key can
+// only verify tokens.
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	signKey   any // []byte, *rsa.PrivateKey or ed25519.PrivateKey; nil when verification-only
+	verifyKey any // []byte, *rsa.PublicKey or ed25519.PublicKey
+}
+
+// JWK is a public key in JSON Web Key form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at the JWKS endpoint.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewHMACKey(id string, secret []byte) *SigningKey {
+	return &SigningKey{ID: id, Algorithm: AlgHS256, signKey: secret, verifyKey: secret}
+}
+
+// ParseSigningKey builds a key from its configured value: the secret for
+// HS256, or a PEM encoded private or public key for RS256 and EdDSA.
+func ParseSigningKey(id, algorithm string, value []byte) (*SigningKey, error) {
+	if id == "" {
+		return nil, fmt.Errorf("key id is required")
+	}
+	if len(value) == 0 {
+		return nil, fmt.Errorf("key %q is empty", id)
+	}
+
+	switch algorithm {
+	case AlgHS256:
+		return NewHMACKey(id, value), nil
+	case AlgRS256:
+		if private, err := jwt.ParseRSAPrivateKeyFromPEM(value); err == nil {
+			return &SigningKey{ID: id, Algorithm: algorithm, signKey: private, verifyKey: &private.PublicKey}, nil
+		}
+		public, err := jwt.ParseRSAPublicKeyFromPEM(value)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not a PEM encoded RSA key: %w", id, err)
+		}
+		return &SigningKey{ID: id, Algorithm: algorithm, verifyKey: public}, nil
+	case AlgEdDSA:
+		if private, err := jwt.ParseEdPrivateKeyFromPEM(value); err == nil {
+			if edPrivate, ok := private.(ed25519.PrivateKey); ok {
+				return &SigningKey{ID: id, Algorithm: algorithm, signKey: edPrivate, verifyKey: edPrivate.Public()}, nil
+			}
+		}
+		public, err := jwt.ParseEdPublicKeyFromPEM(value)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not a PEM encoded Ed25519 key: %w", id, err)
+		}
+		return &SigningKey{ID: id, Algorithm: algorithm, verifyKey: public}, nil
+	default:
+		return nil, fmt.Errorf("key %q has unsupported algorithm %q", id, algorithm)
+	}
+}
+
+func (k *SigningKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// JWK returns the public part of the key. It reports false for HMAC keys.
+func (k *SigningKey) JWK() (JWK, bool) {
+	switch public := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: k.ID,
+			Use: "sig",
+			Alg: k.Algorithm,
+			N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: k.ID,
+			Use: "sig",
+			Alg: k.Algorithm,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(public),
+		}, true
+	default:
+		return JWK{}, false
+	}
+}
+
+func sortedKeyIDs(keys map[string]*SigningKey) []string {
+	ids := make([]string, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
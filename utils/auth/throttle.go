@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/cache"
+)
+
+// Limiter slows down guessing on an endpoint. Each failure is counted per key
+// (the client IP and the email it targets); once a key has used up its free
+// attempts it is blocked for a delay that doubles with every further failure.
+// An IP can stand for many users behind one NAT, so IP keys get their own,
+// larger budget. Counters live in Redis, so without Redis the limiter lets
+// everything through.
+type Limiter struct {
+	Scope          string
+	FreeAttempts   int
+	IPFreeAttempts int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	Window         time.Duration // how long failures are remembered
+}
+
+// NewLimiter returns a limiter for the scope configured from THROTTLE_*.
+// Scopes keep the counters of different endpoints apart.
+func NewLimiter(scope string) *Limiter {
+	return &Limiter{
+		Scope:          scope,
+		FreeAttempts:   envInt("THROTTLE_FREE_ATTEMPTS", 5),
+		IPFreeAttempts: envInt("THROTTLE_IP_FREE_ATTEMPTS", 50),
+		BaseDelay:      envDuration("THROTTLE_BASE_DELAY", time.Second),
+		MaxDelay:       envDuration("THROTTLE_MAX_DELAY", 15*time.Minute),
+		Window:         envDuration("THROTTLE_WINDOW", time.Hour),
+	}
+}
+
+// ThrottleKeys returns the limiter keys of a request from ip for email. The
+// email is left out when the request did not name one.
+func ThrottleKeys(ip, email string) []string {
+	keys := []string{"ip:" + ip}
+	if email = normalizeEmail(email); email != "" {
+		keys = append(keys, "email:"+email)
+	}
+	return keys
+}
+
+// Delay returns how long a key is blocked after its nth failure.
+func (l *Limiter) Delay(key string, failures int) time.Duration {
+	free := l.FreeAttempts
+	if strings.HasPrefix(key, "ip:") {
+		free = l.IPFreeAttempts
+	}
+	if failures <= free {
+		return 0
+	}
+
+	delay := l.BaseDelay
+	for i := free + 1; i < failures && delay < l.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, l.MaxDelay)
+}
+
+// Wait returns how long the caller must wait before trying again, the
+// longest block of any of the keys.
+func (l *Limiter) Wait(ctx context.Context, keys ...string) (time.Duration, error) {
+	if cache.RedisClient == nil {
+		return 0, nil
+	}
+
+	var wait time.Duration
+	for _, key := range keys {
+		ttl, err := cache.TTL(ctx, l.key(key, "blocked"))
+		if err != nil {
+			return 0, err
+		}
+		wait = max(wait, ttl)
+	}
+	return wait, nil
+}
+
+// Fail records a failed attempt for every key and blocks the keys that have
+// run out of free attempts.
+func (l *Limiter) Fail(ctx context.Context, keys ...string) error {
+	if cache.RedisClient == nil {
+		return nil
+	}
+
+	for _, key := range keys {
+		failures, err := cache.Increment(ctx, l.key(key, "failures"), l.Window)
+		if err != nil {
+			return err
+		}
+		if delay := l.Delay(key, int(failures)); delay > 0 {
+			if err := cache.Set(ctx, l.key(key, "blocked"), true, delay); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Reset forgets the failures of the keys, e.g. after a successful login.
+func (l *Limiter) Reset(ctx context.Context, keys ...string) error {
+	if cache.RedisClient == nil {
+		return nil
+	}
+
+	for _, key := range keys {
+		if err := cache.Delete(ctx, l.key(key, "failures")); err != nil {
+			return err
+		}
+		if err := cache.Delete(ctx, l.key(key, "blocked")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Limiter) key(key, suffix string) string {
+	return "throttle:" + l.Scope + ":" + key + ":" + suffix
+}
+
+// AccountLockout locks an account after too many wrong passwords, no matter
+// which IPs they came from. Like Limiter it is a no-op without Redis.
+type AccountLockout struct {
+	Threshold int
+	Duration  time.Duration
+	Window    time.Duration // how long failures are remembered
+}
+
+// NewAccountLockout returns the lockout configured from LOGIN_LOCKOUT_*.
+func NewAccountLockout() *AccountLockout {
+	return &AccountLockout{
+		Threshold: envInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+		Duration:  envDuration("LOGIN_LOCKOUT_DURATION", 30*time.Minute),
+		Window:    envDuration("THROTTLE_WINDOW", time.Hour),
+	}
+}
+
+// LockedFor returns how long the account of email stays locked, or zero.
+func (l *AccountLockout) LockedFor(ctx context.Context, email string) (time.Duration, error) {
+	if cache.RedisClient == nil {
+		return 0, nil
+	}
+	return cache.TTL(ctx, l.key(email, "locked"))
+}
+
+// Fail records a wrong password for the account. It reports true when this
+// failure locked the account, so the owner is notified only once.
+func (l *AccountLockout) Fail(ctx context.Context, email string) (bool, error) {
+	if cache.RedisClient == nil {
+		return false, nil
+	}
+
+	failures, err := cache.Increment(ctx, l.key(email, "failures"), l.Window)
+	if err != nil {
+		return false, err
+	}
+	if int(failures) != l.Threshold {
+		return false, nil
+	}
+
+	if err := cache.Set(ctx, l.key(email, "locked"), true, l.Duration); err != nil {
+		return false, err
+	}
+	return true, cache.Delete(ctx, l.key(email, "failures"))
+}
+
+// Reset forgets the wrong passwords of the account after a successful login.
+func (l *AccountLockout) Reset(ctx context.Context, email string) error {
+	if cache.RedisClient == nil {
+		return nil
+	}
+	return cache.Delete(ctx, l.key(email, "failures"))
+}
+
+// Unlock lifts the lock of the account and clears the limiter blocks on its
+// email in every scope.
+func (l *AccountLockout) Unlock(ctx context.Context, email string) error {
+	if cache.RedisClient == nil {
+		return nil
+	}
+
+	email = normalizeEmail(email)
+	if err := cache.Delete(ctx, l.key(email, "failures")); err != nil {
+		return err
+	}
+	if err := cache.Delete(ctx, l.key(email, "locked")); err != nil {
+		return err
+	}
+	return cache.DeletePattern(ctx, "throttle:*:email:"+escapeGlob(email)+":*")
+}
+
+func (l *AccountLockout) key(email, suffix string) string {
+	return "throttle:lockout:" + normalizeEmail(email) + ":" + suffix
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// escapeGlob meloloskan karakter glob Redis agar email dicocokkan apa adanya.
+func escapeGlob(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(value)
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(app.GetEnv(key, strconv.Itoa(fallback)))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/studio-senkou/lentera-cendekia-be/utils/auth"
+)
+
+func TestLimiterDelay(t *testing.T) {
+	limiter := &Limiter{FreeAttempts: 3, IPFreeAttempts: 20, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	expected := map[int]time.Duration{
+		1:  0,
+		3:  0,
+		4:  time.Second,
+		5:  2 * time.Second,
+		6:  4 * time.Second,
+		7:  8 * time.Second,
+		8:  10 * time.Second,
+		60: 10 * time.Second,
+	}
+	for failures, delay := range expected {
+		if got := limiter.Delay("email:user@example.com", failures); got != delay {
+			t.Errorf("expected %v after %d failures, got %v", delay, failures, got)
+		}
+	}
+
+	ipExpected := map[int]time.Duration{
+		4:  0,
+		20: 0,
+		21: time.Second,
+		22: 2 * time.Second,
+		60: 10 * time.Second,
+	}
+	for failures, delay := range ipExpected {
+		if got := limiter.Delay("ip:10.0.0.1", failures); got != delay {
+			t.Errorf("expected %v after %d failures of an IP, got %v", delay, failures, got)
+		}
+	}
+}
+
+func TestThrottleKeys(t *testing.T) {
+	keys := ThrottleKeys("10.0.0.1", " User@Example.com ")
+	if len(keys) != 2 || keys[0] != "ip:10.0.0.1" || keys[1] != "email:user@example.com" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	if keys := ThrottleKeys("10.0.0.1", ""); len(keys) != 1 {
+		t.Fatalf("expected only the IP key, got %v", keys)
+	}
+}
@@ -0,0 +1,74 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/studio-senkou/lentera-cendekia-be/utils/auth"
+)
+
+// rfc6238Secret is the SHA-1 test key of RFC 6238, base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	expected := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, code := range expected {
+		got, err := TOTPCode(rfc6238Secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != code {
+			t.Errorf("expected %s at %d, got %s", code, unix, got)
+		}
+	}
+}
+
+func TestValidateTOTPAllowsOneStepOfSkew(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Unix(1_800_000_000, 0)
+
+	previous, _ := TOTPCode(secret, now.Add(-30*time.Second))
+	step, ok := ValidateTOTP(secret, previous, now)
+	if !ok || step != now.Unix()/30-1 {
+		t.Fatalf("expected the previous code to match its step, got %d %t", step, ok)
+	}
+
+	stale, _ := TOTPCode(secret, now.Add(-90*time.Second))
+	if _, ok := ValidateTOTP(secret, stale, now); ok {
+		t.Fatal("expected a code three steps old to be rejected")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(codes) != 10 || len(hashes) != len(codes) {
+		t.Fatalf("expected 10 codes and hashes, got %d and %d", len(codes), len(hashes))
+	}
+
+	typed := strings.ToLower(strings.ReplaceAll(codes[0], "-", ""))
+	if HashRecoveryCode(typed) != hashes[0] {
+		t.Fatal("expected the hash to ignore case and dashes")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("Lentera Cendekia", "admin@example.com", rfc6238Secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Lentera%20Cendekia:admin@example.com?") {
+		t.Fatalf("unexpected URI: %s", uri)
+	}
+	if !strings.Contains(uri, "secret="+rfc6238Secret) {
+		t.Fatalf("expected the secret in the URI: %s", uri)
+	}
+}
@@ -35,3 +35,58 @@ func Exists(ctx context.Context, key string) (bool, error) {
 	}
 	return exists > 0, nil
 }
+
+// DeletePattern removes every key matching the glob pattern. Keys are found
+// with SCAN so large keyspaces do not block Redis.
+func DeletePattern(ctx context.Context, pattern string) error {
+	iter := RedisClient.Scan(ctx, 0, pattern, 100).Iterator()
+	keys := make([]string, 0)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return RedisClient.Del(ctx, keys...).Err()
+}
+
+// Increment adds one to the counter at key and returns the new value. The
+// counter expires after expire, counted from its first increment.
+func Increment(ctx context.Context, key string, expire time.Duration) (int64, error) {
+	value, err := RedisClient.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if value == 1 {
+		if err := RedisClient.Expire(ctx, key, expire).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return value, nil
+}
+
+// TTL returns how long until key expires, or zero when it does not exist.
+func TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := RedisClient.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// GetDelete reads the value at key into dest and removes it in one step, so
+// a value can be consumed only once.
+func GetDelete(ctx context.Context, key string, dest any) error {
+	value, err := RedisClient.GetDel(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(value), dest)
+}
@@ -0,0 +1,196 @@
+// Package certificate renders PDF certificates from a JSON layout template.
+// A layout lists the shapes and text lines of one page; text may use Go
+// template placeholders such as {{.StudentName}} that are filled per
+// certificate, so designers can change the certificate without touching code.
+package certificate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	ElementText = "text"
+	ElementLine = "line"
+	ElementRect = "rect"
+)
+
+// Layout is one certificate page. Coordinates are in millimetres from the
+// top left corner.
+type Layout struct {
+	Orientation string    `json:"orientation"` // "L" or "P"
+	Size        string    `json:"size"`        // e.g. "A4"
+	Title       string    `json:"title"`
+	Elements    []Element `json:"elements"`
+}
+
+// Element is a text line, a line or a rectangle on the page. Text elements
+// are centred, left or right aligned within W starting at X; lines run from
+// (X, Y) to (X2, Y2).
+type Element struct {
+	Type      string  `json:"type"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	X2        float64 `json:"x2,omitempty"`
+	Y2        float64 `json:"y2,omitempty"`
+	W         float64 `json:"w,omitempty"`
+	H         float64 `json:"h,omitempty"`
+	Text      string  `json:"text,omitempty"`
+	Font      string  `json:"font,omitempty"`
+	Style     string  `json:"style,omitempty"` // "", "B", "I" or "BI"
+	Size      float64 `json:"size,omitempty"`
+	Align     string  `json:"align,omitempty"` // "L", "C" or "R"
+	Color     string  `json:"color,omitempty"` // "#rrggbb"
+	Fill      string  `json:"fill,omitempty"`  // "#rrggbb", rectangles only
+	LineWidth float64 `json:"line_width,omitempty"`
+}
+
+// LoadLayout reads a layout template from a JSON file.
+func LoadLayout(path string) (*Layout, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate template: %w", err)
+	}
+
+	layout := new(Layout)
+	if err := json.Unmarshal(content, layout); err != nil {
+		return nil, fmt.Errorf("failed to parse certificate template: %w", err)
+	}
+	return layout, nil
+}
+
+// RenderFile renders the layout template at path with data and returns the
+// PDF.
+func RenderFile(path string, data any) ([]byte, error) {
+	layout, err := LoadLayout(path)
+	if err != nil {
+		return nil, err
+	}
+	return layout.Render(data)
+}
+
+// Render fills the text placeholders with data and returns the PDF.
+func (l *Layout) Render(data any) ([]byte, error) {
+	orientation := l.Orientation
+	if orientation == "" {
+		orientation = "L"
+	}
+	size := l.Size
+	if size == "" {
+		size = "A4"
+	}
+
+	pdf := fpdf.New(orientation, "mm", size, "")
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetCreator("Lentera Cendekia", true)
+	if l.Title != "" {
+		pdf.SetTitle(l.Title, true)
+	}
+	pdf.AddPage()
+
+	// The core fonts only cover cp1252, which is enough for Indonesian names.
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for i, el := range l.Elements {
+		if el.LineWidth > 0 {
+			pdf.SetLineWidth(el.LineWidth)
+		}
+		r, g, b, err := parseColor(el.Color)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+
+		switch el.Type {
+		case ElementText:
+			text, err := executeText(el.Text, data)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			font := el.Font
+			if font == "" {
+				font = "Helvetica"
+			}
+			fontSize := el.Size
+			if fontSize == 0 {
+				fontSize = 12
+			}
+			align := el.Align
+			if align == "" {
+				align = "C"
+			}
+			height := el.H
+			if height == 0 {
+				height = fontSize * 0.5
+			}
+			pdf.SetFont(font, el.Style, fontSize)
+			pdf.SetTextColor(r, g, b)
+			pdf.SetXY(el.X, el.Y)
+			pdf.CellFormat(el.W, height, translate(text), "", 0, align+"M", false, 0, "")
+		case ElementLine:
+			pdf.SetDrawColor(r, g, b)
+			pdf.Line(el.X, el.Y, el.X2, el.Y2)
+		case ElementRect:
+			pdf.SetDrawColor(r, g, b)
+			style := "D"
+			if el.Fill != "" {
+				fr, fg, fb, err := parseColor(el.Fill)
+				if err != nil {
+					return nil, fmt.Errorf("element %d: %w", i, err)
+				}
+				pdf.SetFillColor(fr, fg, fb)
+				style = "F"
+				if el.LineWidth > 0 {
+					style = "FD"
+				}
+			}
+			pdf.Rect(el.X, el.Y, el.W, el.H, style)
+		default:
+			return nil, fmt.Errorf("element %d: unknown type %q", i, el.Type)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render certificate: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func executeText(text string, data any) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("text").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid text template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to fill text template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// parseColor reads a "#rrggbb" color. An empty color is black.
+func parseColor(color string) (int, int, int, error) {
+	if color == "" {
+		return 0, 0, 0, nil
+	}
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 {
+		return 0, 0, 0, fmt.Errorf("invalid color %q", color)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid color %q", color)
+	}
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff), nil
+}
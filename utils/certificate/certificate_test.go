@@ -0,0 +1,56 @@
+package certificate_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/studio-senkou/lentera-cendekia-be/utils/certificate"
+)
+
+type certificateData struct {
+	StudentName string
+	QuizTitle   string
+	Score       string
+	IssuedDate  string
+	Code        string
+	VerifyURL   string
+}
+
+func TestRenderQuizCertificateTemplate(t *testing.T) {
+	pdf, err := RenderFile("../../templates/certificates/quiz_certificate.json", certificateData{
+		StudentName: "Siti Nurhaliza",
+		QuizTitle:   "Tryout Matematika",
+		Score:       "87.50",
+		IssuedDate:  "18 Oktober 2026",
+		Code:        "LC-7QX2M9KD",
+		VerifyURL:   "https://portal.lenteracendekia.id/certificates/LC-7QX2M9KD",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Fatalf("expected a PDF, got %q", pdf[:min(len(pdf), 16)])
+	}
+}
+
+func TestRenderRejectsUnknownPlaceholder(t *testing.T) {
+	layout := &Layout{Elements: []Element{
+		{Type: ElementText, X: 0, Y: 10, W: 297, Text: "{{.Missing}}"},
+	}}
+
+	_, err := layout.Render(map[string]string{"StudentName": "Budi"})
+	if err == nil || !strings.Contains(err.Error(), "element 0") {
+		t.Fatalf("expected a placeholder error on element 0, got %v", err)
+	}
+}
+
+func TestRenderRejectsInvalidColor(t *testing.T) {
+	layout := &Layout{Elements: []Element{
+		{Type: ElementRect, X: 10, Y: 10, W: 50, H: 20, Color: "blue"},
+	}}
+
+	if _, err := layout.Render(nil); err == nil {
+		t.Fatal("expected an invalid color error")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"strconv"
 
 	"github.com/studio-senkou/lentera-cendekia-be/utils/app"
@@ -13,11 +14,18 @@ import (
 )
 
 type Mail struct {
-	To       string
-	Subject  string
-	Body     string
-	HTMLBody string
-	IsHTML   bool
+	To          string
+	Subject     string
+	Body        string
+	HTMLBody    string
+	IsHTML      bool
+	Attachments []Attachment
+}
+
+// Attachment is a file sent along with the mail from memory.
+type Attachment struct {
+	Filename string
+	Data     []byte
 }
 
 func NewMail(to, subject, body string) *Mail {
@@ -52,6 +60,12 @@ func NewMailFromTemplate(to, subject, templatePath string, data interface{}) (*M
 	}, nil
 }
 
+// Attach adds an in-memory file to the mail.
+func (m *Mail) Attach(filename string, data []byte) *Mail {
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, Data: data})
+	return m
+}
+
 func parseTemplate(templatePath string, data interface{}) (string, error) {
 	tmpl, err := template.ParseFiles(templatePath)
 	if err != nil {
@@ -112,6 +126,14 @@ func (m *Mail) Send() error {
 		mail.SetBody("text/plain", m.Body)
 	}
 
+	for _, attachment := range m.Attachments {
+		data := attachment.Data
+		mail.Attach(attachment.Filename, gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		}))
+	}
+
 	if err := d.DialAndSend(mail); err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
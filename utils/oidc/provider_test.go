@@ -0,0 +1,192 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/studio-senkou/lentera-cendekia-be/utils/oidc"
+	"github.com/studio-senkou/lentera-cendekia-be/utils/oidc/oidctest"
+)
+
+const redirectURL = "http://localhost:3000/auth/google/callback"
+
+var student = oidctest.User{
+	Subject:       "1234567890",
+	Email:         "student@example.com",
+	EmailVerified: true,
+	Name:          "Student",
+}
+
+func newTestProvider(t *testing.T) (*oidctest.Server, *Provider) {
+	t.Helper()
+	server, err := oidctest.NewServer("client-id", "client-secret", student)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(server.Close)
+	return server, NewProvider("google", server.URL, "client-id", "client-secret", redirectURL)
+}
+
+// authorize follows the authorization URL like a browser and returns the
+// code and state the provider redirected back with.
+func authorize(t *testing.T, authURL string) (code, state string) {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected a redirect, got %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestProviderLoginFlow(t *testing.T) {
+	_, provider := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, err := GenerateCodeVerifier()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	code, state := authorize(t, authURL)
+	if state != "state-1" {
+		t.Fatalf("expected the state to come back, got %q", state)
+	}
+
+	idToken, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, idToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Subject != student.Subject || claims.Email != student.Email || !claims.EmailVerified {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	if _, err := provider.Exchange(ctx, code, verifier); err == nil {
+		t.Fatal("expected a used code to be rejected")
+	}
+}
+
+func TestProviderRejectsWrongCodeVerifier(t *testing.T) {
+	_, provider := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := GenerateCodeVerifier()
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	code, _ := authorize(t, authURL)
+
+	other, _ := GenerateCodeVerifier()
+	if _, err := provider.Exchange(ctx, code, other); err == nil {
+		t.Fatal("expected the exchange to fail with another verifier")
+	}
+}
+
+func TestVerifyIDTokenRejectsInvalidTokens(t *testing.T) {
+	server, provider := newTestProvider(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   server.URL,
+			"sub":   student.Subject,
+			"aud":   "client-id",
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	tests := map[string]func(jwt.MapClaims){
+		"wrong nonce":     func(c jwt.MapClaims) { c["nonce"] = "other" },
+		"missing nonce":   func(c jwt.MapClaims) { delete(c, "nonce") },
+		"wrong audience":  func(c jwt.MapClaims) { c["aud"] = "other-client" },
+		"wrong issuer":    func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"expired":         func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() },
+		"missing subject": func(c jwt.MapClaims) { delete(c, "sub") },
+		"other azp": func(c jwt.MapClaims) {
+			c["aud"] = []string{"client-id", "other-client"}
+			c["azp"] = "other-client"
+		},
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims := valid()
+			mutate(claims)
+			token, err := server.SignIDToken(claims)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := provider.VerifyIDToken(ctx, token, "nonce"); !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("expected ErrInvalidIDToken, got %v", err)
+			}
+		})
+	}
+
+	token, err := server.SignIDToken(valid())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, token, "nonce"); err != nil {
+		t.Fatalf("expected the valid token to pass, got %v", err)
+	}
+}
+
+func TestVerifyIDTokenRejectsForeignKey(t *testing.T) {
+	server, provider := newTestProvider(t)
+
+	// Another provider signs with another key under the same kid.
+	impostor, err := oidctest.NewProvider(server.URL, "client-id", "client-secret", student)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	token, err := impostor.SignIDToken(jwt.MapClaims{
+		"iss":   server.URL,
+		"sub":   student.Subject,
+		"aud":   "client-id",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": "nonce",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := provider.VerifyIDToken(context.Background(), token, "nonce"); !errors.Is(err, ErrInvalidIDToken) {
+		t.Fatalf("expected ErrInvalidIDToken, got %v", err)
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// RFC 7636 appendix B.
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	if got := CodeChallenge(verifier); got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Fatalf("unexpected code challenge %q", got)
+	}
+}
@@ -175,6 +175,28 @@ func (qs *QueueService) Start() error {
 	return qs.server.Run(mux)
 }
 
+// StartWorker starts the task server and the periodic scheduler without
+// blocking, so the worker can run inside another process such as the HTTP
+// server. Unlike Start it does not install its own signal handling; call Stop
+// to shut both down.
+func (qs *QueueService) StartWorker() error {
+	mux := asynq.NewServeMux()
+	for taskName, handler := range qs.handlers {
+		mux.HandleFunc(taskName, handler)
+	}
+
+	if err := qs.server.Start(mux); err != nil {
+		return fmt.Errorf("failed to start queue server: %w", err)
+	}
+
+	if err := qs.scheduler.Start(); err != nil {
+		qs.server.Shutdown()
+		return fmt.Errorf("failed to start scheduler: %w", err)
+	}
+
+	return nil
+}
+
 func (qs *QueueService) Stop() {
 	qs.scheduler.Shutdown()
 	qs.server.Shutdown()
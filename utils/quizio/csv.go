@@ -0,0 +1,343 @@
+package quizio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSV files hold one question per row and start with a header row. Only
+// question_text is required; every column whose name starts with "option_"
+// is an option, in column order. The correct column depends on the type:
+//
+//	single/multiple choice  option letters or 1-based numbers, e.g. "B" or "A,C"
+//	true_false              true/false (benar/salah also work)
+//	short_answer            accepted answers separated by "|" (one pattern for regex)
+//	numeric                 the answer, with numeric_tolerance in its own column
+//
+// Without a question_type a row with options is single choice, or multiple
+// choice when it lists several correct options, and a row without options is
+// short answer.
+const (
+	csvQuestionText     = "question_text"
+	csvQuestionType     = "question_type"
+	csvCorrect          = "correct"
+	csvPoints           = "points"
+	csvPenaltyPoints    = "penalty_points"
+	csvExplanation      = "explanation"
+	csvTags             = "tags"
+	csvDifficulty       = "difficulty"
+	csvScoringMode      = "scoring_mode"
+	csvMatchMode        = "match_mode"
+	csvNumericTolerance = "numeric_tolerance"
+	csvOptionPrefix     = "option_"
+)
+
+// DecodeCSV reads one question per row. The quiz title and settings are not
+// part of the file and are left empty.
+func DecodeCSV(r io.Reader) (*Document, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("the CSV file is empty")
+		}
+		return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int)
+	var optionColumns []int
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if strings.HasPrefix(name, csvOptionPrefix) {
+			optionColumns = append(optionColumns, i)
+			continue
+		}
+		columns[name] = i
+	}
+	if _, ok := columns[csvQuestionText]; !ok {
+		return nil, nil, fmt.Errorf("the CSV header must have a %s column", csvQuestionText)
+	}
+
+	doc := &Document{Questions: make([]Question, 0)}
+	var rowErrors []RowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		if isBlankRecord(record) {
+			continue
+		}
+
+		q, errs := parseCSVQuestion(line, record, cell, optionColumns)
+		rowErrors = append(rowErrors, errs...)
+		doc.Questions = append(doc.Questions, q)
+	}
+
+	return doc, rowErrors, nil
+}
+
+func parseCSVQuestion(line int, record []string, cell func(string) string, optionColumns []int) (Question, []RowError) {
+	q := Question{
+		Row:          line,
+		QuestionText: cell(csvQuestionText),
+		QuestionType: strings.ToLower(cell(csvQuestionType)),
+		ScoringMode:  strings.ToLower(cell(csvScoringMode)),
+		Explanation:  optionalString(cell(csvExplanation)),
+		Difficulty:   optionalString(strings.ToLower(cell(csvDifficulty))),
+		MatchMode:    optionalString(strings.ToLower(cell(csvMatchMode))),
+	}
+	var rowErrors []RowError
+	fail := func(field, message string) {
+		rowErrors = append(rowErrors, RowError{Row: line, Field: field, Message: message})
+	}
+
+	q.Tags = splitList(cell(csvTags), "|;,")
+
+	numberCell := func(name string, target *float64) {
+		if value := cell(name); value != "" {
+			number, err := parseNumber(value)
+			if err != nil {
+				fail(name, "must be a number")
+				return
+			}
+			*target = number
+		}
+	}
+	numberCell(csvPoints, &q.Points)
+	numberCell(csvPenaltyPoints, &q.PenaltyPoints)
+	numberCell(csvNumericTolerance, &q.NumericTolerance)
+
+	// Remember the column position of every option so letters in the correct
+	// column keep pointing at the right option when some cells are empty.
+	optionAt := make(map[int]int)
+	for position, column := range optionColumns {
+		if column >= len(record) {
+			continue
+		}
+		text := strings.TrimSpace(record[column])
+		if text == "" {
+			continue
+		}
+		optionAt[position] = len(q.Options)
+		q.Options = append(q.Options, Option{OptionText: text})
+	}
+
+	correct := cell(csvCorrect)
+	if q.QuestionType == "" {
+		q.QuestionType = typeShortAnswer
+		if len(q.Options) > 0 {
+			q.QuestionType = typeSingleChoice
+			if len(splitList(correct, ",;|")) > 1 {
+				q.QuestionType = typeMultipleChoice
+			}
+		}
+	}
+
+	switch q.QuestionType {
+	case typeTrueFalse:
+		value, ok := parseTrueFalse(correct)
+		if !ok {
+			fail(csvCorrect, "must be true or false")
+			break
+		}
+		q.CorrectAnswer = &value
+	case typeShortAnswer:
+		// A regex answer may contain "|" itself and already means "any of".
+		if q.MatchMode != nil && *q.MatchMode == "regex" {
+			q.AcceptedAnswers = splitList(correct, "")
+			break
+		}
+		q.AcceptedAnswers = splitList(correct, "|")
+	case typeNumeric:
+		if correct == "" {
+			break
+		}
+		number, err := parseNumber(correct)
+		if err != nil {
+			fail(csvCorrect, "must be a number")
+			break
+		}
+		q.NumericAnswer = &number
+	default:
+		for _, ref := range splitList(correct, ",;|") {
+			position, ok := parseOptionRef(ref)
+			index, exists := optionAt[position]
+			if !ok || !exists {
+				fail(csvCorrect, fmt.Sprintf("%q does not point to a filled option column", ref))
+				continue
+			}
+			q.Options[index].IsCorrect = true
+		}
+	}
+
+	return q, rowErrors
+}
+
+// EncodeCSV writes the questions one per row. Quiz settings and option
+// explanations have no column and are left out.
+func EncodeCSV(w io.Writer, doc *Document) error {
+	maxOptions := 0
+	for _, q := range doc.Questions {
+		if len(q.Options) > maxOptions {
+			maxOptions = len(q.Options)
+		}
+	}
+
+	header := []string{csvQuestionText, csvQuestionType}
+	for i := 0; i < maxOptions; i++ {
+		header = append(header, csvOptionPrefix+optionLetter(i))
+	}
+	header = append(header,
+		csvCorrect, csvPoints, csvPenaltyPoints, csvExplanation, csvTags,
+		csvDifficulty, csvScoringMode, csvMatchMode, csvNumericTolerance,
+	)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, q := range doc.Questions {
+		record := []string{q.QuestionText, q.QuestionType}
+		var correct []string
+		for i := 0; i < maxOptions; i++ {
+			if i < len(q.Options) {
+				record = append(record, q.Options[i].OptionText)
+				if q.Options[i].IsCorrect {
+					correct = append(correct, strings.ToUpper(optionLetter(i)))
+				}
+			} else {
+				record = append(record, "")
+			}
+		}
+
+		switch q.QuestionType {
+		case typeTrueFalse:
+			correct = []string{strconv.FormatBool(q.CorrectAnswer != nil && *q.CorrectAnswer)}
+		case typeShortAnswer:
+			correct = []string{strings.Join(q.AcceptedAnswers, "|")}
+		case typeNumeric:
+			correct = nil
+			if q.NumericAnswer != nil {
+				correct = []string{strconv.FormatFloat(*q.NumericAnswer, 'f', -1, 64)}
+			}
+		}
+
+		record = append(record,
+			strings.Join(correct, ","),
+			formatNumber(q.Points),
+			formatNumber(q.PenaltyPoints),
+			derefString(q.Explanation),
+			strings.Join(q.Tags, "|"),
+			derefString(q.Difficulty),
+			q.ScoringMode,
+			derefString(q.MatchMode),
+			formatNumber(q.NumericTolerance),
+		)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func splitList(value, separators string) []string {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return strings.ContainsRune(separators, r)
+	})
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// parseNumber accepts both "2.5" and the Indonesian spreadsheet style "2,5".
+func parseNumber(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(strings.TrimSpace(value), ",", ".", 1), 64)
+}
+
+func formatNumber(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func parseTrueFalse(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "t", "benar", "b", "1":
+		return true, true
+	case "false", "f", "salah", "s", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// parseOptionRef turns "B" or "2" into the 0-based option position.
+func parseOptionRef(ref string) (int, bool) {
+	ref = strings.ToUpper(strings.TrimSpace(ref))
+	if n, err := strconv.Atoi(ref); err == nil {
+		return n - 1, n >= 1
+	}
+	if len(ref) == 1 && ref[0] >= 'A' && ref[0] <= 'Z' {
+		return int(ref[0] - 'A'), true
+	}
+	return 0, false
+}
+
+func optionLetter(i int) string {
+	if i < 26 {
+		return string(rune('a' + i))
+	}
+	return strconv.Itoa(i + 1)
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,194 @@
+// Package quizio reads and writes whole quizzes in portable formats so they
+// can be authored outside the admin panel: JSON (the admin API shape), CSV
+// (one question per row, for spreadsheets) and Moodle GIFT.
+package quizio
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatGIFT = "gift"
+)
+
+const (
+	typeSingleChoice   = "single_choice"
+	typeMultipleChoice = "multiple_choice"
+	typeTrueFalse      = "true_false"
+	typeShortAnswer    = "short_answer"
+	typeNumeric        = "numeric"
+
+	scoringPartial = "partial"
+)
+
+// Document is a quiz with all its questions. Field names follow the admin API
+// so a JSON export can be edited and imported again.
+type Document struct {
+	Title            string     `json:"title"`
+	Description      *string    `json:"description,omitempty"`
+	PassingScore     int        `json:"passing_score"`
+	TimeLimitMinutes *int       `json:"time_limit_minutes,omitempty"`
+	MaxAttempts      *int       `json:"max_attempts,omitempty"`
+	CooldownMinutes  int        `json:"cooldown_minutes,omitempty"`
+	ScorePolicy      string     `json:"score_policy,omitempty"`
+	ReviewPolicy     string     `json:"review_policy,omitempty"`
+	IsActive         bool       `json:"is_active"`
+	Questions        []Question `json:"questions"`
+}
+
+type Question struct {
+	// Row locates the question in the source file for error messages: the
+	// question number for JSON and the line number for CSV and GIFT.
+	Row int `json:"-"`
+
+	QuestionText     string   `json:"question_text"               validate:"required,min=3"`
+	TextFormat       string   `json:"text_format,omitempty"       validate:"omitempty,oneof=plain markdown latex"`
+	QuestionType     string   `json:"question_type,omitempty"     validate:"omitempty,oneof=single_choice multiple_choice true_false short_answer numeric"`
+	ScoringMode      string   `json:"scoring_mode,omitempty"      validate:"omitempty,oneof=all_or_nothing partial"`
+	MatchMode        *string  `json:"match_mode,omitempty"        validate:"omitempty,oneof=exact case_insensitive regex"`
+	AcceptedAnswers  []string `json:"accepted_answers,omitempty"  validate:"required_if=QuestionType short_answer,omitempty,dive,required"`
+	NumericAnswer    *float64 `json:"numeric_answer,omitempty"    validate:"required_if=QuestionType numeric"`
+	NumericTolerance float64  `json:"numeric_tolerance,omitempty" validate:"omitempty,min=0"`
+	CorrectAnswer    *bool    `json:"correct_answer,omitempty"    validate:"required_if=QuestionType true_false"`
+	Tags             []string `json:"tags,omitempty"              validate:"omitempty,dive,required,max=100"`
+	Difficulty       *string  `json:"difficulty,omitempty"        validate:"omitempty,oneof=easy medium hard"`
+	Explanation      *string  `json:"explanation,omitempty"`
+	Points           float64  `json:"points,omitempty"            validate:"omitempty,gt=0,max=1000"`
+	PenaltyPoints    float64  `json:"penalty_points,omitempty"    validate:"omitempty,min=0,max=1000"`
+	Options          []Option `json:"options,omitempty"`
+}
+
+type Option struct {
+	OptionText  string  `json:"option_text"           validate:"required,min=1"`
+	TextFormat  string  `json:"text_format,omitempty" validate:"omitempty,oneof=plain markdown latex"`
+	IsCorrect   bool    `json:"is_correct"`
+	Explanation *string `json:"explanation,omitempty"`
+}
+
+// RowError is a problem with one question of an imported file.
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("row %d: %s", e.Row, e.Message)
+	}
+	return fmt.Sprintf("row %d: %s: %s", e.Row, e.Field, e.Message)
+}
+
+// Decode parses a document in the given format. Problems with single
+// questions come back as row errors; the error is for unreadable input.
+func Decode(format string, r io.Reader) (*Document, []RowError, error) {
+	switch format {
+	case FormatJSON:
+		return DecodeJSON(r)
+	case FormatCSV:
+		return DecodeCSV(r)
+	case FormatGIFT:
+		return DecodeGIFT(r)
+	default:
+		return nil, nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// Encode writes the document in the given format.
+func Encode(format string, w io.Writer, doc *Document) error {
+	switch format {
+	case FormatJSON:
+		return EncodeJSON(w, doc)
+	case FormatCSV:
+		return EncodeCSV(w, doc)
+	case FormatGIFT:
+		return EncodeGIFT(w, doc)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// FormatFromFilename guesses the format from a file extension, returning an
+// empty string when it is not recognised.
+func FormatFromFilename(name string) string {
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return ""
+	}
+	switch strings.ToLower(name[dot+1:]) {
+	case "json":
+		return FormatJSON
+	case "csv":
+		return FormatCSV
+	case "gift", "txt":
+		return FormatGIFT
+	default:
+		return ""
+	}
+}
+
+// Validate checks every question and returns one error per problem found.
+func (d *Document) Validate() []RowError {
+	var rowErrors []RowError
+	if len(d.Questions) == 0 {
+		rowErrors = append(rowErrors, RowError{Message: "the quiz has no questions"})
+	}
+
+	for i := range d.Questions {
+		q := &d.Questions[i]
+		for field, message := range validator.ValidateStruct(q) {
+			rowErrors = append(rowErrors, RowError{Row: q.Row, Field: field, Message: message})
+		}
+		for j := range q.Options {
+			for field, message := range validator.ValidateStruct(&q.Options[j]) {
+				rowErrors = append(rowErrors, RowError{
+					Row:     q.Row,
+					Field:   fmt.Sprintf("options[%d].%s", j, field),
+					Message: message,
+				})
+			}
+		}
+		if message := q.checkOptions(); message != "" {
+			rowErrors = append(rowErrors, RowError{Row: q.Row, Field: "options", Message: message})
+		}
+	}
+	return rowErrors
+}
+
+// checkOptions validates the options against the question type.
+func (q *Question) checkOptions() string {
+	correct := 0
+	for _, opt := range q.Options {
+		if opt.IsCorrect {
+			correct++
+		}
+	}
+
+	switch q.QuestionType {
+	case typeShortAnswer, typeNumeric, typeTrueFalse:
+		if len(q.Options) > 0 {
+			return fmt.Sprintf("%s questions do not take options", q.QuestionType)
+		}
+	case typeMultipleChoice:
+		if len(q.Options) < 2 {
+			return "choice questions need at least two options"
+		}
+		if correct == 0 {
+			return "mark at least one option as correct"
+		}
+	default:
+		if len(q.Options) < 2 {
+			return "choice questions need at least two options"
+		}
+		if correct != 1 {
+			return "single choice questions need exactly one correct option"
+		}
+	}
+	return ""
+}
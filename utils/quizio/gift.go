@@ -0,0 +1,447 @@
+package quizio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GIFT support covers the question types the quiz engine grades:
+//
+//	Who wrote it? {=Pramoedya ~Chairil ~Rendra}      single choice
+//	Pick primes {~%50%2 ~%50%3 ~%-100%4}             multiple choice, partial
+//	The sky is blue. {T}                            true/false
+//	Capital of Indonesia? {=Jakarta =DKI Jakarta}    short answer
+//	Pi to two decimals? {#3.14:0.005}                numeric, also {#3.1..3.2}
+//
+// Answer feedback ("=A#because...") becomes the option explanation and general
+// feedback ("####...") the question explanation. Matching and essay questions
+// are rejected, and points, tags and difficulty are not part of the format.
+
+// DecodeGIFT reads questions separated by blank lines. The quiz title and
+// settings are not part of the file and are left empty.
+func DecodeGIFT(r io.Reader) (*Document, []RowError, error) {
+	doc := &Document{Questions: make([]Question, 0)}
+	var rowErrors []RowError
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var block []string
+	startLine, lineNo := 0, 0
+	flush := func() {
+		if len(block) == 0 {
+			return
+		}
+		q, err := parseGIFTQuestion(strings.Join(block, "\n"))
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Row: startLine, Message: err.Error()})
+		} else {
+			q.Row = startLine
+			doc.Questions = append(doc.Questions, q)
+		}
+		block = nil
+	}
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+			continue
+		case strings.HasPrefix(trimmed, "//"):
+			continue
+		case strings.HasPrefix(trimmed, "$CATEGORY:"):
+			flush()
+			continue
+		}
+
+		if len(block) == 0 {
+			startLine = lineNo
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("invalid GIFT file: %w", err)
+	}
+	flush()
+
+	return doc, rowErrors, nil
+}
+
+func parseGIFTQuestion(text string) (Question, error) {
+	text = strings.TrimSpace(text)
+
+	// Optional "::name::" title, which the quiz engine has no place for.
+	if strings.HasPrefix(text, "::") {
+		end := indexUnescaped(text[2:], "::")
+		if end < 0 {
+			return Question{}, fmt.Errorf("unterminated question title")
+		}
+		text = strings.TrimSpace(text[end+4:])
+	}
+	text = stripTextFormat(text)
+
+	open := indexUnescaped(text, "{")
+	if open < 0 {
+		return Question{}, fmt.Errorf("missing answer block {...}")
+	}
+	closing := indexUnescaped(text[open:], "}")
+	if closing < 0 {
+		return Question{}, fmt.Errorf("unterminated answer block")
+	}
+	closing += open
+
+	before := strings.TrimSpace(text[:open])
+	after := strings.TrimSpace(text[closing+1:])
+	questionText := unescapeGIFT(before)
+	if after != "" {
+		// Missing word format: "The {=sun ~moon} rises in the east."
+		questionText = strings.TrimSpace(questionText + " _____ " + unescapeGIFT(after))
+	}
+
+	body := strings.TrimSpace(text[open+1 : closing])
+	var explanation *string
+	if i := indexUnescaped(body, "####"); i >= 0 {
+		explanation = optionalString(strings.TrimSpace(unescapeGIFT(body[i+4:])))
+		body = strings.TrimSpace(body[:i])
+	}
+
+	q := Question{QuestionText: questionText, Explanation: explanation}
+	switch {
+	case body == "":
+		return Question{}, fmt.Errorf("essay questions are not supported")
+	case strings.HasPrefix(body, "#"):
+		return q, parseGIFTNumeric(&q, body[1:])
+	}
+
+	head := strings.ToUpper(strings.TrimSpace(splitUnescaped(body, '#')[0]))
+	switch head {
+	case "T", "TRUE", "F", "FALSE":
+		value := head == "T" || head == "TRUE"
+		q.QuestionType = typeTrueFalse
+		q.CorrectAnswer = &value
+		return q, nil
+	}
+
+	return q, parseGIFTAnswers(&q, body)
+}
+
+type giftAnswer struct {
+	marker   byte
+	weight   *float64
+	text     string
+	feedback *string
+}
+
+func parseGIFTAnswers(q *Question, body string) error {
+	answers, err := splitGIFTAnswers(body)
+	if err != nil {
+		return err
+	}
+	if len(answers) == 0 {
+		return fmt.Errorf("the answer block has no answers")
+	}
+
+	hasWrong := false
+	for _, a := range answers {
+		if strings.Contains(a.text, "->") {
+			return fmt.Errorf("matching questions are not supported")
+		}
+		if a.marker == '~' {
+			hasWrong = true
+		}
+	}
+
+	if !hasWrong {
+		q.QuestionType = typeShortAnswer
+		for _, a := range answers {
+			q.AcceptedAnswers = append(q.AcceptedAnswers, a.text)
+		}
+		return nil
+	}
+
+	correct, weighted := 0, false
+	for _, a := range answers {
+		isCorrect := a.marker == '='
+		if a.weight != nil {
+			weighted = true
+			isCorrect = *a.weight > 0
+		}
+		if isCorrect {
+			correct++
+		}
+		q.Options = append(q.Options, Option{
+			OptionText:  a.text,
+			IsCorrect:   isCorrect,
+			Explanation: a.feedback,
+		})
+	}
+
+	q.QuestionType = typeSingleChoice
+	if correct > 1 || weighted {
+		q.QuestionType = typeMultipleChoice
+		q.ScoringMode = scoringPartial
+	}
+	return nil
+}
+
+// splitGIFTAnswers cuts the answer block at every unescaped "=" or "~".
+func splitGIFTAnswers(body string) ([]giftAnswer, error) {
+	var answers []giftAnswer
+	var current *giftAnswer
+	var sb strings.Builder
+
+	finish := func() error {
+		if current == nil {
+			if strings.TrimSpace(sb.String()) != "" {
+				return fmt.Errorf("answers must start with = or ~")
+			}
+			return nil
+		}
+		raw := strings.TrimSpace(sb.String())
+		if strings.HasPrefix(raw, "%") {
+			end := strings.Index(raw[1:], "%")
+			if end < 0 {
+				return fmt.Errorf("unterminated answer weight")
+			}
+			weight, err := strconv.ParseFloat(raw[1:end+1], 64)
+			if err != nil {
+				return fmt.Errorf("invalid answer weight %q", raw[1:end+1])
+			}
+			current.weight = &weight
+			raw = strings.TrimSpace(raw[end+2:])
+		}
+		parts := splitUnescaped(raw, '#')
+		current.text = strings.TrimSpace(unescapeGIFT(parts[0]))
+		if len(parts) > 1 {
+			current.feedback = optionalString(strings.TrimSpace(unescapeGIFT(strings.Join(parts[1:], "#"))))
+		}
+		if current.text == "" {
+			return fmt.Errorf("empty answer")
+		}
+		answers = append(answers, *current)
+		return nil
+	}
+
+	for i := 0; i < len(body); i++ {
+		ch := body[i]
+		if ch == '\\' && i+1 < len(body) {
+			sb.WriteByte(ch)
+			sb.WriteByte(body[i+1])
+			i++
+			continue
+		}
+		if ch == '=' || ch == '~' {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			current = &giftAnswer{marker: ch}
+			sb.Reset()
+			continue
+		}
+		sb.WriteByte(ch)
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
+
+// parseGIFTNumeric reads "answer:tolerance" or "min..max". Only the first
+// answer of a numeric block is used.
+func parseGIFTNumeric(q *Question, body string) error {
+	q.QuestionType = typeNumeric
+	body = strings.TrimSpace(body)
+	if strings.HasPrefix(body, "=") {
+		body = body[1:]
+		if i := indexUnescaped(body, "="); i >= 0 {
+			body = body[:i]
+		}
+	}
+	if strings.HasPrefix(body, "%") {
+		if end := strings.Index(body[1:], "%"); end >= 0 {
+			body = body[end+2:]
+		}
+	}
+	body = strings.TrimSpace(splitUnescaped(body, '#')[0])
+
+	if lo, hi, ok := strings.Cut(body, ".."); ok {
+		min, err1 := strconv.ParseFloat(strings.TrimSpace(lo), 64)
+		max, err2 := strconv.ParseFloat(strings.TrimSpace(hi), 64)
+		if err1 != nil || err2 != nil || max < min {
+			return fmt.Errorf("invalid numeric range %q", body)
+		}
+		answer := (min + max) / 2
+		q.NumericAnswer = &answer
+		q.NumericTolerance = (max - min) / 2
+		return nil
+	}
+
+	value, tolerance, _ := strings.Cut(body, ":")
+	answer, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return fmt.Errorf("invalid numeric answer %q", body)
+	}
+	q.NumericAnswer = &answer
+	if tolerance != "" {
+		t, err := strconv.ParseFloat(strings.TrimSpace(tolerance), 64)
+		if err != nil || t < 0 {
+			return fmt.Errorf("invalid numeric tolerance %q", tolerance)
+		}
+		q.NumericTolerance = t
+	}
+	return nil
+}
+
+// EncodeGIFT writes every question as a GIFT block.
+func EncodeGIFT(w io.Writer, doc *Document) error {
+	bw := bufio.NewWriter(w)
+	if doc.Title != "" {
+		fmt.Fprintf(bw, "// %s\n\n", strings.ReplaceAll(doc.Title, "\n", " "))
+	}
+
+	for i, q := range doc.Questions {
+		fmt.Fprintf(bw, "::Q%d:: %s {\n", i+1, escapeGIFT(q.QuestionText))
+
+		switch q.QuestionType {
+		case typeTrueFalse:
+			if q.CorrectAnswer != nil && *q.CorrectAnswer {
+				bw.WriteString("TRUE\n")
+			} else {
+				bw.WriteString("FALSE\n")
+			}
+		case typeShortAnswer:
+			for _, answer := range q.AcceptedAnswers {
+				fmt.Fprintf(bw, "=%s\n", escapeGIFT(answer))
+			}
+		case typeNumeric:
+			answer := 0.0
+			if q.NumericAnswer != nil {
+				answer = *q.NumericAnswer
+			}
+			fmt.Fprintf(bw, "#%s", strconv.FormatFloat(answer, 'f', -1, 64))
+			if q.NumericTolerance > 0 {
+				fmt.Fprintf(bw, ":%s", strconv.FormatFloat(q.NumericTolerance, 'f', -1, 64))
+			}
+			bw.WriteString("\n")
+		case typeMultipleChoice:
+			correct := 0
+			for _, opt := range q.Options {
+				if opt.IsCorrect {
+					correct++
+				}
+			}
+			weight := 100.0
+			if correct > 0 {
+				weight = math.Round(100/float64(correct)*100000) / 100000
+			}
+			for _, opt := range q.Options {
+				sign := "-"
+				if opt.IsCorrect {
+					sign = ""
+				}
+				fmt.Fprintf(bw, "~%%%s%s%%%s%s\n", sign, strconv.FormatFloat(weight, 'f', -1, 64),
+					escapeGIFT(opt.OptionText), giftFeedback(opt.Explanation))
+			}
+		default:
+			for _, opt := range q.Options {
+				marker := "~"
+				if opt.IsCorrect {
+					marker = "="
+				}
+				fmt.Fprintf(bw, "%s%s%s\n", marker, escapeGIFT(opt.OptionText), giftFeedback(opt.Explanation))
+			}
+		}
+
+		if q.Explanation != nil && *q.Explanation != "" {
+			fmt.Fprintf(bw, "####%s\n", escapeGIFT(*q.Explanation))
+		}
+		bw.WriteString("}\n\n")
+	}
+
+	return bw.Flush()
+}
+
+func giftFeedback(explanation *string) string {
+	if explanation == nil || *explanation == "" {
+		return ""
+	}
+	return "#" + escapeGIFT(*explanation)
+}
+
+var giftEscaper = strings.NewReplacer(
+	`\`, `\\`, `~`, `\~`, `=`, `\=`, `#`, `\#`, `{`, `\{`, `}`, `\}`, `:`, `\:`, "\n", `\n`,
+)
+
+func escapeGIFT(s string) string {
+	return giftEscaper.Replace(s)
+}
+
+func unescapeGIFT(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' {
+				sb.WriteByte('\n')
+			} else {
+				sb.WriteByte(s[i])
+			}
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// indexUnescaped finds sep in s, skipping backslash-escaped characters.
+func indexUnescaped(s, sep string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitUnescaped splits s at every unescaped sep, keeping escapes intact.
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	last := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == sep {
+			parts = append(parts, s[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, s[last:])
+}
+
+// stripTextFormat drops a leading [html], [markdown], [plain] or [moodle]
+// marker; the text is stored as written.
+func stripTextFormat(s string) string {
+	for _, marker := range []string{"[html]", "[markdown]", "[plain]", "[moodle]"} {
+		if strings.HasPrefix(strings.ToLower(s), marker) {
+			return strings.TrimSpace(s[len(marker):])
+		}
+	}
+	return s
+}
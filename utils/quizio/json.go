@@ -0,0 +1,25 @@
+package quizio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DecodeJSON reads a document in the admin API shape.
+func DecodeJSON(r io.Reader) (*Document, []RowError, error) {
+	doc := new(Document)
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	for i := range doc.Questions {
+		doc.Questions[i].Row = i + 1
+	}
+	return doc, nil, nil
+}
+
+func EncodeJSON(w io.Writer, doc *Document) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
@@ -0,0 +1,235 @@
+package quizio_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/studio-senkou/lentera-cendekia-be/utils/quizio"
+)
+
+func TestDecodeCSV(t *testing.T) {
+	input := strings.Join([]string{
+		"question_text,option_a,option_b,option_c,correct,points,penalty_points,tags,question_type",
+		"Ibu kota Indonesia?,Bandung,Jakarta,Surabaya,B,4,1,geografi|umum,",
+		"Bilangan prima?,2,4,3,\"A,C\",,,,",
+		"Air mendidih pada 100 C.,,,,benar,,,,true_false",
+		"Sebutkan warna langit,,,,biru|Biru Muda,,,,",
+		"Akar dari 16?,,,,4,,,,numeric",
+		"",
+		"Opsi kosong?,Satu,,Tiga,B,,,,",
+	}, "\n")
+
+	doc, rowErrors, err := DecodeCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(doc.Questions) != 6 {
+		t.Fatalf("expected 6 questions, got %d", len(doc.Questions))
+	}
+
+	single := doc.Questions[0]
+	if single.QuestionType != "single_choice" || !single.Options[1].IsCorrect || single.Points != 4 || single.PenaltyPoints != 1 {
+		t.Errorf("unexpected single choice question: %+v", single)
+	}
+	if len(single.Tags) != 2 || single.Tags[0] != "geografi" {
+		t.Errorf("unexpected tags: %v", single.Tags)
+	}
+
+	multiple := doc.Questions[1]
+	if multiple.QuestionType != "multiple_choice" || !multiple.Options[0].IsCorrect || multiple.Options[1].IsCorrect || !multiple.Options[2].IsCorrect {
+		t.Errorf("unexpected multiple choice question: %+v", multiple)
+	}
+
+	trueFalse := doc.Questions[2]
+	if trueFalse.CorrectAnswer == nil || !*trueFalse.CorrectAnswer {
+		t.Errorf("expected true/false answer true, got %+v", trueFalse)
+	}
+
+	short := doc.Questions[3]
+	if short.QuestionType != "short_answer" || len(short.AcceptedAnswers) != 2 {
+		t.Errorf("unexpected short answer question: %+v", short)
+	}
+
+	numeric := doc.Questions[4]
+	if numeric.NumericAnswer == nil || *numeric.NumericAnswer != 4 {
+		t.Errorf("unexpected numeric question: %+v", numeric)
+	}
+
+	if len(rowErrors) != 1 || rowErrors[0].Row != 8 || rowErrors[0].Field != "correct" {
+		t.Errorf("expected one error on row 8 for the empty option, got %v", rowErrors)
+	}
+}
+
+func TestDecodeCSVRequiresQuestionColumn(t *testing.T) {
+	if _, _, err := DecodeCSV(strings.NewReader("text,correct\nx,A\n")); err == nil {
+		t.Fatal("expected an error for a header without question_text")
+	}
+}
+
+func TestDecodeGIFT(t *testing.T) {
+	input := `// Tryout UTBK
+$CATEGORY: tryout
+
+::Q1:: Siapa penulis Bumi Manusia? {
+=Pramoedya Ananta Toer#Tetralogi Buru
+~Chairil Anwar
+~W.S. Rendra
+####Novel pertama Tetralogi Buru.
+}
+
+::Q2:: Pilih bilangan prima {~%50%2 ~%50%3 ~%-100%4}
+
+Matahari terbit dari timur. {T}
+
+Ibu kota Indonesia? {=Jakarta =DKI Jakarta}
+
+Nilai pi dua desimal? {#3.14:0.005}
+
+Rentang {#1..3}
+
+Tulis esai tentang banjir. {}
+
+Pasangkan {=a -> b =c -> d}
+
+Harga 5 \= lima \{benar\}? {=ya ~tidak}
+`
+	doc, rowErrors, err := DecodeGIFT(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(doc.Questions) != 7 {
+		t.Fatalf("expected 7 questions, got %d: %+v", len(doc.Questions), doc.Questions)
+	}
+
+	q1 := doc.Questions[0]
+	if q1.QuestionType != "single_choice" || len(q1.Options) != 3 || !q1.Options[0].IsCorrect {
+		t.Errorf("unexpected single choice question: %+v", q1)
+	}
+	if q1.Options[0].Explanation == nil || *q1.Options[0].Explanation != "Tetralogi Buru" {
+		t.Errorf("expected option feedback, got %v", q1.Options[0].Explanation)
+	}
+	if q1.Explanation == nil || *q1.Explanation != "Novel pertama Tetralogi Buru." {
+		t.Errorf("expected general feedback, got %v", q1.Explanation)
+	}
+	if q1.Row != 4 {
+		t.Errorf("expected Q1 on line 4, got %d", q1.Row)
+	}
+
+	q2 := doc.Questions[1]
+	if q2.QuestionType != "multiple_choice" || q2.ScoringMode != "partial" || !q2.Options[1].IsCorrect || q2.Options[2].IsCorrect {
+		t.Errorf("unexpected multiple choice question: %+v", q2)
+	}
+
+	if q := doc.Questions[2]; q.QuestionType != "true_false" || q.CorrectAnswer == nil || !*q.CorrectAnswer {
+		t.Errorf("unexpected true/false question: %+v", q)
+	}
+	if q := doc.Questions[3]; q.QuestionType != "short_answer" || len(q.AcceptedAnswers) != 2 {
+		t.Errorf("unexpected short answer question: %+v", q)
+	}
+	if q := doc.Questions[4]; q.NumericAnswer == nil || *q.NumericAnswer != 3.14 || q.NumericTolerance != 0.005 {
+		t.Errorf("unexpected numeric question: %+v", q)
+	}
+	if q := doc.Questions[5]; q.NumericAnswer == nil || *q.NumericAnswer != 2 || q.NumericTolerance != 1 {
+		t.Errorf("unexpected numeric range question: %+v", q)
+	}
+	if q := doc.Questions[6]; q.QuestionText != "Harga 5 = lima {benar}?" {
+		t.Errorf("expected escaped characters to be restored, got %q", q.QuestionText)
+	}
+
+	if len(rowErrors) != 2 {
+		t.Fatalf("expected errors for the essay and matching questions, got %v", rowErrors)
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	explanation := "Karena 2 + 2 = 4 {pasti}"
+	answer := 4.0
+	correct := false
+	doc := &Document{
+		Title: "Tryout",
+		Questions: []Question{
+			{QuestionText: "2 + 2?", QuestionType: "single_choice", Explanation: &explanation, Options: []Option{
+				{OptionText: "3"}, {OptionText: "4", IsCorrect: true},
+			}},
+			{QuestionText: "Prima?", QuestionType: "multiple_choice", ScoringMode: "partial", Options: []Option{
+				{OptionText: "2", IsCorrect: true}, {OptionText: "3", IsCorrect: true}, {OptionText: "4"},
+			}},
+			{QuestionText: "Bumi datar.", QuestionType: "true_false", CorrectAnswer: &correct},
+			{QuestionText: "Warna langit?", QuestionType: "short_answer", AcceptedAnswers: []string{"biru", "biru muda"}},
+			{QuestionText: "Akar 16?", QuestionType: "numeric", NumericAnswer: &answer, NumericTolerance: 0.5},
+		},
+	}
+
+	for _, format := range []string{FormatJSON, FormatCSV, FormatGIFT} {
+		var buf bytes.Buffer
+		if err := Encode(format, &buf, doc); err != nil {
+			t.Fatalf("%s: encode failed: %v", format, err)
+		}
+		decoded, rowErrors, err := Decode(format, &buf)
+		if err != nil || len(rowErrors) > 0 {
+			t.Fatalf("%s: decode failed: %v %v", format, err, rowErrors)
+		}
+		if len(decoded.Questions) != len(doc.Questions) {
+			t.Fatalf("%s: expected %d questions, got %d", format, len(doc.Questions), len(decoded.Questions))
+		}
+		for i, q := range decoded.Questions {
+			want := doc.Questions[i]
+			if q.QuestionText != want.QuestionText || q.QuestionType != want.QuestionType {
+				t.Errorf("%s: question %d: got %q (%s), want %q (%s)", format, i, q.QuestionText, q.QuestionType, want.QuestionText, want.QuestionType)
+			}
+			for j, opt := range q.Options {
+				if opt.OptionText != want.Options[j].OptionText || opt.IsCorrect != want.Options[j].IsCorrect {
+					t.Errorf("%s: question %d option %d: got %+v, want %+v", format, i, j, opt, want.Options[j])
+				}
+			}
+		}
+		if got := decoded.Questions[0].Explanation; got == nil || *got != explanation {
+			t.Errorf("%s: explanation not preserved: %v", format, got)
+		}
+		if got := decoded.Questions[4]; got.NumericAnswer == nil || *got.NumericAnswer != 4 || got.NumericTolerance != 0.5 {
+			t.Errorf("%s: numeric answer not preserved: %+v", format, got)
+		}
+		if problems := decoded.Validate(); len(problems) > 0 {
+			t.Errorf("%s: round-tripped document is invalid: %v", format, problems)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	doc := &Document{Questions: []Question{
+		{Row: 2, QuestionText: "Pilih satu", Options: []Option{{OptionText: "A", IsCorrect: true}, {OptionText: "B", IsCorrect: true}}},
+		{Row: 3, QuestionText: "Isian", QuestionType: "short_answer"},
+		{Row: 4, QuestionText: "Hanya satu opsi", QuestionType: "multiple_choice", Options: []Option{{OptionText: "A", IsCorrect: true}}},
+	}}
+
+	rows := make(map[int]bool)
+	for _, problem := range doc.Validate() {
+		rows[problem.Row] = true
+	}
+	for _, row := range []int{2, 3, 4} {
+		if !rows[row] {
+			t.Errorf("expected a validation error on row %d", row)
+		}
+	}
+
+	if problems := (&Document{}).Validate(); len(problems) == 0 {
+		t.Error("expected an error for a quiz without questions")
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	cases := map[string]string{
+		"tryout.JSON": FormatJSON,
+		"soal.csv":    FormatCSV,
+		"moodle.gift": FormatGIFT,
+		"export.txt":  FormatGIFT,
+		"soal.xlsx":   "",
+		"tanpa-ext":   "",
+	}
+	for name, want := range cases {
+		if got := FormatFromFilename(name); got != want {
+			t.Errorf("FormatFromFilename(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -43,6 +43,19 @@ func IsValidImageExtension(filename string) bool {
 	return false
 }
 
+func IsValidAudioExtension(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	validExts := []string{".mp3", ".m4a", ".ogg", ".wav", ".webm"}
+
+	for _, validExt := range validExts {
+		if ext == validExt {
+			return true
+		}
+	}
+
+	return false
+}
+
 func GetContentType(filename string) string {
 	ext := strings.ToLower(filepath.Ext(filename))
 	switch ext {
@@ -54,6 +67,18 @@ func GetContentType(filename string) string {
 		return "image/gif"
 	case ".webp":
 		return "image/webp"
+	case ".mp3":
+		return "audio/mpeg"
+	case ".m4a":
+		return "audio/mp4"
+	case ".ogg":
+		return "audio/ogg"
+	case ".wav":
+		return "audio/wav"
+	case ".webm":
+		return "audio/webm"
+	case ".pdf":
+		return "application/pdf"
 	default:
 		return "application/octet-stream"
 	}
@@ -66,7 +91,7 @@ func UploadFileToStorage(file *multipart.FileHeader, storagePath, prefix string,
 	}
 
 	originalFilename := file.Filename
-	if !IsValidImageExtension(originalFilename) {
+	if !IsValidImageExtension(originalFilename) && !IsValidAudioExtension(originalFilename) {
 		return "", fmt.Errorf("invalid file type: %s", originalFilename)
 	}
 
@@ -83,6 +108,22 @@ func UploadFileToStorage(file *multipart.FileHeader, storagePath, prefix string,
 	return uploadedPath, nil
 }
 
+// UploadBytesToStorage stores generated content under an unguessable name
+// derived from filename and returns its storage path.
+func UploadBytesToStorage(data []byte, filename, storagePath, prefix string) (string, error) {
+	encryptedFilename := GenerateEncryptedFilename(filename, prefix)
+	fullPath := filepath.Join(storagePath, encryptedFilename)
+
+	ctx := context.Background()
+	uploader := NewUploadService()
+	uploadedPath, err := uploader.UploadBytes(ctx, data, fullPath, GetContentType(filename))
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return uploadedPath, nil
+}
+
 func RemoveFileFromStorage(path string) error {
 	ctx := context.Background()
 	uploader := NewUploadService()
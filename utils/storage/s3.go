@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"mime/multipart"
@@ -107,6 +108,28 @@ func (s *UploadService) UploadFile(ctx context.Context, file *multipart.FileHead
 	return key, nil
 }
 
+// UploadBytes stores generated content, such as a rendered PDF, under the
+// given key.
+func (s *UploadService) UploadBytes(ctx context.Context, data []byte, key, contentType string) (string, error) {
+	_, err := s.storage.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(int64(len(data))),
+		ACL:           types.ObjectCannedACLPublicRead,
+		CacheControl:  aws.String("max-age=31536000, public"),
+		Metadata: map[string]string{
+			"uploaded-by": "Lentera Cendekia API",
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
 func (s *UploadService) RemoveFile(ctx context.Context, path string) error {
 	_, err := s.storage.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
@@ -0,0 +1,276 @@
+package validator
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// MaxPasswordLength is the most bcrypt can hash; longer passwords would be
+// silently truncated.
+const MaxPasswordLength = 72
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// PasswordPolicy decides which new passwords are accepted. It is applied to
+// fields tagged `validate:"password"`; reuse of earlier passwords needs the
+// database and is checked by the caller against HistorySize.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// HistorySize is how many of the user's latest passwords, the current one
+	// included, may not be used again. Zero allows reuse.
+	HistorySize int
+
+	// breached holds upper case SHA-1 hashes of known breached passwords.
+	breached map[string]struct{}
+	// breachedRangeDir holds k-anonymity range files as served by Have I Been
+	// Pwned: <first 5 hex of the SHA-1>.txt with "<remaining 35 hex>:<count>"
+	// lines.
+	breachedRangeDir string
+}
+
+// PasswordPolicyError lists every rule a password broke.
+type PasswordPolicyError struct {
+	Reasons []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password " + strings.Join(e.Reasons, ", ")
+}
+
+var (
+	passwordPolicy        *PasswordPolicy
+	defaultPasswordPolicy = sync.OnceValue(NewPasswordPolicy)
+)
+
+// InitPasswordPolicy loads the policy from the environment. The server calls
+// it at startup, so a breached password file that cannot be read stops it
+// before the first request instead of failing a validation.
+func InitPasswordPolicy() error {
+	policy, err := LoadPasswordPolicy()
+	if err != nil {
+		return err
+	}
+	passwordPolicy = policy
+	return nil
+}
+
+// GetPasswordPolicy returns the policy loaded by InitPasswordPolicy, or the
+// default policy when it was not loaded, as in tests and tools.
+func GetPasswordPolicy() *PasswordPolicy {
+	if passwordPolicy != nil {
+		return passwordPolicy
+	}
+	return defaultPasswordPolicy()
+}
+
+// LoadPasswordPolicy reads the policy from PASSWORD_* variables:
+//
+//   - PASSWORD_MIN_LENGTH, 8 unless set, at most MaxPasswordLength.
+//   - PASSWORD_REQUIRE_UPPER, _LOWER and _DIGIT, true unless set, and
+//     PASSWORD_REQUIRE_SYMBOL, false unless set.
+//   - PASSWORD_HISTORY, how many latest passwords cannot be reused, 5 unless set.
+//   - PASSWORD_BREACHED_FILE, a list of breached passwords, one per line,
+//     either as plain text or as SHA-1 hex with an optional ":count".
+//   - PASSWORD_BREACHED_RANGE_DIR, a directory of k-anonymity range files.
+//
+// A short list of the most common passwords is always rejected.
+func LoadPasswordPolicy() (*PasswordPolicy, error) {
+	policy := NewPasswordPolicy()
+	policy.MinLength = min(envInt("PASSWORD_MIN_LENGTH", policy.MinLength), MaxPasswordLength)
+	policy.RequireUpper = envBool("PASSWORD_REQUIRE_UPPER", policy.RequireUpper)
+	policy.RequireLower = envBool("PASSWORD_REQUIRE_LOWER", policy.RequireLower)
+	policy.RequireDigit = envBool("PASSWORD_REQUIRE_DIGIT", policy.RequireDigit)
+	policy.RequireSymbol = envBool("PASSWORD_REQUIRE_SYMBOL", policy.RequireSymbol)
+	policy.HistorySize = envInt("PASSWORD_HISTORY", policy.HistorySize)
+
+	if path := os.Getenv("PASSWORD_BREACHED_FILE"); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("PASSWORD_BREACHED_FILE: %w", err)
+		}
+		defer file.Close()
+
+		if err := policy.AddBreachedPasswords(bufio.NewScanner(file)); err != nil {
+			return nil, fmt.Errorf("PASSWORD_BREACHED_FILE: %w", err)
+		}
+	}
+
+	if dir := os.Getenv("PASSWORD_BREACHED_RANGE_DIR"); dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("PASSWORD_BREACHED_RANGE_DIR: %s is not a directory", dir)
+		}
+		policy.breachedRangeDir = dir
+	}
+
+	return policy, nil
+}
+
+// NewPasswordPolicy returns the default policy: at least 8 characters with
+// upper and lower case letters and a digit, no reuse of the last 5
+// passwords, and none of the most common passwords.
+func NewPasswordPolicy() *PasswordPolicy {
+	policy := &PasswordPolicy{
+		MinLength:    8,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+		HistorySize:  5,
+		breached:     make(map[string]struct{}),
+	}
+	_ = policy.AddBreachedPasswords(bufio.NewScanner(strings.NewReader(commonPasswords)))
+	return policy
+}
+
+// AddBreachedPasswords adds every line of the scanner to the breached list.
+// Lines are plain passwords or SHA-1 hex hashes with an optional ":count".
+func (p *PasswordPolicy) AddBreachedPasswords(scanner *bufio.Scanner) error {
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if hash, _, _ := strings.Cut(line, ":"); isSHA1Hex(hash) {
+			p.breached[strings.ToUpper(hash)] = struct{}{}
+			continue
+		}
+		p.breached[sha1Hex(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Check returns a *PasswordPolicyError naming every rule the password breaks,
+// or nil when it is acceptable.
+func (p *PasswordPolicy) Check(password string) error {
+	var reasons []string
+
+	if length := len([]rune(password)); length < p.MinLength {
+		reasons = append(reasons, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+	if len(password) > MaxPasswordLength {
+		reasons = append(reasons, fmt.Sprintf("must be at most %d bytes long", MaxPasswordLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		reasons = append(reasons, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		reasons = append(reasons, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		reasons = append(reasons, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		reasons = append(reasons, "must contain a symbol")
+	}
+
+	if p.IsBreached(password) {
+		reasons = append(reasons, "has appeared in a data breach, please choose another one")
+	}
+
+	if len(reasons) > 0 {
+		return &PasswordPolicyError{Reasons: reasons}
+	}
+	return nil
+}
+
+// IsBreached reports whether the password is on the breached list or in the
+// range file of its hash prefix.
+func (p *PasswordPolicy) IsBreached(password string) bool {
+	hash := sha1Hex(password)
+	if _, ok := p.breached[hash]; ok {
+		return true
+	}
+	if p.breachedRangeDir == "" {
+		return false
+	}
+
+	file, err := os.Open(filepath.Join(p.breachedRangeDir, hash[:5]+".txt"))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		suffix, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(suffix, hash[5:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordReusedMessage is the validation message for a password that is one
+// of the user's latest ones.
+func PasswordReusedMessage(field string, history int) string {
+	if history <= 1 {
+		return fmt.Sprintf("The %s field must be different from your current password", field)
+	}
+	return fmt.Sprintf("The %s field must be different from your last %d passwords", field, history)
+}
+
+func passwordMessage(field string, value any) string {
+	password, _ := value.(string)
+	err, ok := GetPasswordPolicy().Check(password).(*PasswordPolicyError)
+	if !ok {
+		return fmt.Sprintf("The %s field is invalid", field)
+	}
+	return fmt.Sprintf("The %s field %s", field, strings.Join(err.Reasons, ", "))
+}
+
+func sha1Hex(value string) string {
+	sum := sha1.Sum([]byte(value))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isSHA1Hex(value string) bool {
+	if len(value) != sha1.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+func envBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+package validator_test
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/studio-senkou/lentera-cendekia-be/utils/validator"
+)
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	policy := NewPasswordPolicy()
+	policy.RequireSymbol = true
+
+	tests := []struct {
+		name     string
+		password string
+		reasons  []string
+	}{
+		{
+			name:     "valid password",
+			password: "Lentera#2026",
+		},
+		{
+			name:     "too short",
+			password: "Ab1#",
+			reasons:  []string{"must be at least 8 characters long"},
+		},
+		{
+			name:     "missing character classes",
+			password: "lowercaseonly",
+			reasons:  []string{"must contain an uppercase letter", "must contain a digit", "must contain a symbol"},
+		},
+		{
+			name:     "too long for bcrypt",
+			password: "Aa1#" + strings.Repeat("x", MaxPasswordLength),
+			reasons:  []string{"must be at most 72 bytes long"},
+		},
+		{
+			name:     "common password",
+			password: "Admin@123",
+			reasons:  []string{"has appeared in a data breach, please choose another one"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.password)
+			if tt.reasons == nil {
+				if err != nil {
+					t.Fatalf("expected the password to pass, got %v", err)
+				}
+				return
+			}
+
+			policyErr, ok := err.(*PasswordPolicyError)
+			if !ok {
+				t.Fatalf("expected a *PasswordPolicyError, got %v", err)
+			}
+			if !reflect.DeepEqual(policyErr.Reasons, tt.reasons) {
+				t.Fatalf("expected reasons %v, got %v", tt.reasons, policyErr.Reasons)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyBreachedList(t *testing.T) {
+	policy := NewPasswordPolicy()
+
+	// "Lentera#2026" as plain text, "Cendekia#2026" as an uppercase SHA-1 hash
+	// with a count, the way breach corpora ship them.
+	list := "# comment\nLentera#2026\n81E4219DA3C4F11C1B3247D6441955A6D6ED0CEC:12\n"
+	if err := policy.AddBreachedPasswords(bufio.NewScanner(strings.NewReader(list))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !policy.IsBreached("Lentera#2026") {
+		t.Fatal("expected the plain text entry to be breached")
+	}
+	if !policy.IsBreached("Cendekia#2026") {
+		t.Fatal("expected the hashed entry to be breached")
+	}
+	if policy.IsBreached("Pelita#2026") {
+		t.Fatal("expected an unlisted password to pass")
+	}
+}
+
+func TestPasswordPolicyBreachedRangeDir(t *testing.T) {
+	// Range files are named after the first 5 characters of the SHA-1 and list
+	// the remaining 35.
+	sum := sha1.Sum([]byte("Lentera#2026"))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	dir := t.TempDir()
+	content := "0018A45C4D1DEF81644B54AB7F969B88D65:1\n" + hash[5:] + ":42\n"
+	if err := os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(content), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("PASSWORD_BREACHED_RANGE_DIR", dir)
+	t.Setenv("PASSWORD_MIN_LENGTH", "10")
+	policy, err := LoadPasswordPolicy()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if policy.MinLength != 10 {
+		t.Fatalf("expected a minimum length of 10, got %d", policy.MinLength)
+	}
+	if !policy.IsBreached("Lentera#2026") {
+		t.Fatal("expected the password to be found in its range file")
+	}
+	if policy.IsBreached("Pelita#2026") {
+		t.Fatal("expected an unlisted password to pass")
+	}
+}
+
+func TestInitPasswordPolicyUnreadableFile(t *testing.T) {
+	t.Setenv("PASSWORD_BREACHED_FILE", filepath.Join(t.TempDir(), "missing.txt"))
+	if err := InitPasswordPolicy(); err == nil {
+		t.Fatal("expected an error for a missing breached password file")
+	}
+
+	// The validator keeps using the default policy instead of panicking.
+	if err := GetPasswordPolicy().Check("Pelita#2026"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateStructPasswordTag(t *testing.T) {
+	type ChangePasswordDTO struct {
+		NewPassword     string `json:"new_password" validate:"required,password"`
+		ConfirmPassword string `json:"confirm_password" validate:"required,eqfield=NewPassword"`
+	}
+
+	errors := ValidateStruct(&ChangePasswordDTO{NewPassword: "password", ConfirmPassword: "different"})
+	expected := map[string]string{
+		"new_password": "The new_password field must contain an uppercase letter, must contain a digit, " +
+			"has appeared in a data breach, please choose another one",
+		"confirm_password": "The confirm_password field must match the new_password field",
+	}
+	if !reflect.DeepEqual(errors, expected) {
+		t.Fatalf("expected %v, got %v", expected, errors)
+	}
+
+	if errors := ValidateStruct(&ChangePasswordDTO{NewPassword: "Pelita#2026", ConfirmPassword: "Pelita#2026"}); len(errors) != 0 {
+		t.Fatalf("expected no errors, got %v", errors)
+	}
+}
+
+func TestPasswordReusedMessage(t *testing.T) {
+	if got := PasswordReusedMessage("new_password", 5); got != "The new_password field must be different from your last 5 passwords" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	if got := PasswordReusedMessage("new_password", 1); got != "The new_password field must be different from your current password" {
+		t.Fatalf("unexpected message %q", got)
+	}
+}
@@ -13,6 +13,9 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	_ = validate.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return GetPasswordPolicy().Check(fl.Field().String()) == nil
+	})
 }
 
 func ValidateStruct(data any) map[string]string {
@@ -23,6 +26,12 @@ func ValidateStruct(data any) map[string]string {
 		for _, err := range err.(validator.ValidationErrors) {
 			field := getFieldName(data, err.Field())
 			message := getErrorMessage(field, err.Tag(), err.Param())
+			switch err.Tag() {
+			case "password":
+				message = passwordMessage(field, err.Value())
+			case "eqfield":
+				message = fmt.Sprintf("The %s field must match the %s field", field, getFieldName(data, err.Param()))
+			}
 			errors[field] = message
 		}
 	}
@@ -77,6 +86,10 @@ func getErrorMessage(field, tag, param string) string {
 	switch tag {
 	case "required":
 		return fmt.Sprintf("The %s field is required", field)
+	case "required_if":
+		return fmt.Sprintf("The %s field is required when %s", field, strings.Replace(param, " ", " is ", 1))
+	case "required_without_all":
+		return fmt.Sprintf("The %s field is required when none of %s are present", field, param)
 	case "email":
 		return fmt.Sprintf("The %s field must be a valid email address", field)
 	case "min":
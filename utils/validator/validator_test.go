@@ -87,3 +87,20 @@ func TestValidateStruct(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateStructRequiredIf(t *testing.T) {
+	type QuestionDTO struct {
+		QuestionType  string   `json:"question_type" validate:"required"`
+		NumericAnswer *float64 `json:"numeric_answer" validate:"required_if=QuestionType numeric"`
+	}
+
+	errs := ValidateStruct(QuestionDTO{QuestionType: "numeric"})
+	expected := "The numeric_answer field is required when QuestionType is numeric"
+	if errs["numeric_answer"] != expected {
+		t.Errorf("expected %q, got %q", expected, errs["numeric_answer"])
+	}
+
+	if errs := ValidateStruct(QuestionDTO{QuestionType: "single_choice"}); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}